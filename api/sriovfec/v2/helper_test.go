@@ -131,4 +131,59 @@ var _ = Describe("helperFunctionsTest", func() {
 			})
 		})
 	})
+
+	var _ = Describe("DrainPolicy", func() {
+		It("should pick the least disruptive policy", func() {
+			Expect(DrainPolicyFull.LeastDisruptive(DrainPolicyAcceleratorConsumersOnly)).To(Equal(DrainPolicyAcceleratorConsumersOnly))
+			Expect(DrainPolicyAcceleratorConsumersOnly.LeastDisruptive(DrainPolicyNever)).To(Equal(DrainPolicyNever))
+			Expect(DrainPolicyNever.LeastDisruptive(DrainPolicyFull)).To(Equal(DrainPolicyNever))
+		})
+
+		It("should derive policy from drainSkip when not set", func() {
+			drainSkip := false
+			Expect(SriovFecClusterConfigSpec{}.EffectiveDrainPolicy()).To(Equal(DrainPolicyNever))
+			Expect(SriovFecClusterConfigSpec{DrainSkip: &drainSkip}.EffectiveDrainPolicy()).To(Equal(DrainPolicyFull))
+			Expect(SriovFecNodeConfigSpec{DrainSkip: true}.EffectiveDrainPolicy()).To(Equal(DrainPolicyNever))
+			Expect(SriovFecNodeConfigSpec{DrainSkip: false}.EffectiveDrainPolicy()).To(Equal(DrainPolicyFull))
+		})
+
+		It("should prefer explicitly requested policy over drainSkip", func() {
+			spec := SriovFecNodeConfigSpec{DrainSkip: true, DrainPolicy: DrainPolicyAcceleratorConsumersOnly}
+			Expect(spec.EffectiveDrainPolicy()).To(Equal(DrainPolicyAcceleratorConsumersOnly))
+		})
+	})
+
+	var _ = Describe("ApprovalPolicy", func() {
+		It("should pick Manual if any policy requires it", func() {
+			var unset ApprovalPolicy
+			Expect(unset.MostRestrictive(unset)).To(BeEmpty())
+			Expect(unset.MostRestrictive(ApprovalPolicyAutomatic)).To(Equal(ApprovalPolicyAutomatic))
+			Expect(ApprovalPolicyAutomatic.MostRestrictive(ApprovalPolicyManual)).To(Equal(ApprovalPolicyManual))
+			Expect(ApprovalPolicyManual.MostRestrictive(unset)).To(Equal(ApprovalPolicyManual))
+		})
+
+		It("should require approval only for Manual policy", func() {
+			Expect(SriovFecNodeConfigSpec{}.RequiresApproval()).To(BeFalse())
+			Expect(SriovFecNodeConfigSpec{ApprovalPolicy: ApprovalPolicyManual}.RequiresApproval()).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("VFGroups", func() {
+	pf := PhysicalFunctionConfigExt{VFDriver: "vfio-pci", VFAmount: 16, VFGroups: []VFGroup{
+		{First: 0, Last: 7, ResourceName: "intel_fec_du_a"},
+		{First: 8, Last: 11, ResourceName: "intel_fec_du_b", VFDriver: "igb_uio"},
+	}}
+
+	It("should return the group of the VF", func() {
+		Expect(pf.VFGroupOf(7).ResourceName).To(Equal("intel_fec_du_a"))
+		Expect(pf.VFGroupOf(8).ResourceName).To(Equal("intel_fec_du_b"))
+		Expect(pf.VFGroupOf(12)).To(BeNil())
+	})
+
+	It("should return the driver of the group or the PF", func() {
+		Expect(pf.VFDriverOf(0)).To(Equal("vfio-pci"))
+		Expect(pf.VFDriverOf(11)).To(Equal("igb_uio"))
+		Expect(pf.VFDriverOf(15)).To(Equal("vfio-pci"))
+	})
 })
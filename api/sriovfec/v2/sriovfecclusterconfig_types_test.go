@@ -8,6 +8,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -165,3 +167,114 @@ var _ = Describe("BBDevConfig Validation", func() {
 		})
 	})
 })
+
+var _ = Describe("DrainSettings Validation", func() {
+	Context("when lease timings are consistent", func() {
+		It("should not return an error", func() {
+			spec := SriovFecClusterConfigSpec{DrainSettings: &DrainSettings{
+				Lease: &LeaseSettings{DurationSeconds: 137, RenewDeadlineSeconds: 107, RetryPeriodSeconds: 26},
+			}}
+			Expect(drainSettingsValidator(spec)).To(BeEmpty())
+		})
+	})
+
+	Context("when lease duration is not greater than renew deadline", func() {
+		It("should return an error", func() {
+			spec := SriovFecClusterConfigSpec{DrainSettings: &DrainSettings{
+				Lease: &LeaseSettings{DurationSeconds: 60, RenewDeadlineSeconds: 60, RetryPeriodSeconds: 10},
+			}}
+			errs := drainSettingsValidator(spec)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.drainSettings.lease.durationSeconds"))
+		})
+	})
+
+	Context("when excluded pod selector is invalid", func() {
+		It("should return an error", func() {
+			spec := SriovFecClusterConfigSpec{DrainSettings: &DrainSettings{
+				ExcludedPodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: "Unknown"},
+				}},
+			}}
+			errs := drainSettingsValidator(spec)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.drainSettings.excludedPodSelector"))
+		})
+	})
+})
+
+var _ = Describe("ReconfigurationHooks Validation", func() {
+	Context("when hook defines both url and jobTemplate", func() {
+		It("should return an error", func() {
+			spec := SriovFecClusterConfigSpec{Hooks: &ReconfigurationHooks{
+				PreReconfiguration: &ReconfigurationHook{URL: "http://du:8080/quiesce", JobTemplate: &batchv1.JobTemplateSpec{}},
+			}}
+			errs := hooksValidator(spec)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.hooks.preReconfiguration"))
+		})
+	})
+
+	Context("when job template has no containers", func() {
+		It("should return an error", func() {
+			spec := SriovFecClusterConfigSpec{Hooks: &ReconfigurationHooks{
+				PostReconfiguration: &ReconfigurationHook{JobTemplate: &batchv1.JobTemplateSpec{}},
+			}}
+			errs := hooksValidator(spec)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+		})
+	})
+
+	Context("when hooks are valid", func() {
+		It("should not return an error", func() {
+			spec := SriovFecClusterConfigSpec{Hooks: &ReconfigurationHooks{
+				PreReconfiguration:  &ReconfigurationHook{URL: "http://du:8080/quiesce"},
+				PostReconfiguration: &ReconfigurationHook{URL: "http://du:8080/attach"},
+			}}
+			Expect(hooksValidator(spec)).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("VFGroups Validation", func() {
+	spec := func(groups ...VFGroup) SriovFecClusterConfigSpec {
+		return SriovFecClusterConfigSpec{PhysicalFunction: PhysicalFunctionConfig{VFAmount: 16, VFGroups: groups}}
+	}
+
+	Context("when groups split VFs of the PF", func() {
+		It("should not return an error", func() {
+			Expect(vfGroupsValidator(spec(
+				VFGroup{First: 0, Last: 7, ResourceName: "intel_fec_du_a"},
+				VFGroup{First: 8, Last: 15, ResourceName: "intel_fec_du_b", VFDriver: "vfio-pci"},
+			))).To(BeEmpty())
+		})
+	})
+
+	Context("when a group exceeds VFs of the PF", func() {
+		It("should return an error", func() {
+			errs := vfGroupsValidator(spec(VFGroup{First: 8, Last: 16, ResourceName: "intel_fec_du_b"}))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.physicalFunction.vfGroups[0].last"))
+		})
+	})
+
+	Context("when a group range is inverted", func() {
+		It("should return an error", func() {
+			errs := vfGroupsValidator(spec(VFGroup{First: 7, Last: 0, ResourceName: "intel_fec_du_a"}))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.physicalFunction.vfGroups[0].first"))
+		})
+	})
+
+	Context("when groups overlap", func() {
+		It("should return an error", func() {
+			errs := vfGroupsValidator(spec(
+				VFGroup{First: 0, Last: 8, ResourceName: "intel_fec_du_a"},
+				VFGroup{First: 8, Last: 15, ResourceName: "intel_fec_du_b"},
+			))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.physicalFunction.vfGroups[1]"))
+		})
+	})
+})
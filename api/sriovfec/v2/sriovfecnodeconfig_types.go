@@ -11,6 +11,23 @@ type VF struct {
 	PCIAddress string `json:"pciAddress"`
 	Driver     string `json:"driver"`
 	DeviceID   string `json:"deviceID"`
+	// Index of the VF within its PF, N of the virtfnN link of the PF; unset when the link cannot be read
+	// +optional
+	Index *int `json:"index,omitempty"`
+	// IOMMU group of the VF
+	// +optional
+	IOMMUGroup string `json:"iommuGroup,omitempty"`
+	// Container the VF is allocated to, as reported by PodResources API of the kubelet
+	// +optional
+	Consumer *VFConsumer `json:"consumer,omitempty"`
+}
+
+// VFConsumer identifies the container a VF is allocated to
+type VFConsumer struct {
+	Namespace    string `json:"namespace"`
+	Pod          string `json:"pod"`
+	Container    string `json:"container"`
+	ResourceName string `json:"resourceName"`
 }
 
 type SriovAccelerator struct {
@@ -20,6 +37,30 @@ type SriovAccelerator struct {
 	PFDriver   string `json:"driver"`
 	MaxVFs     int    `json:"maxVirtualFunctions"`
 	VFs        []VF   `json:"virtualFunctions"`
+	// NUMA node the accelerator is local to; not set when the platform does not report NUMA locality
+	// +optional
+	NUMANode *int `json:"numaNode,omitempty"`
+	// IOMMU group of the PF
+	// +optional
+	IOMMUGroup string `json:"iommuGroup,omitempty"`
+	// PCI revision ID
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// PCI subsystem vendor ID
+	// +optional
+	SubsystemVendorID string `json:"subsystemVendorID,omitempty"`
+	// PCI subsystem device ID
+	// +optional
+	SubsystemDeviceID string `json:"subsystemDeviceID,omitempty"`
+	// Device Serial Number (DSN) of the accelerator when it exposes the capability, e.g. 00-11-22-ff-fe-33-44-55
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// Current PCIe link speed, e.g. "16.0 GT/s PCIe"
+	// +optional
+	LinkSpeed string `json:"linkSpeed,omitempty"`
+	// Current PCIe link width, number of lanes
+	// +optional
+	LinkWidth int `json:"linkWidth,omitempty"`
 }
 
 type NodeInventory struct {
@@ -35,6 +76,26 @@ type SriovFecNodeConfigSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// Skips drain process when true; default false. Should be true if operator is running on SNO
 	DrainSkip bool `json:"drainSkip,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Defines which workloads are evicted from the node during reconfiguration. Takes precedence over drainSkip when set
+	// +kubebuilder:validation:Optional
+	DrainPolicy DrainPolicy `json:"drainPolicy,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Tunes cordon & drain performed during reconfiguration
+	// +kubebuilder:validation:Optional
+	DrainSettings *DrainSettings `json:"drainSettings,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Hooks invoked before and after reconfiguration of accelerators
+	// +kubebuilder:validation:Optional
+	Hooks *ReconfigurationHooks `json:"hooks,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Defines whether reconfiguration of the node waits for manual approval; default Automatic
+	// +kubebuilder:validation:Optional
+	ApprovalPolicy ApprovalPolicy `json:"approvalPolicy,omitempty"`
 }
 
 // SriovFecNodeConfigStatus defines the observed state of SriovFecNodeConfig
@@ -45,6 +106,22 @@ type SriovFecNodeConfigStatus struct {
 	// Provides information about FPGA inventory on the node
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Inventory NodeInventory `json:"inventory,omitempty"`
+	// Results of the reconfiguration hooks invoked during the last reconfiguration
+	HookResults []HookResult `json:"hookResults,omitempty"`
+	// PhysicalFunctions of the spec applied by the last successful reconfiguration
+	AppliedPhysicalFunctions []PhysicalFunctionConfigExt `json:"appliedPhysicalFunctions,omitempty"`
+}
+
+// HookResult describes the last invocation of a reconfiguration hook
+type HookResult struct {
+	// PreReconfiguration or PostReconfiguration
+	Phase string `json:"phase"`
+	// Succeeded or Failed
+	Result string `json:"result"`
+	// Details of the invocation e.g. failure reason
+	Message        string      `json:"message,omitempty"`
+	StartTime      metav1.Time `json:"startTime"`
+	CompletionTime metav1.Time `json:"completionTime"`
 }
 
 // +kubebuilder:object:root=true
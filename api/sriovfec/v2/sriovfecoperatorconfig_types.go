@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the only SriovFecOperatorConfig honored by the operator
+const OperatorConfigName = "default"
+
+// +kubebuilder:validation:Enum=panic;fatal;error;warn;info;debug;trace
+type LogLevel string
+
+// +kubebuilder:validation:Enum=json;text
+type LogFormat string
+
+// DaemonLoggerLevels defines log levels of individual loggers of the daemon
+type DaemonLoggerLevels struct {
+	// Log level of the node config reconcilers
+	// +kubebuilder:validation:Optional
+	Reconciler LogLevel `json:"reconciler,omitempty"`
+
+	// Log level of the drain helper
+	// +kubebuilder:validation:Optional
+	DrainHelper LogLevel `json:"drainHelper,omitempty"`
+
+	// Log level of the telemetry gatherer
+	// +kubebuilder:validation:Optional
+	Telemetry LogLevel `json:"telemetry,omitempty"`
+
+	// Log level of the pf_bb_config monitor
+	// +kubebuilder:validation:Optional
+	PfBbConfigMonitor LogLevel `json:"pfBbConfigMonitor,omitempty"`
+}
+
+// ComponentLogLevels defines log levels of operator components
+type ComponentLogLevels struct {
+	// Log level of the operator (controller manager)
+	// +kubebuilder:validation:Optional
+	Operator LogLevel `json:"operator,omitempty"`
+
+	// Log level of the daemon; applies to loggers of the daemon without a level in daemonLoggers
+	// +kubebuilder:validation:Optional
+	Daemon LogLevel `json:"daemon,omitempty"`
+
+	// Log levels of individual loggers of the daemon
+	// +kubebuilder:validation:Optional
+	DaemonLoggers *DaemonLoggerLevels `json:"daemonLoggers,omitempty"`
+}
+
+// DrainDefaults defines daemon defaults used when drainSettings of the node config do not override them
+type DrainDefaults struct {
+	// Timeout of a single drain attempt in seconds
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	// Duration of the lease serializing drains across nodes in seconds
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	LeaseDurationSeconds *int64 `json:"leaseDurationSeconds,omitempty"`
+}
+
+// ResourceNames defines names of resources exposed by the device plugin
+type ResourceNames struct {
+	// +kubebuilder:validation:Optional
+	LTE string `json:"lte,omitempty"`
+	// +kubebuilder:validation:Optional
+	FiveG string `json:"fiveG,omitempty"`
+	// +kubebuilder:validation:Optional
+	ACC100 string `json:"acc100,omitempty"`
+	// +kubebuilder:validation:Optional
+	ACC200 string `json:"acc200,omitempty"`
+	// +kubebuilder:validation:Optional
+	VRB2 string `json:"vrb2,omitempty"`
+}
+
+// Teardown defines how nodes are cleaned up when the SriovFecOperatorConfig is deleted
+type Teardown struct {
+	// Bind PFs back to the driver selected by the kernel instead of leaving them unbound
+	// +kubebuilder:validation:Optional
+	RestorePfDriver bool `json:"restorePfDriver,omitempty"`
+
+	// Delete SriovFecNodeConfigs and SriovVrbNodeConfigs once accelerators are reset
+	// +kubebuilder:validation:Optional
+	DeleteNodeConfigs bool `json:"deleteNodeConfigs,omitempty"`
+
+	// Remove node labels set by the labeler once accelerators are reset
+	// +kubebuilder:validation:Optional
+	RemoveNodeLabels bool `json:"removeNodeLabels,omitempty"`
+}
+
+// DaemonNodeGrouping defines groups of accelerator nodes getting a DaemonSet of the daemon each
+type DaemonNodeGrouping struct {
+	// Group nodes by their kernel version
+	// +kubebuilder:validation:Optional
+	KernelVersion bool `json:"kernelVersion,omitempty"`
+
+	// Group nodes by the value of the node label with this key; nodes without the label form a group of their own
+	// +kubebuilder:validation:Optional
+	Label string `json:"label,omitempty"`
+}
+
+// SriovFecOperatorConfigSpec defines the desired configuration of the operator
+type SriovFecOperatorConfigSpec struct {
+	// Additional node selector of the daemon; the daemon runs only on nodes with accelerators regardless of it
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	DaemonNodeSelector map[string]string `json:"daemonNodeSelector,omitempty"`
+
+	// Additional tolerations of the daemon
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	DaemonTolerations []corev1.Toleration `json:"daemonTolerations,omitempty"`
+
+	// Deploys the daemon as one DaemonSet per group of accelerator nodes, rendered with the kernel version of the group
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	DaemonNodeGrouping *DaemonNodeGrouping `json:"daemonNodeGrouping,omitempty"`
+
+	// Log levels of operator components
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	LogLevels *ComponentLogLevels `json:"logLevels,omitempty"`
+
+	// Format of logs of the operator and the daemon; json by default
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	LogFormat LogFormat `json:"logFormat,omitempty"`
+
+	// Interval of telemetry gathering by the daemon; 0s disables telemetry
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	TelemetryInterval *metav1.Duration `json:"telemetryInterval,omitempty"`
+
+	// Drain defaults of the daemon
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	DrainDefaults *DrainDefaults `json:"drainDefaults,omitempty"`
+
+	// Names of resources exposed by the device plugin
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	ResourceNames *ResourceNames `json:"resourceNames,omitempty"`
+
+	// Enables or disables optional features of the daemon; key: name of the feature
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Resets accelerators on all nodes when the config is deleted; deletion of the config is blocked until daemons finish
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	Teardown *Teardown `json:"teardown,omitempty"`
+}
+
+// SriovFecOperatorConfigStatus defines the observed state of SriovFecOperatorConfig
+type SriovFecOperatorConfigStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].reason`
+// +kubebuilder:resource:shortName=sfoc
+
+// SriovFecOperatorConfig is the Schema for the sriovfecoperatorconfigs API
+// +operator-sdk:csv:customresourcedefinitions:displayName="SriovFecOperatorConfig",resources={{DaemonSet,v1,sriov-fec-daemonset}}
+type SriovFecOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SriovFecOperatorConfigSpec   `json:"spec,omitempty"`
+	Status SriovFecOperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SriovFecOperatorConfigList contains a list of SriovFecOperatorConfig
+type SriovFecOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SriovFecOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SriovFecOperatorConfig{}, &SriovFecOperatorConfigList{})
+}
@@ -8,6 +8,8 @@
 package v2
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -115,6 +117,141 @@ func (in ByPriority) DeepCopy() ByPriority {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentLogLevels) DeepCopyInto(out *ComponentLogLevels) {
+	*out = *in
+	if in.DaemonLoggers != nil {
+		in, out := &in.DaemonLoggers, &out.DaemonLoggers
+		*out = new(DaemonLoggerLevels)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentLogLevels.
+func (in *ComponentLogLevels) DeepCopy() *ComponentLogLevels {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentLogLevels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonLoggerLevels) DeepCopyInto(out *DaemonLoggerLevels) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonLoggerLevels.
+func (in *DaemonLoggerLevels) DeepCopy() *DaemonLoggerLevels {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonLoggerLevels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonNodeGrouping) DeepCopyInto(out *DaemonNodeGrouping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonNodeGrouping.
+func (in *DaemonNodeGrouping) DeepCopy() *DaemonNodeGrouping {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonNodeGrouping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainDefaults) DeepCopyInto(out *DrainDefaults) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LeaseDurationSeconds != nil {
+		in, out := &in.LeaseDurationSeconds, &out.LeaseDurationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainDefaults.
+func (in *DrainDefaults) DeepCopy() *DrainDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainRetryBackoff) DeepCopyInto(out *DrainRetryBackoff) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainRetryBackoff.
+func (in *DrainRetryBackoff) DeepCopy() *DrainRetryBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainRetryBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainSettings) DeepCopyInto(out *DrainSettings) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.RespectPodDisruptionBudgets != nil {
+		in, out := &in.RespectPodDisruptionBudgets, &out.RespectPodDisruptionBudgets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludedPodSelector != nil {
+		in, out := &in.ExcludedPodSelector, &out.ExcludedPodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryBackoff != nil {
+		in, out := &in.RetryBackoff, &out.RetryBackoff
+		*out = new(DrainRetryBackoff)
+		**out = **in
+	}
+	if in.Lease != nil {
+		in, out := &in.Lease, &out.Lease
+		*out = new(LeaseSettings)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainSettings.
+func (in *DrainSettings) DeepCopy() *DrainSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FFTLutParam) DeepCopyInto(out *FFTLutParam) {
 	*out = *in
@@ -130,6 +267,38 @@ func (in *FFTLutParam) DeepCopy() *FFTLutParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookResult) DeepCopyInto(out *HookResult) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookResult.
+func (in *HookResult) DeepCopy() *HookResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HookResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaseSettings) DeepCopyInto(out *LeaseSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaseSettings.
+func (in *LeaseSettings) DeepCopy() *LeaseSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaseSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *N3000BBDevConfig) DeepCopyInto(out *N3000BBDevConfig) {
 	*out = *in
@@ -173,6 +342,11 @@ func (in *NodeInventory) DeepCopy() *NodeInventory {
 func (in *PhysicalFunctionConfig) DeepCopyInto(out *PhysicalFunctionConfig) {
 	*out = *in
 	in.BBDevConfig.DeepCopyInto(&out.BBDevConfig)
+	if in.VFGroups != nil {
+		in, out := &in.VFGroups, &out.VFGroups
+		*out = make([]VFGroup, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalFunctionConfig.
@@ -189,6 +363,11 @@ func (in *PhysicalFunctionConfig) DeepCopy() *PhysicalFunctionConfig {
 func (in *PhysicalFunctionConfigExt) DeepCopyInto(out *PhysicalFunctionConfigExt) {
 	*out = *in
 	in.BBDevConfig.DeepCopyInto(&out.BBDevConfig)
+	if in.VFGroups != nil {
+		in, out := &in.VFGroups, &out.VFGroups
+		*out = make([]VFGroup, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalFunctionConfigExt.
@@ -216,13 +395,146 @@ func (in *QueueGroupConfig) DeepCopy() *QueueGroupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconfigurationHook) DeepCopyInto(out *ReconfigurationHook) {
+	*out = *in
+	if in.JobTemplate != nil {
+		in, out := &in.JobTemplate, &out.JobTemplate
+		*out = new(batchv1.JobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconfigurationHook.
+func (in *ReconfigurationHook) DeepCopy() *ReconfigurationHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconfigurationHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconfigurationHooks) DeepCopyInto(out *ReconfigurationHooks) {
+	*out = *in
+	if in.PreReconfiguration != nil {
+		in, out := &in.PreReconfiguration, &out.PreReconfiguration
+		*out = new(ReconfigurationHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostReconfiguration != nil {
+		in, out := &in.PostReconfiguration, &out.PostReconfiguration
+		*out = new(ReconfigurationHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconfigurationHooks.
+func (in *ReconfigurationHooks) DeepCopy() *ReconfigurationHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconfigurationHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceNames) DeepCopyInto(out *ResourceNames) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceNames.
+func (in *ResourceNames) DeepCopy() *ResourceNames {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceNames)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutCheck) DeepCopyInto(out *RolloutCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutCheck.
+func (in *RolloutCheck) DeepCopy() *RolloutCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]RolloutCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SriovAccelerator) DeepCopyInto(out *SriovAccelerator) {
 	*out = *in
 	if in.VFs != nil {
 		in, out := &in.VFs, &out.VFs
 		*out = make([]VF, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int)
+		**out = **in
 	}
 }
 
@@ -242,7 +554,7 @@ func (in *SriovFecClusterConfig) DeepCopyInto(out *SriovFecClusterConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecClusterConfig.
@@ -312,6 +624,21 @@ func (in *SriovFecClusterConfigSpec) DeepCopyInto(out *SriovFecClusterConfigSpec
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DrainSettings != nil {
+		in, out := &in.DrainSettings, &out.DrainSettings
+		*out = new(DrainSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ReconfigurationHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecClusterConfigSpec.
@@ -327,6 +654,11 @@ func (in *SriovFecClusterConfigSpec) DeepCopy() *SriovFecClusterConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SriovFecClusterConfigStatus) DeepCopyInto(out *SriovFecClusterConfigStatus) {
 	*out = *in
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecClusterConfigStatus.
@@ -408,6 +740,16 @@ func (in *SriovFecNodeConfigSpec) DeepCopyInto(out *SriovFecNodeConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DrainSettings != nil {
+		in, out := &in.DrainSettings, &out.DrainSettings
+		*out = new(DrainSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ReconfigurationHooks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecNodeConfigSpec.
@@ -431,6 +773,20 @@ func (in *SriovFecNodeConfigStatus) DeepCopyInto(out *SriovFecNodeConfigStatus)
 		}
 	}
 	in.Inventory.DeepCopyInto(&out.Inventory)
+	if in.HookResults != nil {
+		in, out := &in.HookResults, &out.HookResults
+		*out = make([]HookResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedPhysicalFunctions != nil {
+		in, out := &in.AppliedPhysicalFunctions, &out.AppliedPhysicalFunctions
+		*out = make([]PhysicalFunctionConfigExt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecNodeConfigStatus.
@@ -443,6 +799,168 @@ func (in *SriovFecNodeConfigStatus) DeepCopy() *SriovFecNodeConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SriovFecOperatorConfig) DeepCopyInto(out *SriovFecOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecOperatorConfig.
+func (in *SriovFecOperatorConfig) DeepCopy() *SriovFecOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SriovFecOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SriovFecOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SriovFecOperatorConfigList) DeepCopyInto(out *SriovFecOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SriovFecOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecOperatorConfigList.
+func (in *SriovFecOperatorConfigList) DeepCopy() *SriovFecOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(SriovFecOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SriovFecOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SriovFecOperatorConfigSpec) DeepCopyInto(out *SriovFecOperatorConfigSpec) {
+	*out = *in
+	if in.DaemonNodeSelector != nil {
+		in, out := &in.DaemonNodeSelector, &out.DaemonNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DaemonTolerations != nil {
+		in, out := &in.DaemonTolerations, &out.DaemonTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DaemonNodeGrouping != nil {
+		in, out := &in.DaemonNodeGrouping, &out.DaemonNodeGrouping
+		*out = new(DaemonNodeGrouping)
+		**out = **in
+	}
+	if in.LogLevels != nil {
+		in, out := &in.LogLevels, &out.LogLevels
+		*out = new(ComponentLogLevels)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TelemetryInterval != nil {
+		in, out := &in.TelemetryInterval, &out.TelemetryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DrainDefaults != nil {
+		in, out := &in.DrainDefaults, &out.DrainDefaults
+		*out = new(DrainDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = new(ResourceNames)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Teardown != nil {
+		in, out := &in.Teardown, &out.Teardown
+		*out = new(Teardown)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecOperatorConfigSpec.
+func (in *SriovFecOperatorConfigSpec) DeepCopy() *SriovFecOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SriovFecOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SriovFecOperatorConfigStatus) DeepCopyInto(out *SriovFecOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovFecOperatorConfigStatus.
+func (in *SriovFecOperatorConfigStatus) DeepCopy() *SriovFecOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SriovFecOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Teardown) DeepCopyInto(out *Teardown) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Teardown.
+func (in *Teardown) DeepCopy() *Teardown {
+	if in == nil {
+		return nil
+	}
+	out := new(Teardown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UplinkDownlink) DeepCopyInto(out *UplinkDownlink) {
 	*out = *in
@@ -477,6 +995,16 @@ func (in *UplinkDownlinkQueues) DeepCopy() *UplinkDownlinkQueues {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VF) DeepCopyInto(out *VF) {
 	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int)
+		**out = **in
+	}
+	if in.Consumer != nil {
+		in, out := &in.Consumer, &out.Consumer
+		*out = new(VFConsumer)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VF.
@@ -488,3 +1016,33 @@ func (in *VF) DeepCopy() *VF {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VFConsumer) DeepCopyInto(out *VFConsumer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VFConsumer.
+func (in *VFConsumer) DeepCopy() *VFConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(VFConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VFGroup) DeepCopyInto(out *VFGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VFGroup.
+func (in *VFGroup) DeepCopy() *VFGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VFGroup)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -56,6 +56,86 @@ func (in *SriovVrbNodeConfig) FindCondition(conditionType string) *metav1.Condit
 	return meta.FindStatusCondition(in.Status.Conditions, conditionType)
 }
 
+// drainPolicyRank orders drain policies from the least to the most disruptive one
+var drainPolicyRank = map[DrainPolicy]int{
+	DrainPolicyNever:                    0,
+	DrainPolicyAcceleratorConsumersOnly: 1,
+	DrainPolicyFull:                     2,
+}
+
+// LeastDisruptive returns the less disruptive one of both drain policies
+func (p DrainPolicy) LeastDisruptive(other DrainPolicy) DrainPolicy {
+	if drainPolicyRank[other] < drainPolicyRank[p] {
+		return other
+	}
+	return p
+}
+
+// EffectiveDrainPolicy returns requested drain policy; drainSkip is taken into account when the policy is not set
+func (s SriovVrbClusterConfigSpec) EffectiveDrainPolicy() DrainPolicy {
+	if s.DrainPolicy != "" {
+		return s.DrainPolicy
+	}
+	if s.DrainSkip == nil || *s.DrainSkip {
+		return DrainPolicyNever
+	}
+	return DrainPolicyFull
+}
+
+// EffectiveDrainPolicy returns requested drain policy; drainSkip is taken into account when the policy is not set
+func (s SriovVrbNodeConfigSpec) EffectiveDrainPolicy() DrainPolicy {
+	if s.DrainPolicy != "" {
+		return s.DrainPolicy
+	}
+	if s.DrainSkip {
+		return DrainPolicyNever
+	}
+	return DrainPolicyFull
+}
+
+// MostRestrictive returns Manual if any of both approval policies is Manual
+func (p ApprovalPolicy) MostRestrictive(other ApprovalPolicy) ApprovalPolicy {
+	if p == ApprovalPolicyManual || other == ApprovalPolicyManual {
+		return ApprovalPolicyManual
+	}
+	if p == "" {
+		return other
+	}
+	return p
+}
+
+// RequiresApproval returns true if reconfiguration of the node has to be approved manually
+func (s SriovVrbNodeConfigSpec) RequiresApproval() bool {
+	return s.ApprovalPolicy == ApprovalPolicyManual
+}
+
+// VFGroupOf returns the VF group the VF of given index belongs to; nil when it does not belong to any
+func (pf PhysicalFunctionConfigExt) VFGroupOf(index int) *VFGroup {
+	for i := range pf.VFGroups {
+		if pf.VFGroups[i].First <= index && index <= pf.VFGroups[i].Last {
+			return &pf.VFGroups[i]
+		}
+	}
+	return nil
+}
+
+// VFDriverOf returns the driver the VF of given index is bound to; VFDriver of its group takes precedence
+func (pf PhysicalFunctionConfigExt) VFDriverOf(index int) string {
+	if group := pf.VFGroupOf(index); group != nil && group.VFDriver != "" {
+		return group.VFDriver
+	}
+	return pf.VFDriver
+}
+
+// VFDrivers returns drivers VFs of the PF are bound to, indexed by VF index
+func (pf PhysicalFunctionConfigExt) VFDrivers() []string {
+	drivers := make([]string, 0, pf.VFAmount)
+	for i := 0; i < pf.VFAmount; i++ {
+		drivers = append(drivers, pf.VFDriverOf(i))
+	}
+	return drivers
+}
+
 func isNil(v interface{}) bool {
 	return v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
 }
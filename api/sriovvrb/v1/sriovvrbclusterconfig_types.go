@@ -6,6 +6,7 @@ package v1
 import (
 	"fmt"
 
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,6 +30,31 @@ var (
 	IgnoredSync SyncStatus = "Ignored"
 )
 
+// DrainPolicy defines which workloads are evicted from the node before its accelerators are reconfigured
+// +kubebuilder:validation:Enum=Full;AcceleratorConsumersOnly;Never
+type DrainPolicy string
+
+const (
+	// DrainPolicyFull cordons the node and evicts all pods except the ones managed by DaemonSets
+	DrainPolicyFull DrainPolicy = "Full"
+	// DrainPolicyAcceleratorConsumersOnly evicts only pods requesting resources served from the reconfigured accelerators;
+	// the node is not cordoned, it is only tainted NoSchedule for the time of reconfiguration
+	DrainPolicyAcceleratorConsumersOnly DrainPolicy = "AcceleratorConsumersOnly"
+	// DrainPolicyNever skips the drain process
+	DrainPolicyNever DrainPolicy = "Never"
+)
+
+// ApprovalPolicy defines whether reconfiguration of the node has to be approved before it is applied
+// +kubebuilder:validation:Enum=Automatic;Manual
+type ApprovalPolicy string
+
+const (
+	// ApprovalPolicyAutomatic applies changes as soon as they are detected
+	ApprovalPolicyAutomatic ApprovalPolicy = "Automatic"
+	// ApprovalPolicyManual applies changes only after the generation of the node config is approved
+	ApprovalPolicyManual ApprovalPolicy = "Manual"
+)
+
 type QueueGroupConfig struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=32
@@ -140,6 +166,25 @@ type PhysicalFunctionConfig struct {
 	VFAmount int `json:"vfAmount"`
 	// BBDevConfig is a config for PF's queues
 	BBDevConfig BBDevConfig `json:"bbDevConfig"`
+	// VFGroups expose ranges of VFs as separate resources of sriov-device-plugin
+	// +kubebuilder:validation:Optional
+	VFGroups []VFGroup `json:"vfGroups,omitempty"`
+}
+
+// VFGroup is a range of VFs of the PF served as a separate resource
+type VFGroup struct {
+	// First is the index of the first VF of the group
+	// +kubebuilder:validation:Minimum=0
+	First int `json:"first"`
+	// Last is the index of the last VF of the group, inclusive
+	// +kubebuilder:validation:Minimum=0
+	Last int `json:"last"`
+	// ResourceName of sriov-device-plugin serving VFs of the group
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-_]+$`
+	ResourceName string `json:"resourceName"`
+	// VFDriver to bound VFs of the group to; VFDriver of the PF is used when not set
+	// +kubebuilder:validation:Optional
+	VFDriver string `json:"vfDriver,omitempty"`
 }
 
 type PhysicalFunctionConfigExt struct {
@@ -163,6 +208,9 @@ type PhysicalFunctionConfigExt struct {
 
 	// VrbResourceName is optional for custom resource name for sriov-device-plugin
 	VrbResourceName string `json:"vrbResourceName"`
+
+	// VFGroups expose ranges of VFs as separate resources of sriov-device-plugin
+	VFGroups []VFGroup `json:"vfGroups,omitempty"`
 }
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -191,6 +239,31 @@ type SriovVrbClusterConfigSpec struct {
 	// Skips drain process when true; default false. Should be true if operator is running on SNO
 	DrainSkip *bool `json:"drainSkip,omitempty"`
 
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Defines which workloads are evicted from the node during reconfiguration. Takes precedence over drainSkip when set
+	// +kubebuilder:validation:Optional
+	DrainPolicy DrainPolicy `json:"drainPolicy,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Tunes cordon & drain performed during reconfiguration
+	// +kubebuilder:validation:Optional
+	DrainSettings *DrainSettings `json:"drainSettings,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Hooks invoked before and after reconfiguration of accelerators
+	// +kubebuilder:validation:Optional
+	Hooks *ReconfigurationHooks `json:"hooks,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Defines whether reconfiguration of the node waits for manual approval; default Automatic
+	// +kubebuilder:validation:Optional
+	ApprovalPolicy ApprovalPolicy `json:"approvalPolicy,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Rolls changes of the CR out to canary nodes first; changes are applied to remaining nodes after canaries stay healthy for the soak period
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
 	// Indicates custom resource name for sriov-device-plugin
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-_]+$`
@@ -208,12 +281,165 @@ type AcceleratorSelector struct {
 	MaxVFs   int    `json:"maxVirtualFunctions,omitempty"`
 }
 
+// DrainSettings tunes cordon & drain performed before accelerators are reconfigured.
+// Daemon defaults are used for the fields which are not set.
+type DrainSettings struct {
+	// Timeout of a single drain attempt in seconds
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	// Grace period given to evicted pods in seconds; -1 uses terminationGracePeriodSeconds of the pod
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	GracePeriodSeconds *int `json:"gracePeriodSeconds,omitempty"`
+
+	// Respects PodDisruptionBudgets when true (default). When false, pods which cannot be evicted are deleted
+	// +kubebuilder:validation:Optional
+	RespectPodDisruptionBudgets *bool `json:"respectPodDisruptionBudgets,omitempty"`
+
+	// Pods matching the selector are never evicted
+	// +kubebuilder:validation:Optional
+	ExcludedPodSelector *metav1.LabelSelector `json:"excludedPodSelector,omitempty"`
+
+	// Backoff between failed cordon & drain attempts
+	// +kubebuilder:validation:Optional
+	RetryBackoff *DrainRetryBackoff `json:"retryBackoff,omitempty"`
+
+	// Timings of the lease which serializes node reconfigurations across the cluster
+	// +kubebuilder:validation:Optional
+	Lease *LeaseSettings `json:"lease,omitempty"`
+}
+
+type DrainRetryBackoff struct {
+	// Number of attempts
+	// +kubebuilder:validation:Minimum=1
+	Steps int `json:"steps"`
+	// Interval after the first failed attempt in seconds
+	// +kubebuilder:validation:Minimum=1
+	InitialIntervalSeconds int64 `json:"initialIntervalSeconds"`
+	// Interval is multiplied by the factor after each failed attempt
+	// +kubebuilder:validation:Minimum=1
+	Factor int `json:"factor"`
+}
+
+type LeaseSettings struct {
+	// +kubebuilder:validation:Minimum=1
+	DurationSeconds int64 `json:"durationSeconds"`
+	// +kubebuilder:validation:Minimum=1
+	RenewDeadlineSeconds int64 `json:"renewDeadlineSeconds"`
+	// +kubebuilder:validation:Minimum=1
+	RetryPeriodSeconds int64 `json:"retryPeriodSeconds"`
+}
+
+// HookFailurePolicy defines how a failed hook affects the reconfiguration
+// +kubebuilder:validation:Enum=Fail;Ignore
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail aborts the reconfiguration when the hook fails
+	HookFailurePolicyFail HookFailurePolicy = "Fail"
+	// HookFailurePolicyIgnore continues the reconfiguration when the hook fails
+	HookFailurePolicyIgnore HookFailurePolicy = "Ignore"
+)
+
+// ReconfigurationHooks are invoked around reconfiguration of accelerators
+type ReconfigurationHooks struct {
+	// Invoked before the node is drained and the accelerators are reconfigured
+	// +kubebuilder:validation:Optional
+	PreReconfiguration *ReconfigurationHook `json:"preReconfiguration,omitempty"`
+
+	// Invoked after the device plugin has been restarted
+	// +kubebuilder:validation:Optional
+	PostReconfiguration *ReconfigurationHook `json:"postReconfiguration,omitempty"`
+}
+
+// ReconfigurationHook is either an HTTP webhook or a Job running on the reconfigured node; exactly one has to be set
+type ReconfigurationHook struct {
+	// URL receiving POST request with JSON payload describing the reconfiguration; 2xx response means success
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url,omitempty"`
+
+	// Template of the Job executed on the reconfigured node
+	// +kubebuilder:validation:Optional
+	JobTemplate *batchv1.JobTemplateSpec `json:"jobTemplate,omitempty"`
+
+	// Time given to the hook to complete
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+
+	// Defines whether the reconfiguration is aborted when the hook fails
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Fail
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
 // SriovVrbClusterConfigStatus defines the observed state of SriovVrbClusterConfig
 type SriovVrbClusterConfigStatus struct {
 	// Indicates the synchronization status of the CR
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	SyncStatus    SyncStatus `json:"syncStatus,omitempty"`
 	LastSyncError string     `json:"lastSyncError,omitempty"`
+	// Progress of the rollout of the latest generation of the CR
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStrategy defines canary nodes and the period they have to stay healthy before the change is applied to remaining nodes
+type RolloutStrategy struct {
+	// Selects canary nodes among nodes matching nodeSelector; first canaryCount nodes (sorted by name) are used when not set
+	// +kubebuilder:validation:Optional
+	CanaryNodeSelector map[string]string `json:"canaryNodeSelector,omitempty"`
+	// Number of canary nodes used when canaryNodeSelector is not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	CanaryCount int `json:"canaryCount,omitempty"`
+	// Time the canary nodes have to stay healthy after they have been configured
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=300
+	SoakSeconds int `json:"soakSeconds,omitempty"`
+}
+
+// RolloutPhase describes the progress of the rollout
+type RolloutPhase string
+
+const (
+	// RolloutPhaseCanary indicates that the change is being applied to canary nodes
+	RolloutPhaseCanary RolloutPhase = "Canary"
+	// RolloutPhaseSoaking indicates that canary nodes are configured and their health is being verified
+	RolloutPhaseSoaking RolloutPhase = "Soaking"
+	// RolloutPhaseCompleted indicates that the change is applied to all nodes
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+	// RolloutPhaseHalted indicates that health verification of canary nodes failed; remaining nodes are not updated
+	RolloutPhaseHalted RolloutPhase = "Halted"
+)
+
+// RolloutStatus describes the rollout of the given generation of the CR
+type RolloutStatus struct {
+	// Generation of the CR being rolled out
+	Generation int64        `json:"generation"`
+	Phase      RolloutPhase `json:"phase"`
+	// Nodes receiving the change first
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+	// Time when all canary nodes were configured
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Results of the last health verification of canary nodes
+	Checks  []RolloutCheck `json:"checks,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// RolloutCheck is a result of a single health check of a canary node
+type RolloutCheck struct {
+	Node string `json:"node"`
+	// Configured, VfStatus or Degraded
+	Check   string `json:"check"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -8,6 +8,7 @@ import (
 
 	"github.com/intel/sriov-fec-operator/pkg/common/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -56,6 +57,9 @@ func validate(spec SriovVrbClusterConfigSpec) (errs field.ErrorList) {
 		vrb2VfAmountValidator,
 		vrb2NumQueueGroupsValidator,
 		vrb2NumQueuesPerOperationValidator,
+		drainSettingsValidator,
+		hooksValidator,
+		vfGroupsValidator,
 	}
 
 	for _, validate := range validators {
@@ -336,3 +340,82 @@ func vrb2NumQueuesPerOperationValidator(spec SriovVrbClusterConfigSpec) (errs fi
 
 	return errs
 }
+
+func drainSettingsValidator(spec SriovVrbClusterConfigSpec) (errs field.ErrorList) {
+	settings := spec.DrainSettings
+	if settings == nil {
+		return
+	}
+	path := field.NewPath("spec", "drainSettings")
+
+	if settings.ExcludedPodSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(settings.ExcludedPodSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("excludedPodSelector"), settings.ExcludedPodSelector, err.Error()))
+		}
+	}
+
+	// leader election requires leaseDuration > renewDeadline > 1.2 * retryPeriod
+	if lease := settings.Lease; lease != nil {
+		if lease.DurationSeconds <= lease.RenewDeadlineSeconds {
+			errs = append(errs, field.Invalid(path.Child("lease", "durationSeconds"), lease.DurationSeconds,
+				"durationSeconds must be greater than renewDeadlineSeconds"))
+		}
+		if lease.RenewDeadlineSeconds*10 <= lease.RetryPeriodSeconds*12 {
+			errs = append(errs, field.Invalid(path.Child("lease", "renewDeadlineSeconds"), lease.RenewDeadlineSeconds,
+				"renewDeadlineSeconds must be greater than 1.2 * retryPeriodSeconds"))
+		}
+	}
+
+	return
+}
+
+func hooksValidator(spec SriovVrbClusterConfigSpec) (errs field.ErrorList) {
+	if spec.Hooks == nil {
+		return
+	}
+
+	validateHook := func(path *field.Path, hook *ReconfigurationHook) *field.Error {
+		if hook == nil {
+			return nil
+		}
+		if (hook.URL == "") == (hook.JobTemplate == nil) {
+			return field.Invalid(path, hook, "exactly one of url or jobTemplate has to be specified")
+		}
+		if hook.JobTemplate != nil && len(hook.JobTemplate.Spec.Template.Spec.Containers) == 0 {
+			return field.Required(path.Child("jobTemplate", "spec", "template", "spec", "containers"), "jobTemplate has to define at least one container")
+		}
+		return nil
+	}
+
+	path := field.NewPath("spec", "hooks")
+	if err := validateHook(path.Child("preReconfiguration"), spec.Hooks.PreReconfiguration); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateHook(path.Child("postReconfiguration"), spec.Hooks.PostReconfiguration); err != nil {
+		errs = append(errs, err)
+	}
+
+	return
+}
+
+func vfGroupsValidator(spec SriovVrbClusterConfigSpec) (errs field.ErrorList) {
+	pf := spec.PhysicalFunction
+	path := field.NewPath("spec", "physicalFunction", "vfGroups")
+
+	for i, group := range pf.VFGroups {
+		if group.First > group.Last {
+			errs = append(errs, field.Invalid(path.Index(i).Child("first"), group.First, "first must not be greater than last"))
+		}
+		if group.Last >= pf.VFAmount {
+			errs = append(errs, field.Invalid(path.Index(i).Child("last"), group.Last, "last must be lower than physicalFunction.vfAmount"))
+		}
+		for j := 0; j < i; j++ {
+			if group.First <= pf.VFGroups[j].Last && pf.VFGroups[j].First <= group.Last {
+				errs = append(errs, field.Invalid(path.Index(i), fmt.Sprintf("%d-%d", group.First, group.Last),
+					fmt.Sprintf("VF range overlaps with vfGroups[%d]", j)))
+			}
+		}
+	}
+
+	return
+}
@@ -8,6 +8,7 @@
 package v1
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -92,6 +93,66 @@ func (in ByPriority) DeepCopy() ByPriority {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainRetryBackoff) DeepCopyInto(out *DrainRetryBackoff) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainRetryBackoff.
+func (in *DrainRetryBackoff) DeepCopy() *DrainRetryBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainRetryBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainSettings) DeepCopyInto(out *DrainSettings) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.RespectPodDisruptionBudgets != nil {
+		in, out := &in.RespectPodDisruptionBudgets, &out.RespectPodDisruptionBudgets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludedPodSelector != nil {
+		in, out := &in.ExcludedPodSelector, &out.ExcludedPodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryBackoff != nil {
+		in, out := &in.RetryBackoff, &out.RetryBackoff
+		*out = new(DrainRetryBackoff)
+		**out = **in
+	}
+	if in.Lease != nil {
+		in, out := &in.Lease, &out.Lease
+		*out = new(LeaseSettings)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainSettings.
+func (in *DrainSettings) DeepCopy() *DrainSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FFTLutParam) DeepCopyInto(out *FFTLutParam) {
 	*out = *in
@@ -107,6 +168,38 @@ func (in *FFTLutParam) DeepCopy() *FFTLutParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookResult) DeepCopyInto(out *HookResult) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookResult.
+func (in *HookResult) DeepCopy() *HookResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HookResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaseSettings) DeepCopyInto(out *LeaseSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaseSettings.
+func (in *LeaseSettings) DeepCopy() *LeaseSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaseSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeInventory) DeepCopyInto(out *NodeInventory) {
 	*out = *in
@@ -133,6 +226,11 @@ func (in *NodeInventory) DeepCopy() *NodeInventory {
 func (in *PhysicalFunctionConfig) DeepCopyInto(out *PhysicalFunctionConfig) {
 	*out = *in
 	in.BBDevConfig.DeepCopyInto(&out.BBDevConfig)
+	if in.VFGroups != nil {
+		in, out := &in.VFGroups, &out.VFGroups
+		*out = make([]VFGroup, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalFunctionConfig.
@@ -149,6 +247,11 @@ func (in *PhysicalFunctionConfig) DeepCopy() *PhysicalFunctionConfig {
 func (in *PhysicalFunctionConfigExt) DeepCopyInto(out *PhysicalFunctionConfigExt) {
 	*out = *in
 	in.BBDevConfig.DeepCopyInto(&out.BBDevConfig)
+	if in.VFGroups != nil {
+		in, out := &in.VFGroups, &out.VFGroups
+		*out = make([]VFGroup, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalFunctionConfigExt.
@@ -176,13 +279,131 @@ func (in *QueueGroupConfig) DeepCopy() *QueueGroupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconfigurationHook) DeepCopyInto(out *ReconfigurationHook) {
+	*out = *in
+	if in.JobTemplate != nil {
+		in, out := &in.JobTemplate, &out.JobTemplate
+		*out = new(batchv1.JobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconfigurationHook.
+func (in *ReconfigurationHook) DeepCopy() *ReconfigurationHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconfigurationHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconfigurationHooks) DeepCopyInto(out *ReconfigurationHooks) {
+	*out = *in
+	if in.PreReconfiguration != nil {
+		in, out := &in.PreReconfiguration, &out.PreReconfiguration
+		*out = new(ReconfigurationHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostReconfiguration != nil {
+		in, out := &in.PostReconfiguration, &out.PostReconfiguration
+		*out = new(ReconfigurationHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconfigurationHooks.
+func (in *ReconfigurationHooks) DeepCopy() *ReconfigurationHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconfigurationHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutCheck) DeepCopyInto(out *RolloutCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutCheck.
+func (in *RolloutCheck) DeepCopy() *RolloutCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]RolloutCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SriovAccelerator) DeepCopyInto(out *SriovAccelerator) {
 	*out = *in
 	if in.VFs != nil {
 		in, out := &in.VFs, &out.VFs
 		*out = make([]VF, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int)
+		**out = **in
 	}
 }
 
@@ -202,7 +423,7 @@ func (in *SriovVrbClusterConfig) DeepCopyInto(out *SriovVrbClusterConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovVrbClusterConfig.
@@ -272,6 +493,21 @@ func (in *SriovVrbClusterConfigSpec) DeepCopyInto(out *SriovVrbClusterConfigSpec
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DrainSettings != nil {
+		in, out := &in.DrainSettings, &out.DrainSettings
+		*out = new(DrainSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ReconfigurationHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovVrbClusterConfigSpec.
@@ -287,6 +523,11 @@ func (in *SriovVrbClusterConfigSpec) DeepCopy() *SriovVrbClusterConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SriovVrbClusterConfigStatus) DeepCopyInto(out *SriovVrbClusterConfigStatus) {
 	*out = *in
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovVrbClusterConfigStatus.
@@ -368,6 +609,16 @@ func (in *SriovVrbNodeConfigSpec) DeepCopyInto(out *SriovVrbNodeConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DrainSettings != nil {
+		in, out := &in.DrainSettings, &out.DrainSettings
+		*out = new(DrainSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ReconfigurationHooks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovVrbNodeConfigSpec.
@@ -391,6 +642,20 @@ func (in *SriovVrbNodeConfigStatus) DeepCopyInto(out *SriovVrbNodeConfigStatus)
 		}
 	}
 	in.Inventory.DeepCopyInto(&out.Inventory)
+	if in.HookResults != nil {
+		in, out := &in.HookResults, &out.HookResults
+		*out = make([]HookResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedPhysicalFunctions != nil {
+		in, out := &in.AppliedPhysicalFunctions, &out.AppliedPhysicalFunctions
+		*out = make([]PhysicalFunctionConfigExt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SriovVrbNodeConfigStatus.
@@ -406,6 +671,16 @@ func (in *SriovVrbNodeConfigStatus) DeepCopy() *SriovVrbNodeConfigStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VF) DeepCopyInto(out *VF) {
 	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int)
+		**out = **in
+	}
+	if in.Consumer != nil {
+		in, out := &in.Consumer, &out.Consumer
+		*out = new(VFConsumer)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VF.
@@ -418,6 +693,36 @@ func (in *VF) DeepCopy() *VF {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VFConsumer) DeepCopyInto(out *VFConsumer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VFConsumer.
+func (in *VFConsumer) DeepCopy() *VFConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(VFConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VFGroup) DeepCopyInto(out *VFGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VFGroup.
+func (in *VFGroup) DeepCopy() *VFGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VFGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VRB1BBDevConfig) DeepCopyInto(out *VRB1BBDevConfig) {
 	*out = *in
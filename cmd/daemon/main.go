@@ -4,10 +4,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"syscall"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -22,6 +24,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,6 +37,9 @@ var (
 	setupLog = utils.NewLogger()
 )
 
+// staleReconfigurationMarksTimeout bounds the startup of the daemon when stale reconfiguration marks cannot be removed
+const staleReconfigurationMarksTimeout = 2 * time.Minute
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(sriovv2.AddToScheme(scheme))
@@ -41,7 +47,9 @@ func init() {
 }
 
 func initFecReconciler(mgr manager.Manager, drainHelper *drainhelper.DrainHelper, nodeNameRef types.NamespacedName,
-	nodeConfigurer *daemon.NodeConfigurator, devicePluginController *daemon.DevicePluginController, directClient client.Client) error {
+	nodeConfigurer *daemon.NodeConfigurator, restartDevicePlugin daemon.RestartDevicePluginFunction, devicePlugin *daemon.DevicePlugin,
+	directClient client.Client, logConfigController *daemon.LogConfigController, nodeFeatureWriter *daemon.NodeFeatureWriter,
+	cdiSpecWriter *daemon.CDISpecWriter) error {
 
 	isFecDevice, _, err := utils.FindAccelerator(daemon.FecConfigPath)
 	if err != nil {
@@ -52,10 +60,14 @@ func initFecReconciler(mgr manager.Manager, drainHelper *drainhelper.DrainHelper
 		return nil
 	}
 
-	reconciler, err := daemon.FecNewNodeConfigReconciler(mgr.GetClient(), drainHelper.Run, nodeNameRef, nodeConfigurer, devicePluginController.RestartDevicePlugin)
+	reconciler, err := daemon.FecNewNodeConfigReconciler(mgr.GetClient(), drainHelper.Run, nodeNameRef, nodeConfigurer, restartDevicePlugin)
 	if err != nil {
 		return err
 	}
+	logConfigController.Register(daemon.ReconcilerLogger, reconciler.Logger())
+	reconciler.SetNodeFeatureWriter(nodeFeatureWriter)
+	reconciler.SetDevicePlugin(devicePlugin)
+	reconciler.SetCDISpecWriter(cdiSpecWriter)
 
 	if err := reconciler.SetupWithManager(mgr); err != nil {
 		return err
@@ -69,7 +81,9 @@ func initFecReconciler(mgr manager.Manager, drainHelper *drainhelper.DrainHelper
 }
 
 func initVrbReconciler(mgr manager.Manager, drainHelper *drainhelper.DrainHelper, nodeNameRef types.NamespacedName,
-	nodeConfigurer *daemon.NodeConfigurator, devicePluginController *daemon.DevicePluginController, directClient client.Client) error {
+	nodeConfigurer *daemon.NodeConfigurator, restartDevicePlugin daemon.RestartDevicePluginFunction, devicePlugin *daemon.DevicePlugin,
+	directClient client.Client, logConfigController *daemon.LogConfigController, nodeFeatureWriter *daemon.NodeFeatureWriter,
+	cdiSpecWriter *daemon.CDISpecWriter) error {
 
 	isVrbDevice, _, err := utils.FindAccelerator(daemon.VrbConfigPath)
 	if err != nil {
@@ -80,10 +94,14 @@ func initVrbReconciler(mgr manager.Manager, drainHelper *drainhelper.DrainHelper
 		return nil
 	}
 
-	reconciler, err := daemon.VrbNewNodeConfigReconciler(mgr.GetClient(), drainHelper.Run, nodeNameRef, nodeConfigurer, devicePluginController.RestartDevicePlugin)
+	reconciler, err := daemon.VrbNewNodeConfigReconciler(mgr.GetClient(), drainHelper.Run, nodeNameRef, nodeConfigurer, restartDevicePlugin)
 	if err != nil {
 		return err
 	}
+	logConfigController.Register(daemon.ReconcilerLogger, reconciler.Logger())
+	reconciler.SetNodeFeatureWriter(nodeFeatureWriter)
+	reconciler.SetDevicePlugin(devicePlugin)
+	reconciler.SetCDISpecWriter(cdiSpecWriter)
 
 	if err := reconciler.SetupWithManager(mgr); err != nil {
 		return err
@@ -125,13 +143,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		setupLog.WithError(err).Error("failed to create dynamic client")
+		os.Exit(1)
+	}
+
 	mgr, err := daemon.CreateManager(config, scheme, ns, 8080, 8081, setupLog)
 	if err != nil {
 		setupLog.WithError(err).Error("unable to start manager")
 		os.Exit(1)
 	}
 
-	daemon.StartTelemetryDaemon(mgr, nodeName, ns, directClient, setupLog)
+	logConfigController := daemon.NewLogConfigController(mgr.GetClient(), setupLog)
+	if err := logConfigController.SetupWithManager(mgr); err != nil {
+		setupLog.WithError(err).Error("unable to set up log configuration controller")
+		os.Exit(1)
+	}
 
 	vfioToken, err := readVfioToken()
 	if err != nil {
@@ -145,13 +173,68 @@ func main() {
 		os.Exit(1)
 	}
 
+	// reconcilers are not started yet, so the taint of an interrupted reconfiguration cannot be confused with a new one;
+	// the daemon starts anyway when the API server is unreachable or access to the Node is denied for too long
+	staleMarksCtx, cancelStaleMarks := context.WithTimeout(context.Background(), staleReconfigurationMarksTimeout)
+	if err := daemon.RemoveStaleReconfigurationMarksWithRetry(staleMarksCtx, directClient, nodeName, setupLog); err != nil {
+		setupLog.WithError(err).Error("failed to remove stale reconfiguration taint - giving up")
+	}
+	cancelStaleMarks()
+
+	featureGates, err := utils.ParseFeatureGates(os.Getenv(utils.FeatureGatesEnvVarName))
+	if err != nil {
+		setupLog.WithError(err).Error("failed to parse feature gates")
+		os.Exit(1)
+	}
+
 	nodeNameRef := types.NamespacedName{Namespace: ns, Name: nodeName}
-	drainHelper := drainhelper.NewDrainHelper(utils.NewLogger(), cset, nodeName, ns, isSingleNodeCluster)
-	pfBBConfigController := daemon.NewPfBBConfigController(utils.NewLogger(), vfioToken.String())
-	nodeConfigurer := daemon.NewNodeConfigurator(utils.NewLogger(), pfBBConfigController, mgr.GetClient(), nodeNameRef)
-	devicePluginController := daemon.NewDevicePluginController(mgr.GetClient(), utils.NewLogger(), nodeNameRef)
+	drainLog, pfBbConfigLog, nodeConfigurerLog, devicePluginLog := utils.NewLogger(), utils.NewLogger(), utils.NewLogger(), utils.NewLogger()
+	logConfigController.Register(daemon.DrainHelperLogger, drainLog)
+	logConfigController.Register(daemon.PfBbConfigMonitorLogger, pfBbConfigLog)
+	logConfigController.Register(daemon.ReconcilerLogger, nodeConfigurerLog, devicePluginLog)
+
+	var devicePlugin *daemon.DevicePlugin
+	if featureGates.Enabled(utils.FeatureDevicePlugin) {
+		devicePlugin = daemon.NewDevicePlugin(mgr.GetClient(), devicePluginLog, nodeNameRef)
+		if err := mgr.Add(devicePlugin); err != nil {
+			setupLog.WithError(err).Error("unable to set up device plugin")
+			os.Exit(1)
+		}
+	}
+
+	telemetryLog := utils.NewLogger()
+	logConfigController.Register(daemon.TelemetryLogger, telemetryLog)
+	daemon.StartTelemetryDaemon(mgr, nodeName, ns, directClient, telemetryLog, devicePlugin.SetVfReadiness)
+
+	drainHelper := drainhelper.NewDrainHelper(drainLog, cset, nodeName, ns, isSingleNodeCluster)
+	if featureGates.Enabled(utils.FeatureNodeMaintenance) {
+		drainHelper.EnableNodeMaintenance(dynamicClient)
+	}
+	pfBBConfigController := daemon.NewPfBBConfigController(pfBbConfigLog, vfioToken.String())
+	nodeConfigurer := daemon.NewNodeConfigurator(nodeConfigurerLog, pfBBConfigController, mgr.GetClient(), nodeNameRef)
+	restartDevicePlugin := daemon.NewDevicePluginController(mgr.GetClient(), devicePluginLog, nodeNameRef).RestartDevicePlugin
+	if devicePlugin != nil {
+		// the built-in device plugin follows changes of VFs without restarts
+		restartDevicePlugin = devicePlugin.Refresh
+	}
+
+	teardownController := daemon.NewTeardownController(mgr.GetClient(), nodeConfigurerLog, nodeNameRef, nodeConfigurer)
+	if err := teardownController.SetupWithManager(mgr); err != nil {
+		setupLog.WithError(err).Error("unable to set up teardown controller")
+		os.Exit(1)
+	}
+
+	var nodeFeatureWriter *daemon.NodeFeatureWriter
+	if featureGates.Enabled(utils.FeatureNodeFeatureDiscovery) {
+		nodeFeatureWriter = daemon.NewNodeFeatureWriter(nodeConfigurerLog, daemon.NodeFeaturesDir)
+	}
+
+	var cdiSpecWriter *daemon.CDISpecWriter
+	if featureGates.Enabled(utils.FeatureContainerDeviceInterface) {
+		cdiSpecWriter = daemon.NewCDISpecWriter(nodeConfigurerLog, daemon.CDISpecDir, vfioToken.String())
+	}
 
-	if err := initReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, devicePluginController, directClient); err != nil {
+	if err := initReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, restartDevicePlugin, devicePlugin, directClient, logConfigController, nodeFeatureWriter, cdiSpecWriter); err != nil {
 		setupLog.WithError(err).Error("Fail to start Reconciler")
 		os.Exit(1)
 	}
@@ -186,12 +269,12 @@ func readVfioToken() (uuid.UUID, error) {
 	return vfioToken, nil
 }
 
-func initReconciler(mgr ctrl.Manager, drainHelper *drainhelper.DrainHelper, nodeNameRef types.NamespacedName, nodeConfigurer *daemon.NodeConfigurator, devicePluginController *daemon.DevicePluginController, directClient client.Client) error {
-	if err := initFecReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, devicePluginController, directClient); err != nil {
+func initReconciler(mgr ctrl.Manager, drainHelper *drainhelper.DrainHelper, nodeNameRef types.NamespacedName, nodeConfigurer *daemon.NodeConfigurator, restartDevicePlugin daemon.RestartDevicePluginFunction, devicePlugin *daemon.DevicePlugin, directClient client.Client, logConfigController *daemon.LogConfigController, nodeFeatureWriter *daemon.NodeFeatureWriter, cdiSpecWriter *daemon.CDISpecWriter) error {
+	if err := initFecReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, restartDevicePlugin, devicePlugin, directClient, logConfigController, nodeFeatureWriter, cdiSpecWriter); err != nil {
 		return fmt.Errorf("fail to start FEC Reconciler: %w", err)
 	}
 
-	if err := initVrbReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, devicePluginController, directClient); err != nil {
+	if err := initVrbReconciler(mgr, drainHelper, nodeNameRef, nodeConfigurer, restartDevicePlugin, devicePlugin, directClient, logConfigController, nodeFeatureWriter, cdiSpecWriter); err != nil {
 		return fmt.Errorf("fail to start VRB Reconciler: %w", err)
 	}
 
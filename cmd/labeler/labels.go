@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/jaypipes/ghw"
+)
+
+var (
+	sysBusPciDevices = "/sys/bus/pci/devices"
+
+	invalidLabelNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// acceleratorFamily is a family of accelerators described by a discovery config
+type acceleratorFamily struct {
+	name    string
+	cfgPath string
+}
+
+// discoveredFamily holds accelerators of the family found on the node
+type discoveredFamily struct {
+	acceleratorFamily
+	cfg     utils.AcceleratorDiscoveryConfig
+	devices []*ghw.PCIDevice
+}
+
+// model returns the name of the device model given by the discovery config, or the device ID when the name is empty
+func (f discoveredFamily) model(device *ghw.PCIDevice) string {
+	if model := f.cfg.Devices[device.Product.ID]; model != "" {
+		return model
+	}
+	return device.Product.ID
+}
+
+// inventoryEntry describes an accelerator in the inventory annotation of the node
+type inventoryEntry struct {
+	PciAddress   string `json:"pciAddress"`
+	Family       string `json:"family"`
+	DeviceID     string `json:"deviceID"`
+	Model        string `json:"model"`
+	NumaNode     *int   `json:"numaNode,omitempty"`
+	SriovCapable bool   `json:"sriovCapable"`
+}
+
+func (e inventoryEntry) String() string {
+	s := fmt.Sprintf("%s accelerator %s (%s) at %s", e.Family, e.Model, e.DeviceID, e.PciAddress)
+	if e.NumaNode != nil {
+		s += fmt.Sprintf(", NUMA node %d", *e.NumaNode)
+	}
+	if e.SriovCapable {
+		s += ", SR-IOV capable"
+	}
+	return s
+}
+
+// inventory lists accelerators of the discovered families ordered by their PCI address
+func inventory(families []discoveredFamily) []inventoryEntry {
+	entries := []inventoryEntry{}
+	for _, family := range families {
+		for _, device := range family.devices {
+			entry := inventoryEntry{
+				PciAddress:   device.Address,
+				Family:       family.name,
+				DeviceID:     device.Product.ID,
+				Model:        family.model(device),
+				SriovCapable: sriovCapable(device.Address),
+			}
+			if device.Node != nil {
+				numaNode := device.Node.ID
+				entry.NumaNode = &numaNode
+			}
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].PciAddress != entries[j].PciAddress {
+			return entries[i].PciAddress < entries[j].PciAddress
+		}
+		return entries[i].Family < entries[j].Family
+	})
+	return entries
+}
+
+// inventoryChanges returns accelerators which appeared in and disappeared from the current inventory; a swapped card
+// or a changed SR-IOV capability shows up as a removal of the former and an addition of the current accelerator
+func inventoryChanges(previous, current []inventoryEntry) (added, removed []inventoryEntry) {
+	contains := func(entries []inventoryEntry, entry inventoryEntry) bool {
+		for _, e := range entries {
+			if e.String() == entry.String() {
+				return true
+			}
+		}
+		return false
+	}
+	for _, entry := range current {
+		if !contains(previous, entry) {
+			added = append(added, entry)
+		}
+	}
+	for _, entry := range previous {
+		if !contains(current, entry) {
+			removed = append(removed, entry)
+		}
+	}
+	return added, removed
+}
+
+// nodeLabels computes labels describing accelerators of the discovered families
+func nodeLabels(families []discoveredFamily) map[string]string {
+	labels := map[string]string{}
+	counted := map[string]map[string]bool{}
+	numaNodes := map[int]bool{}
+
+	for _, family := range families {
+		if len(family.devices) == 0 {
+			continue
+		}
+		if family.cfg.NodeLabel != "" {
+			labels[family.cfg.NodeLabel] = ""
+		}
+		labels[utils.AcceleratorFamilyLabelPrefix+family.name] = "true"
+
+		for _, device := range family.devices {
+			label := utils.AcceleratorModelLabelPrefix + invalidLabelNameChars.ReplaceAllString(family.model(device), "_")
+			if counted[label] == nil {
+				counted[label] = map[string]bool{}
+			}
+			counted[label][device.Address] = true
+
+			if device.Node != nil {
+				numaNodes[device.Node.ID] = true
+			}
+			if sriovCapable(device.Address) {
+				labels[utils.SriovCapableLabel] = "true"
+			}
+		}
+	}
+
+	for label, devices := range counted {
+		labels[label] = strconv.Itoa(len(devices))
+	}
+
+	if len(numaNodes) > 0 {
+		ids := make([]int, 0, len(numaNodes))
+		for id := range numaNodes {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, strconv.Itoa(id))
+		}
+		labels[utils.AcceleratorNumaNodesLabel] = strings.Join(values, ".")
+	}
+	return labels
+}
+
+// staleLabel tells whether the label, or the inventory annotation, describes accelerators and is not among labels of
+// discovered accelerators; node labels of families which could not be discovered are kept
+func staleLabel(families []discoveredFamily, desired map[string]string) func(label string) bool {
+	managed := map[string]bool{}
+	if len(families) > 0 {
+		managed[utils.AcceleratorInventoryAnnotation] = true
+	}
+	for _, family := range families {
+		managed[utils.AcceleratorFamilyLabelPrefix+family.name] = true
+		if family.cfg.NodeLabel != "" {
+			managed[family.cfg.NodeLabel] = true
+		}
+	}
+	return func(label string) bool {
+		if _, ok := desired[label]; ok {
+			return false
+		}
+		if strings.HasPrefix(label, utils.AcceleratorFamilyLabelPrefix) {
+			return managed[label]
+		}
+		return managed[label] || utils.IsAcceleratorLabel(label)
+	}
+}
+
+// sriovCapable tells whether the device reports support of at least one VF
+func sriovCapable(pciAddress string) bool {
+	raw, err := os.ReadFile(filepath.Join(sysBusPciDevices, pciAddress, "sriov_totalvfs"))
+	if err != nil {
+		return false
+	}
+	totalVfs, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	return err == nil && totalVfs > 0
+}
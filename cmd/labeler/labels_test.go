@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/jaypipes/ghw"
+	"github.com/jaypipes/ghw/pkg/topology"
+	"github.com/jaypipes/pcidb"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("nodeLabels", func() {
+	const nodeLabel = "fpga.intel.com/intel-accelerator-present"
+
+	var (
+		fec, vrb      discoveredFamily
+		origPciDevice = sysBusPciDevices
+	)
+
+	device := func(address, productID string, numaNode int) *ghw.PCIDevice {
+		return &ghw.PCIDevice{Address: address, Product: &pcidb.Product{ID: productID}, Node: &topology.Node{ID: numaNode}}
+	}
+
+	BeforeEach(func() {
+		fec = discoveredFamily{
+			acceleratorFamily: acceleratorFamily{name: "fec"},
+			cfg:               utils.AcceleratorDiscoveryConfig{NodeLabel: nodeLabel, Devices: map[string]string{"0d5c": "ACC100", "0b32": ""}},
+		}
+		vrb = discoveredFamily{
+			acceleratorFamily: acceleratorFamily{name: "vrb"},
+			cfg:               utils.AcceleratorDiscoveryConfig{NodeLabel: nodeLabel, Devices: map[string]string{"57c2": "VRB2"}},
+		}
+		var err error
+		sysBusPciDevices, err = os.MkdirTemp("", "labeler")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(sysBusPciDevices)).To(Succeed())
+		sysBusPciDevices = origPciDevice
+	})
+
+	It("should label every detected family with counts of models and NUMA locality", func() {
+		fec.devices = []*ghw.PCIDevice{device("0000:17:00.0", "0d5c", 0), device("0000:b1:00.0", "0d5c", 1), device("0000:b2:00.0", "0b32", 1)}
+		vrb.devices = []*ghw.PCIDevice{device("0000:f7:00.0", "57c2", 1)}
+		Expect(os.MkdirAll(filepath.Join(sysBusPciDevices, "0000:f7:00.0"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sysBusPciDevices, "0000:f7:00.0", "sriov_totalvfs"), []byte("64\n"), 0600)).To(Succeed())
+
+		Expect(nodeLabels([]discoveredFamily{fec, vrb})).To(Equal(map[string]string{
+			nodeLabel: "",
+			utils.AcceleratorFamilyLabelPrefix + "fec":   "true",
+			utils.AcceleratorFamilyLabelPrefix + "vrb":   "true",
+			utils.AcceleratorModelLabelPrefix + "ACC100": "2",
+			utils.AcceleratorModelLabelPrefix + "0b32":   "1",
+			utils.AcceleratorModelLabelPrefix + "VRB2":   "1",
+			utils.AcceleratorNumaNodesLabel:              "0.1",
+			utils.SriovCapableLabel:                      "true",
+		}))
+	})
+
+	It("should not label nodes without accelerators", func() {
+		Expect(nodeLabels([]discoveredFamily{fec, vrb})).To(BeEmpty())
+	})
+
+	It("should consider labels of disappeared accelerators stale", func() {
+		vrb.devices = []*ghw.PCIDevice{device("0000:f7:00.0", "57c2", 0)}
+		families := []discoveredFamily{fec, vrb}
+		stale := staleLabel(families, nodeLabels(families))
+
+		Expect(stale(nodeLabel)).To(BeFalse())
+		Expect(stale(utils.AcceleratorFamilyLabelPrefix + "vrb")).To(BeFalse())
+		Expect(stale(utils.AcceleratorModelLabelPrefix + "VRB2")).To(BeFalse())
+		Expect(stale(utils.AcceleratorFamilyLabelPrefix + "fec")).To(BeTrue())
+		Expect(stale(utils.AcceleratorModelLabelPrefix + "ACC100")).To(BeTrue())
+		Expect(stale(utils.SriovCapableLabel)).To(BeTrue())
+		Expect(stale("kubernetes.io/hostname")).To(BeFalse())
+
+		stale = staleLabel([]discoveredFamily{fec}, nodeLabels([]discoveredFamily{fec}))
+		Expect(stale(nodeLabel)).To(BeTrue())
+		Expect(stale(utils.AcceleratorFamilyLabelPrefix + "vrb")).To(BeFalse())
+	})
+
+	It("should list accelerators in the inventory and report changes of the inventory", func() {
+		fec.devices = []*ghw.PCIDevice{device("0000:b1:00.0", "0d5c", 1), device("0000:17:00.0", "0d5c", 0)}
+		Expect(os.MkdirAll(filepath.Join(sysBusPciDevices, "0000:17:00.0"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sysBusPciDevices, "0000:17:00.0", "sriov_totalvfs"), []byte("16\n"), 0600)).To(Succeed())
+
+		previous := inventory([]discoveredFamily{fec, vrb})
+		Expect(previous).To(HaveLen(2))
+		Expect(previous[0].PciAddress).To(Equal("0000:17:00.0"))
+		Expect(previous[0].Model).To(Equal("ACC100"))
+		Expect(*previous[0].NumaNode).To(Equal(0))
+		Expect(previous[0].SriovCapable).To(BeTrue())
+		Expect(previous[1].PciAddress).To(Equal("0000:b1:00.0"))
+		Expect(previous[1].SriovCapable).To(BeFalse())
+
+		fec.devices = []*ghw.PCIDevice{device("0000:17:00.0", "0d5c", 0)}
+		vrb.devices = []*ghw.PCIDevice{device("0000:b1:00.0", "57c2", 1)}
+		current := inventory([]discoveredFamily{fec, vrb})
+
+		added, removed := inventoryChanges(previous, current)
+		Expect(added).To(ConsistOf(current[1]))
+		Expect(removed).To(ConsistOf(previous[1]))
+		Expect(added[0].String()).To(Equal("vrb accelerator VRB2 (57c2) at 0000:b1:00.0, NUMA node 1"))
+
+		added, removed = inventoryChanges(current, inventory([]discoveredFamily{fec, vrb}))
+		Expect(added).To(BeEmpty())
+		Expect(removed).To(BeEmpty())
+	})
+})
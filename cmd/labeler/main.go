@@ -5,8 +5,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"reflect"
+	"strconv"
+	"syscall"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -18,11 +25,23 @@ import (
 const (
 	configPath    = "/labeler-workspace/config/accelerators.json"
 	vrbconfigPath = "/labeler-workspace/config/accelerators_vrb.json"
+
+	// rescanIntervalEnv holds the interval of rediscovering accelerators; unset or 0s makes the labeler run once
+	rescanIntervalEnv = utils.SriovPrefix + "LABELER_RESCAN_INTERVAL"
+	// watchPciEventsEnv enables rediscovery on PCI device uevents of the kernel
+	watchPciEventsEnv = utils.SriovPrefix + "LABELER_WATCH_PCI_EVENTS"
+
+	pciEventSettlePeriod = 2 * time.Second
 )
 
 var getInclusterConfigFunc = rest.InClusterConfig
 
-func setNodeLabel(nodeName, label string, removeLabel bool) error {
+// errNodeTornDown is returned when accelerators of the node were torn down and the node must be left unlabeled
+var errNodeTornDown = errors.New("accelerators of the node were torn down")
+
+// setNodeLabels sets given labels and annotations of the node and removes its stale labels and annotations;
+// nodes torn down by the daemon are left untouched until the operator clears their teardown mark
+func setNodeLabels(nodeName string, labels, annotations map[string]string, stale func(key string) bool) error {
 	cfg, err := getInclusterConfigFunc()
 	if err != nil {
 		return fmt.Errorf("failed to get cluster config: %v", err.Error())
@@ -36,14 +55,16 @@ func setNodeLabel(nodeName, label string, removeLabel bool) error {
 	if err != nil {
 		return fmt.Errorf("failed to get the node object: %v", err)
 	}
-	nodeLabels := node.GetLabels()
-	if removeLabel {
-		delete(nodeLabels, label)
-	} else {
-		nodeLabels[label] = ""
-
+	if _, tornDown := node.GetAnnotations()[utils.TeardownCompletedAnnotation]; tornDown {
+		return errNodeTornDown
+	}
+	nodeLabels, labelsChanged := updateKeys(node.GetLabels(), labels, stale)
+	nodeAnnotations, annotationsChanged := updateKeys(node.GetAnnotations(), annotations, stale)
+	if !labelsChanged && !annotationsChanged {
+		return nil
 	}
 	node.SetLabels(nodeLabels)
+	node.SetAnnotations(nodeAnnotations)
 	_, err = cli.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to update the node object: %v", err)
@@ -51,34 +72,174 @@ func setNodeLabel(nodeName, label string, removeLabel bool) error {
 	return nil
 }
 
-func acceleratorDiscovery(cfgPath string, vrbCfgPath string) error {
+// updateKeys removes stale keys which are not desired and sets desired values; returns whether anything changed
+func updateKeys(current, desired map[string]string, stale func(key string) bool) (map[string]string, bool) {
+	if current == nil {
+		current = map[string]string{}
+	}
+	changed := false
+	for key := range current {
+		if _, ok := desired[key]; !ok && stale != nil && stale(key) {
+			fmt.Printf("Removing stale %s\n", key)
+			delete(current, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			current[key] = value
+			changed = true
+		}
+	}
+	return current, changed
+}
 
-	fecAccFound, fecNodeLabel, err1 := utils.FindAccelerator(cfgPath)
-	vrbAccFound, vrbNodeLabel, err2 := utils.FindAccelerator(vrbCfgPath)
+// labeler keeps labels and the inventory annotation of the node in sync with accelerators found on the node
+type labeler struct {
+	cfgPath    string
+	vrbCfgPath string
 
-	if err1 != nil && err2 != nil {
-		return fmt.Errorf("failed to find accelerator: %v %v", err1, err2)
+	// inventory holds accelerators found by the previous discovery
+	inventory []inventoryEntry
+	// labels and annotations hold what was set on the node by the previous successful update
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func (l *labeler) discover() error {
+	var discovered []discoveredFamily
+	var errs []error
+	for _, family := range []acceleratorFamily{{name: "fec", cfgPath: l.cfgPath}, {name: "vrb", cfgPath: l.vrbCfgPath}} {
+		cfg, devices, err := utils.FindAcceleratorDevices(family.cfgPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		discovered = append(discovered, discoveredFamily{acceleratorFamily: family, cfg: cfg, devices: devices})
+	}
+
+	if len(discovered) == 0 {
+		return fmt.Errorf("failed to find accelerator: %v", errs)
 	}
 	nodeName := os.Getenv("NODENAME")
 	if nodeName == "" {
 		return fmt.Errorf("NODENAME environment variable is empty")
 	}
 
-	nodeLabel := ""
-	if fecAccFound {
-		nodeLabel = fecNodeLabel
-	} else if vrbAccFound {
-		nodeLabel = vrbNodeLabel
+	current := inventory(discovered)
+	if l.inventory != nil {
+		added, removed := inventoryChanges(l.inventory, current)
+		for _, entry := range removed {
+			fmt.Printf("Accelerator removed: %s\n", entry)
+		}
+		for _, entry := range added {
+			fmt.Printf("Accelerator added: %s\n", entry)
+		}
 	}
+	l.inventory = current
 
-	return setNodeLabel(nodeName, nodeLabel, !(fecAccFound || vrbAccFound))
+	labels := nodeLabels(discovered)
+	annotations := map[string]string{}
+	if len(current) > 0 {
+		raw, err := json.Marshal(current)
+		if err != nil {
+			return fmt.Errorf("failed to marshal accelerator inventory: %v", err)
+		}
+		annotations[utils.AcceleratorInventoryAnnotation] = string(raw)
+	}
+	if l.labels != nil && reflect.DeepEqual(labels, l.labels) && reflect.DeepEqual(annotations, l.annotations) {
+		return nil
+	}
+
+	fmt.Printf("Accelerator labels: %v\n", labels)
+	if err := setNodeLabels(nodeName, labels, annotations, staleLabel(discovered, labels)); err != nil {
+		if errors.Is(err, errNodeTornDown) {
+			fmt.Printf("Skipping labeling: %v\n", err)
+			return nil
+		}
+		return err
+	}
+	l.labels, l.annotations = labels, annotations
+	return nil
+}
+
+// run rediscovers accelerators every interval and shortly after PCI device events until the context is done
+func (l *labeler) run(ctx context.Context, interval time.Duration, pciEvents <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-pciEvents:
+			if !ok {
+				fmt.Printf("Watching PCI device events stopped, rediscovering every %v\n", interval)
+				pciEvents = nil
+				continue
+			}
+			// let the kernel and drivers settle after hotplug before rescanning
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pciEventSettlePeriod):
+			}
+		}
+		if err := l.discover(); err != nil {
+			fmt.Printf("Accelerator discovery failed: %v\n", err)
+		}
+	}
+}
+
+func acceleratorDiscovery(cfgPath string, vrbCfgPath string) error {
+	return (&labeler{cfgPath: cfgPath, vrbCfgPath: vrbCfgPath}).discover()
+}
+
+// rediscoveryConfig reads the interval of rediscovery, 0 when the labeler runs once, and whether PCI device events
+// trigger rediscovery
+func rediscoveryConfig() (time.Duration, bool, error) {
+	interval := time.Duration(0)
+	if value := os.Getenv(rescanIntervalEnv); value != "" {
+		var err error
+		if interval, err = time.ParseDuration(value); err != nil || interval < 0 {
+			return 0, false, fmt.Errorf("invalid %s %q", rescanIntervalEnv, value)
+		}
+	}
+	watch := false
+	if value := os.Getenv(watchPciEventsEnv); value != "" {
+		var err error
+		if watch, err = strconv.ParseBool(value); err != nil {
+			return 0, false, fmt.Errorf("invalid %s %q", watchPciEventsEnv, value)
+		}
+	}
+	return interval, watch, nil
 }
 
 func main() {
-	if err := acceleratorDiscovery(configPath, vrbconfigPath); err != nil {
+	interval, watch, err := rediscoveryConfig()
+	if err != nil {
 		fmt.Printf("Accelerator discovery failed: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("Accelerator discovery finished successfully\n")
-	os.Exit(0)
+
+	l := &labeler{cfgPath: configPath, vrbCfgPath: vrbconfigPath}
+	if err := l.discover(); err != nil {
+		fmt.Printf("Accelerator discovery failed: %v\n", err)
+		os.Exit(1)
+	}
+	if interval == 0 {
+		fmt.Printf("Accelerator discovery finished successfully\n")
+		os.Exit(0)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var pciEvents <-chan struct{}
+	if watch {
+		if pciEvents, err = watchPciEvents(ctx); err != nil {
+			fmt.Printf("Failed to watch PCI device events, rediscovering every %v: %v\n", interval, err)
+		}
+	}
+	fmt.Printf("Accelerator discovery finished successfully, rediscovering every %v\n", interval)
+	l.run(ctx, interval, pciEvents)
 }
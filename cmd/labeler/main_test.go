@@ -149,7 +149,7 @@ var _ = Describe("Labeler", func() {
 		})
 	})
 	*/
-	var _ = Describe("setNodeLabel", func() {
+	var _ = Describe("setNodeLabels", func() {
 		BeforeEach(func() {
 			fakeGetInclusterConfigReturn = nil
 			getInclusterConfigFunc = fakeGetInclusterConfig
@@ -178,17 +178,56 @@ var _ = Describe("Labeler", func() {
 		})
 		var _ = It("will fail if there is no cluster", func() {
 			fakeGetInclusterConfigReturn = fmt.Errorf("error")
-			err := setNodeLabel("", "", false)
+			err := setNodeLabels("", nil, nil, nil)
 			Expect(err).To(HaveOccurred())
 		})
 		var _ = It("will fail whene update node failes, empty label name", func() {
-			err := setNodeLabel("nodename", "", false)
+			err := setNodeLabels("nodename", map[string]string{"": ""}, nil, nil)
 			Expect(err).To(HaveOccurred())
 		})
 		var _ = It("will pass if there is cluster", func() {
-			err := setNodeLabel("nodename", "testlabel", false)
+			err := setNodeLabels("nodename", map[string]string{"testlabel": ""}, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
+		var _ = It("will remove stale labels", func() {
+			err := setNodeLabels("nodename", map[string]string{"testlabel": ""}, nil, func(label string) bool {
+				return label == "fpga.intel.com/intel-accelerator-present"
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := &corev1.Node{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "nodename"}, updated)).To(Succeed())
+			Expect(updated.Labels).To(HaveKey("testlabel"))
+			Expect(updated.Labels).ToNot(HaveKey("fpga.intel.com/intel-accelerator-present"))
+		})
+		var _ = It("will set and remove stale annotations", func() {
+			err := setNodeLabels("nodename", nil, map[string]string{utils.AcceleratorInventoryAnnotation: "[]"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := &corev1.Node{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "nodename"}, updated)).To(Succeed())
+			Expect(updated.Annotations).To(HaveKeyWithValue(utils.AcceleratorInventoryAnnotation, "[]"))
+
+			err = setNodeLabels("nodename", nil, nil, func(key string) bool {
+				return key == utils.AcceleratorInventoryAnnotation
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "nodename"}, updated)).To(Succeed())
+			Expect(updated.Annotations).ToNot(HaveKey(utils.AcceleratorInventoryAnnotation))
+			Expect(updated.Labels).To(HaveKey("fpga.intel.com/intel-accelerator-present"))
+		})
+		var _ = It("will leave torn down nodes untouched", func() {
+			updated := &corev1.Node{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "nodename"}, updated)).To(Succeed())
+			updated.Annotations = map[string]string{utils.TeardownCompletedAnnotation: "config-uid"}
+			Expect(k8sClient.Update(context.TODO(), updated)).To(Succeed())
+
+			err := setNodeLabels("nodename", map[string]string{"testlabel": ""}, nil, nil)
+			Expect(err).To(MatchError(errNodeTornDown))
+
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "nodename"}, updated)).To(Succeed())
+			Expect(updated.Labels).ToNot(HaveKey("testlabel"))
+		})
 	})
 	var _ = Describe("acceleratorDiscovery", func() {
 		BeforeEach(func() {
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// pciEventActions are actions of PCI device uevents which may change accelerators of the node
+var pciEventActions = map[string]bool{"add": true, "remove": true, "change": true, "bind": true, "unbind": true}
+
+// parseUevent returns the action and the subsystem of a kernel uevent message,
+// e.g. "add@/devices/pci0000:00/0000:00:01.0\x00ACTION=add\x00SUBSYSTEM=pci\x00..."
+func parseUevent(msg []byte) (action, subsystem string) {
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		switch {
+		case bytes.HasPrefix(field, []byte("ACTION=")):
+			action = string(field[len("ACTION="):])
+		case bytes.HasPrefix(field, []byte("SUBSYSTEM=")):
+			subsystem = string(field[len("SUBSYSTEM="):])
+		}
+	}
+	return action, subsystem
+}
+
+// watchPciEvents listens to kernel uevents and signals PCI device events on the returned channel; events arriving
+// before the previous one is consumed are coalesced. The channel is closed when listening fails or the context is done.
+func watchPciEvents(ctx context.Context) (<-chan struct{}, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open netlink socket: %v", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind netlink socket: %v", err)
+	}
+	// receive with timeout to notice the context is done
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to set netlink socket timeout: %v", err)
+	}
+
+	events := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case events <- struct{}{}:
+		default:
+		}
+	}
+	go func() {
+		defer close(events)
+		defer unix.Close(fd)
+		buf := make([]byte, 64*1024)
+		for ctx.Err() == nil {
+			n, _, err := unix.Recvfrom(fd, buf, 0)
+			switch {
+			case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+				continue
+			case errors.Is(err, unix.ENOBUFS):
+				// events were dropped, some of them may have concerned PCI devices
+				notify()
+				continue
+			case err != nil:
+				fmt.Printf("Failed to receive uevent: %v\n", err)
+				return
+			}
+			if action, subsystem := parseUevent(buf[:n]); subsystem == "pci" && pciEventActions[action] {
+				fmt.Printf("PCI device %s event received\n", action)
+				notify()
+			}
+		}
+	}()
+	return events, nil
+}
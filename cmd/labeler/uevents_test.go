@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseUevent", func() {
+	It("should return the action and the subsystem of the uevent", func() {
+		action, subsystem := parseUevent([]byte("remove@/devices/pci0000:b0/0000:b0:02.0/0000:b1:00.0\x00ACTION=remove\x00" +
+			"DEVPATH=/devices/pci0000:b0/0000:b0:02.0/0000:b1:00.0\x00SUBSYSTEM=pci\x00PCI_ID=8086:0D5C\x00SEQNUM=4321\x00"))
+		Expect(action).To(Equal("remove"))
+		Expect(subsystem).To(Equal("pci"))
+	})
+
+	It("should return empty values of malformed uevents", func() {
+		action, subsystem := parseUevent([]byte("libudev"))
+		Expect(action).To(BeEmpty())
+		Expect(subsystem).To(BeEmpty())
+	})
+})
@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+)
+
+const devicePluginPodLabel = "sriov-device-plugin-daemonset"
+
+// DevicePluginConfigReconciler generates node specific configs of sriov-network-device-plugin in sriovdp-config,
+// so that VFs of PFs with custom resource names requested by SriovFecClusterConfigs and SriovVrbClusterConfigs
+// are served under these names; requests are named after nodes
+type DevicePluginConfigReconciler struct {
+	client.Client
+	Log *logrus.Logger
+}
+
+// +kubebuilder:rbac:groups=sriovfec.intel.com,resources=sriovfecnodeconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sriovvrb.intel.com,resources=sriovvrbnodeconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list;delete
+
+/*****************************************************************************
+ * Method: DevicePluginConfigReconciler::Reconcile
+ * Description: Generates config_<node>.json of the node from config.json and
+ * 		resource names of its node configs; the entry is removed when no
+ * 		resource name is requested. sriov-device-plugin of the node is
+ * 		restarted when its config changes. sriovdp-config annotated as
+ * 		unmanaged is left intact
+ ****************************************************************************/
+func (r *DevicePluginConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithField("node", req.Name)
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deviceplugin.ConfigMapName, Namespace: NAMESPACE}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			log.Debug("sriovdp-config is not deployed yet")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if cm.Annotations[utils.UnmanagedAnnotation] == "true" {
+		log.Debug("sriovdp-config is unmanaged, node specific config is not generated")
+		return ctrl.Result{}, nil
+	}
+
+	pinned, err := r.pinnedResources(ctx, req.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, err := nodeDevicePluginConfig(cm, pinned, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	key := deviceplugin.NodeConfigKey(req.Name)
+	current, exists := cm.Data[key]
+	if current == desired && exists == (desired != "") {
+		return ctrl.Result{}, nil
+	}
+
+	if desired == "" {
+		delete(cm.Data, key)
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = desired
+	}
+	if err := r.Update(ctx, cm); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.WithField("key", key).WithField("resources", len(pinned)).Info("node specific device plugin config updated")
+
+	return ctrl.Result{}, r.restartDevicePlugin(ctx, req.Name)
+}
+
+// nodeDevicePluginConfig returns the node specific config serving pinned resources; empty when there are none
+func nodeDevicePluginConfig(cm *corev1.ConfigMap, pinned []deviceplugin.PinnedResource, log *logrus.Entry) (string, error) {
+	if len(pinned) == 0 {
+		return "", nil
+	}
+
+	data, ok := cm.Data[deviceplugin.CommonConfigKey]
+	if !ok {
+		return "", fmt.Errorf("%s not found in %s", deviceplugin.CommonConfigKey, deviceplugin.ConfigMapName)
+	}
+	common, err := deviceplugin.Parse(data)
+	if err != nil {
+		return "", err
+	}
+
+	config, unresolved := deviceplugin.NodeConfig(common, pinned)
+	if len(unresolved) > 0 {
+		log.WithField("resourceNames", unresolved).Warn("no resource of config.json serves VFs of PFs with custom resource names")
+	}
+	if len(unresolved) == len(pinned) {
+		return "", nil
+	}
+	return config.Marshal()
+}
+
+// pinnedResources returns custom resource names of PFs and their VF groups of both node configs of the node which already expose VFs
+func (r *DevicePluginConfigReconciler) pinnedResources(ctx context.Context, nodeName string) ([]deviceplugin.PinnedResource, error) {
+	key := types.NamespacedName{Name: nodeName, Namespace: NAMESPACE}
+
+	fnc := &sriovfecv2.SriovFecNodeConfig{}
+	if err := r.Get(ctx, key, fnc); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	vnc := &vrbv1.SriovVrbNodeConfig{}
+	if err := r.Get(ctx, key, vnc); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return append(fecPinnedResources(fnc), vrbPinnedResources(vnc)...), nil
+}
+
+func fecPinnedResources(nc *sriovfecv2.SriovFecNodeConfig) []deviceplugin.PinnedResource {
+	var pinned []deviceplugin.PinnedResource
+	for _, pf := range nc.Spec.PhysicalFunctions {
+		if pf.ResourceName == "" && len(pf.VFGroups) == 0 {
+			continue
+		}
+		for _, acc := range nc.Status.Inventory.SriovAccelerators {
+			if acc.PCIAddress != pf.PCIAddress || len(acc.VFs) == 0 {
+				continue
+			}
+			group := newPinnedResourceGroup(acc.PCIAddress, acc.VFs[0].DeviceID)
+			for _, vf := range acc.VFs {
+				name := pf.ResourceName
+				// the daemon binds VFs of unknown index to the default VF driver, so they stay in the default resource
+				if vf.Index != nil {
+					if g := pf.VFGroupOf(*vf.Index); g != nil {
+						name = g.ResourceName
+					}
+				}
+				group.add(name, vf.PCIAddress)
+			}
+			pinned = append(pinned, group.resources...)
+		}
+	}
+	return pinned
+}
+
+func vrbPinnedResources(nc *vrbv1.SriovVrbNodeConfig) []deviceplugin.PinnedResource {
+	var pinned []deviceplugin.PinnedResource
+	for _, pf := range nc.Spec.PhysicalFunctions {
+		if pf.VrbResourceName == "" && len(pf.VFGroups) == 0 {
+			continue
+		}
+		for _, acc := range nc.Status.Inventory.SriovAccelerators {
+			if acc.PCIAddress != pf.PCIAddress || len(acc.VFs) == 0 {
+				continue
+			}
+			group := newPinnedResourceGroup(acc.PCIAddress, acc.VFs[0].DeviceID)
+			for _, vf := range acc.VFs {
+				name := pf.VrbResourceName
+				// the daemon binds VFs of unknown index to the default VF driver, so they stay in the default resource
+				if vf.Index != nil {
+					if g := pf.VFGroupOf(*vf.Index); g != nil {
+						name = g.ResourceName
+					}
+				}
+				group.add(name, vf.PCIAddress)
+			}
+			pinned = append(pinned, group.resources...)
+		}
+	}
+	return pinned
+}
+
+// pinnedResourceGroup collects resources VFs of one PF are split into, in order of their first VF
+type pinnedResourceGroup struct {
+	pfAddress  string
+	vfDeviceID string
+	resources  []deviceplugin.PinnedResource
+}
+
+func newPinnedResourceGroup(pfAddress, vfDeviceID string) *pinnedResourceGroup {
+	return &pinnedResourceGroup{pfAddress: pfAddress, vfDeviceID: vfDeviceID}
+}
+
+// add pins the VF to the named resource; VFs without resource name stay served by the common resource
+func (g *pinnedResourceGroup) add(resourceName, vfAddress string) {
+	if resourceName == "" {
+		return
+	}
+	for i := range g.resources {
+		if g.resources[i].ResourceName == resourceName {
+			g.resources[i].VFs = append(g.resources[i].VFs, vfAddress)
+			return
+		}
+	}
+	g.resources = append(g.resources, deviceplugin.PinnedResource{
+		ResourceName: resourceName, PFAddress: g.pfAddress, VFDeviceID: g.vfDeviceID, VFs: []string{vfAddress},
+	})
+}
+
+// restartDevicePlugin deletes sriov-device-plugin pods of the node, so that they read the changed config
+func (r *DevicePluginConfigReconciler) restartDevicePlugin(ctx context.Context, nodeName string) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(NAMESPACE), client.MatchingLabels{"app": devicePluginPodLabel}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Spec.NodeName != nodeName {
+			continue
+		}
+		if err := r.Delete(ctx, &pods.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Log.WithField("node", nodeName).WithField("pod", pods.Items[i].Name).Info("sriov-device-plugin restarted")
+	}
+	return nil
+}
+
+// devicePluginConfigToNodes maps sriovdp-config to nodes having node configs, so that entries follow changes of config.json
+func (r *DevicePluginConfigReconciler) devicePluginConfigToNodes(obj client.Object) []reconcile.Request {
+	if obj.GetName() != deviceplugin.ConfigMapName || obj.GetNamespace() != NAMESPACE {
+		return nil
+	}
+
+	names := map[string]bool{}
+	fncs := &sriovfecv2.SriovFecNodeConfigList{}
+	if err := r.List(context.TODO(), fncs, client.InNamespace(NAMESPACE)); err != nil {
+		r.Log.WithError(err).Error("failed to list SriovFecNodeConfigs")
+	}
+	for _, nc := range fncs.Items {
+		names[nc.Name] = true
+	}
+	vncs := &vrbv1.SriovVrbNodeConfigList{}
+	if err := r.List(context.TODO(), vncs, client.InNamespace(NAMESPACE)); err != nil {
+		r.Log.WithError(err).Error("failed to list SriovVrbNodeConfigs")
+	}
+	for _, nc := range vncs.Items {
+		names[nc.Name] = true
+	}
+
+	requests := make([]reconcile.Request, 0, len(names))
+	for name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: NAMESPACE}})
+	}
+	return requests
+}
+
+func (r *DevicePluginConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("deviceplugin-config").
+		For(&sriovfecv2.SriovFecNodeConfig{}).
+		Watches(&source.Kind{Type: &vrbv1.SriovVrbNodeConfig{}}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.devicePluginConfigToNodes),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == deviceplugin.ConfigMapName
+			}))).
+		Complete(r)
+}
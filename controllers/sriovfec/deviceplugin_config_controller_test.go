@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DevicePluginConfigReconciler", func() {
+	const common = `{"resourceList": [
+		{"resourceName": "intel_fec_acc100", "selectors": {"vendors": ["8086"], "devices": ["0d5d"]}},
+		{"resourceName": "intel_vrb_vrb1", "selectors": {"vendors": ["8086"], "devices": ["57c1"]}}
+	]}`
+
+	var (
+		r        *DevicePluginConfigReconciler
+		fecNC    *sriovv2.SriovFecNodeConfig
+		vrbNC    *vrbv1.SriovVrbNodeConfig
+		dpConfig *corev1.ConfigMap
+	)
+
+	reconcileNode := func() {
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "worker", Namespace: NAMESPACE}})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	nodeConfig := func() (deviceplugin.Config, bool) {
+		cm := &corev1.ConfigMap{}
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(dpConfig), cm)).To(Succeed())
+		data, ok := cm.Data[deviceplugin.NodeConfigKey("worker")]
+		if !ok {
+			return deviceplugin.Config{}, false
+		}
+		config, err := deviceplugin.Parse(data)
+		Expect(err).ToNot(HaveOccurred())
+		return config, true
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		Expect(vrbv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		fecNC = &sriovv2.SriovFecNodeConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "worker", Namespace: NAMESPACE},
+			Spec: sriovv2.SriovFecNodeConfigSpec{PhysicalFunctions: []sriovv2.PhysicalFunctionConfigExt{
+				{PCIAddress: "0000:b2:00.0", VFAmount: 2, ResourceName: "fec_low_latency"},
+				{PCIAddress: "0000:c2:00.0", VFAmount: 1},
+			}},
+			Status: sriovv2.SriovFecNodeConfigStatus{Inventory: sriovv2.NodeInventory{SriovAccelerators: []sriovv2.SriovAccelerator{
+				{PCIAddress: "0000:b2:00.0", VFs: []sriovv2.VF{{PCIAddress: "0000:b3:00.0", DeviceID: "0d5d"}, {PCIAddress: "0000:b3:00.1", DeviceID: "0d5d"}}},
+				{PCIAddress: "0000:c2:00.0", VFs: []sriovv2.VF{{PCIAddress: "0000:c3:00.0", DeviceID: "0d5d"}}},
+			}}},
+		}
+		vrbNC = &vrbv1.SriovVrbNodeConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "worker", Namespace: NAMESPACE},
+			Spec: vrbv1.SriovVrbNodeConfigSpec{PhysicalFunctions: []vrbv1.PhysicalFunctionConfigExt{
+				{PCIAddress: "0000:f7:00.0", VFAmount: 1, VrbResourceName: "vrb_cell"},
+			}},
+			Status: vrbv1.SriovVrbNodeConfigStatus{Inventory: vrbv1.NodeInventory{SriovAccelerators: []vrbv1.SriovAccelerator{
+				{PCIAddress: "0000:f7:00.0", VFs: []vrbv1.VF{{PCIAddress: "0000:f7:00.1", DeviceID: "57c1"}}},
+			}}},
+		}
+		dpConfig = &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: deviceplugin.ConfigMapName, Namespace: NAMESPACE},
+			Data:       map[string]string{deviceplugin.CommonConfigKey: common},
+		}
+
+		r = &DevicePluginConfigReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(fecNC, vrbNC, dpConfig,
+				&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "dp-worker", Namespace: NAMESPACE, Labels: map[string]string{"app": devicePluginPodLabel}},
+					Spec: corev1.PodSpec{NodeName: "worker"}},
+				&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "dp-other", Namespace: NAMESPACE, Labels: map[string]string{"app": devicePluginPodLabel}},
+					Spec: corev1.PodSpec{NodeName: "other"}},
+			).Build(),
+			Log: utils.NewLogger(),
+		}
+	})
+
+	It("should generate the node config from resource names of both node configs and restart the device plugin of the node", func() {
+		reconcileNode()
+
+		config, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+		var names []string
+		for _, resource := range config.ResourceList {
+			names = append(names, resource.ResourceName)
+		}
+		Expect(names).To(Equal([]string{"fec_low_latency", "vrb_cell", "intel_fec_acc100", "intel_vrb_vrb1"}))
+		Expect(config.ResourceList[0].Selectors.PciAddresses).To(Equal([]string{"0000:b3:00.0", "0000:b3:00.1"}))
+		Expect(config.ResourceList[0].PFAddress()).To(Equal("0000:b2:00.0"))
+		Expect(config.ResourceList[1].Selectors.PciAddresses).To(Equal([]string{"0000:f7:00.1"}))
+
+		err := r.Get(context.TODO(), client.ObjectKey{Name: "dp-worker", Namespace: NAMESPACE}, &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(r.Get(context.TODO(), client.ObjectKey{Name: "dp-other", Namespace: NAMESPACE}, &corev1.Pod{})).To(Succeed())
+	})
+
+	It("should split VFs of a PF into resources of its VF groups", func() {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(fecNC), fecNC)).To(Succeed())
+		fecNC.Spec.PhysicalFunctions[1].VFAmount = 4
+		fecNC.Spec.PhysicalFunctions[1].VFGroups = []sriovv2.VFGroup{
+			{First: 0, Last: 1, ResourceName: "intel_fec_du_a"},
+			{First: 2, Last: 2, ResourceName: "intel_fec_du_b"},
+		}
+		Expect(r.Update(context.TODO(), fecNC)).To(Succeed())
+		fecNC.Status.Inventory.SriovAccelerators[1].VFs = []sriovv2.VF{
+			{PCIAddress: "0000:c3:00.0", DeviceID: "0d5d", Index: pointer.Int(0)},
+			{PCIAddress: "0000:c3:00.1", DeviceID: "0d5d", Index: pointer.Int(1)},
+			{PCIAddress: "0000:c3:00.2", DeviceID: "0d5d", Index: pointer.Int(2)},
+			{PCIAddress: "0000:c3:00.3", DeviceID: "0d5d", Index: pointer.Int(3)},
+		}
+		Expect(r.Status().Update(context.TODO(), fecNC)).To(Succeed())
+		Expect(r.Delete(context.TODO(), vrbNC)).To(Succeed())
+
+		reconcileNode()
+
+		config, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+		Expect(config.ResourceList).To(HaveLen(5))
+		Expect(config.ResourceList[1].ResourceName).To(Equal("intel_fec_du_a"))
+		Expect(config.ResourceList[1].Selectors.PciAddresses).To(Equal([]string{"0000:c3:00.0", "0000:c3:00.1"}))
+		Expect(config.ResourceList[1].PFAddress()).To(Equal("0000:c2:00.0"))
+		Expect(config.ResourceList[2].ResourceName).To(Equal("intel_fec_du_b"))
+		Expect(config.ResourceList[2].Selectors.PciAddresses).To(Equal([]string{"0000:c3:00.2"}))
+		Expect(config.ResourceList[3].ResourceName).To(Equal("intel_fec_acc100"), "VFs out of groups stay in the common resource")
+	})
+
+	It("should keep VFs of unknown index in the common resource", func() {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(fecNC), fecNC)).To(Succeed())
+		fecNC.Spec.PhysicalFunctions[1].VFAmount = 2
+		fecNC.Spec.PhysicalFunctions[1].VFGroups = []sriovv2.VFGroup{{First: 0, Last: 0, ResourceName: "intel_fec_du_a"}}
+		Expect(r.Update(context.TODO(), fecNC)).To(Succeed())
+		fecNC.Status.Inventory.SriovAccelerators[1].VFs = []sriovv2.VF{
+			{PCIAddress: "0000:c3:00.0", DeviceID: "0d5d"},
+			{PCIAddress: "0000:c3:00.1", DeviceID: "0d5d", Index: pointer.Int(0)},
+		}
+		Expect(r.Status().Update(context.TODO(), fecNC)).To(Succeed())
+		Expect(r.Delete(context.TODO(), vrbNC)).To(Succeed())
+
+		reconcileNode()
+
+		config, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+		var names []string
+		for _, resource := range config.ResourceList {
+			names = append(names, resource.ResourceName)
+		}
+		Expect(names).To(Equal([]string{"fec_low_latency", "intel_fec_du_a", "intel_fec_acc100", "intel_vrb_vrb1"}))
+		Expect(config.ResourceList[1].Selectors.PciAddresses).To(Equal([]string{"0000:c3:00.1"}), "VF of unknown index is not pinned to a VF group")
+	})
+
+	It("should remove the node config when no resource name is requested", func() {
+		reconcileNode()
+		_, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(fecNC), fecNC)).To(Succeed())
+		fecNC.Spec.PhysicalFunctions[0].ResourceName = ""
+		Expect(r.Update(context.TODO(), fecNC)).To(Succeed())
+		Expect(r.Delete(context.TODO(), vrbNC)).To(Succeed())
+
+		reconcileNode()
+		_, ok = nodeConfig()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should wait for VFs of PFs with resource names", func() {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(fecNC), fecNC)).To(Succeed())
+		fecNC.Status.Inventory.SriovAccelerators[0].VFs = nil
+		Expect(r.Status().Update(context.TODO(), fecNC)).To(Succeed())
+		Expect(r.Delete(context.TODO(), vrbNC)).To(Succeed())
+
+		reconcileNode()
+		_, ok := nodeConfig()
+		Expect(ok).To(BeFalse())
+		Expect(r.Get(context.TODO(), client.ObjectKey{Name: "dp-worker", Namespace: NAMESPACE}, &corev1.Pod{})).To(Succeed())
+	})
+
+	It("should leave unmanaged sriovdp-config intact", func() {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(dpConfig), dpConfig)).To(Succeed())
+		dpConfig.Annotations = map[string]string{utils.UnmanagedAnnotation: "true"}
+		Expect(r.Update(context.TODO(), dpConfig)).To(Succeed())
+
+		reconcileNode()
+		_, ok := nodeConfig()
+		Expect(ok).To(BeFalse())
+
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(dpConfig), dpConfig)).To(Succeed())
+		delete(dpConfig.Annotations, utils.UnmanagedAnnotation)
+		Expect(r.Update(context.TODO(), dpConfig)).To(Succeed())
+
+		reconcileNode()
+		_, ok = nodeConfig()
+		Expect(ok).To(BeTrue(), "node config is generated once the annotation is removed")
+	})
+
+	It("should rename an existing VRB resource in place", func() {
+		reconcileNode()
+		Expect(r.Create(context.TODO(), &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "dp-worker", Namespace: NAMESPACE,
+			Labels: map[string]string{"app": devicePluginPodLabel}}, Spec: corev1.PodSpec{NodeName: "worker"}})).To(Succeed())
+
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(vrbNC), vrbNC)).To(Succeed())
+		vrbNC.Spec.PhysicalFunctions[0].VrbResourceName = "vrb_ran"
+		Expect(r.Update(context.TODO(), vrbNC)).To(Succeed())
+
+		reconcileNode()
+
+		config, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+		var names []string
+		for _, resource := range config.ResourceList {
+			names = append(names, resource.ResourceName)
+		}
+		Expect(names).To(Equal([]string{"fec_low_latency", "vrb_ran", "intel_fec_acc100", "intel_vrb_vrb1"}))
+		Expect(config.ResourceList[1].Selectors.PciAddresses).To(Equal([]string{"0000:f7:00.1"}))
+		Expect(config.ResourceList[1].PFAddress()).To(Equal("0000:f7:00.0"))
+
+		err := r.Get(context.TODO(), client.ObjectKey{Name: "dp-worker", Namespace: NAMESPACE}, &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should replace a VRB resource renamed in config.json by previous versions of the daemon", func() {
+		const renamed = `{"resourceList": [
+			{"resourceName": "intel_fec_acc100", "selectors": {"vendors": ["8086"], "devices": ["0d5d"]}},
+			{"resourceName": "vrb_cell", "selectors": {"vendors": ["8086"], "devices": ["57c1"], "pciAddresses": ["0000:f7:00.1"]},
+				"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:f7:00.0"}}}
+		]}`
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(dpConfig), dpConfig)).To(Succeed())
+		dpConfig.Data[deviceplugin.CommonConfigKey] = renamed
+		Expect(r.Update(context.TODO(), dpConfig)).To(Succeed())
+
+		reconcileNode()
+
+		config, ok := nodeConfig()
+		Expect(ok).To(BeTrue())
+		var names []string
+		for _, resource := range config.ResourceList {
+			names = append(names, resource.ResourceName)
+		}
+		Expect(names).To(Equal([]string{"fec_low_latency", "vrb_cell", "intel_fec_acc100"}))
+		Expect(config.ResourceList[1].Selectors.PciAddresses).To(Equal([]string{"0000:f7:00.1"}))
+		Expect(config.ResourceList[1].PFAddress()).To(Equal("0000:f7:00.0"))
+
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(dpConfig), dpConfig)).To(Succeed())
+		Expect(dpConfig.Data[deviceplugin.CommonConfigKey]).To(Equal(renamed), "config.json is not modified")
+	})
+
+	It("should not generate the node config when no resource of config.json serves the VFs", func() {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(vrbNC), vrbNC)).To(Succeed())
+		vrbNC.Status.Inventory.SriovAccelerators[0].VFs[0].DeviceID = "ffff"
+		Expect(r.Status().Update(context.TODO(), vrbNC)).To(Succeed())
+		Expect(r.Delete(context.TODO(), fecNC)).To(Succeed())
+
+		reconcileNode()
+		_, ok := nodeConfig()
+		Expect(ok).To(BeFalse())
+		Expect(r.Get(context.TODO(), client.ObjectKey{Name: "dp-worker", Namespace: NAMESPACE}, &corev1.Pod{})).To(Succeed())
+	})
+
+	It("should map sriovdp-config to nodes with node configs", func() {
+		Expect(r.devicePluginConfigToNodes(dpConfig)).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Name: "worker", Namespace: NAMESPACE}}))
+		Expect(r.devicePluginConfigToNodes(&corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "other", Namespace: NAMESPACE}})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/assets"
+)
+
+const (
+	assetsReadyCondition = "AssetsReady"
+	assetsReady          = "Ready"
+	assetsNotReady       = "NotReady"
+
+	// assetsReadinessRequeuePeriod is the interval of checking readiness of assets which are not ready yet
+	assetsReadinessRequeuePeriod = 15 * time.Second
+)
+
+var assetReadyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sriov_fec_operator_asset_ready",
+	Help: "Whether objects deployed from the asset of the daemon, labeler or device plugin are ready (1) or not (0)",
+}, []string{"asset"})
+
+func init() {
+	metrics.Registry.MustRegister(assetReadyGauge)
+}
+
+/*****************************************************************************
+ * Method: SriovFecOperatorConfigReconciler::reportReadiness
+ * Description: Exposes readiness of assets as the asset_ready metric and
+ * 		the AssetsReady condition of the config; returns whether all
+ * 		assets are ready
+ ****************************************************************************/
+func (r *SriovFecOperatorConfigReconciler) reportReadiness(config *sriovfecv2.SriovFecOperatorConfig, readiness []assets.AssetReadiness) bool {
+	allReady := true
+	var notReady []string
+	for _, asset := range readiness {
+		value := 1.0
+		if !asset.Ready {
+			value, allReady = 0, false
+			notReady = append(notReady, asset.NotReady...)
+			r.Log.WithField("asset", asset.ConfigMapName).WithField("notReady", asset.NotReady).Info("asset is not ready")
+		}
+		assetReadyGauge.WithLabelValues(asset.ConfigMapName).Set(value)
+	}
+
+	// nothing to report when operator runs with defaults; status is updated together with the Applied condition
+	if config.GetUID() == "" {
+		return allReady
+	}
+	condition := metav1.Condition{
+		Type:               assetsReadyCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             assetsReady,
+		Message:            "objects deployed from assets are ready",
+		ObservedGeneration: config.GetGeneration(),
+	}
+	if !allReady {
+		condition.Status, condition.Reason = metav1.ConditionFalse, assetsNotReady
+		condition.Message = strings.Join(notReady, "; ")
+	}
+	meta.SetStatusCondition(&config.Status.Conditions, condition)
+	return allReady
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"strconv"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("revisions", func() {
+	var (
+		fakeClient client.Client
+		reconciler *SriovFecClusterConfigReconciler
+		cc         *sriovv2.SriovFecClusterConfig
+	)
+
+	revisionNumbers := func(kind, name string) []int64 {
+		revisions, err := reconciler.listRevisions(kind, name)
+		Expect(err).ToNot(HaveOccurred())
+		var numbers []int64
+		for _, revision := range revisions {
+			numbers = append(numbers, revision.Revision)
+		}
+		return numbers
+	}
+
+	changeVFAmount := func(amount int) {
+		Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), cc)).To(Succeed())
+		cc.Spec.PhysicalFunction.VFAmount = amount
+		cc.Generation++
+		Expect(fakeClient.Update(context.TODO(), cc)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		cc = &sriovv2.SriovFecClusterConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "config", Namespace: NAMESPACE, Generation: 1},
+			Spec: sriovv2.SriovFecClusterConfigSpec{
+				NodeSelector:     map[string]string{"fec": "true"},
+				PhysicalFunction: sriovv2.PhysicalFunctionConfig{PFDriver: utils.PciPfStubDash, VFDriver: utils.VfioPci, VFAmount: 2},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(cc).Build()
+		reconciler = &SriovFecClusterConfigReconciler{Client: fakeClient, Log: utils.NewLogger()}
+	})
+
+	It("should record every distinct spec once", func() {
+		revision, recorded, err := reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeTrue())
+		Expect(revision.Revision).To(BeEquivalentTo(1))
+		Expect(revision.Annotations).To(HaveKeyWithValue(utils.RevisionGenerationAnnotation, "1"))
+		Expect(revision.OwnerReferences).To(HaveLen(1))
+
+		_, recorded, err = reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeFalse())
+		Expect(revisionNumbers(clusterConfigKind, cc.Name)).To(Equal([]int64{1}))
+	})
+
+	It("should renumber revision of previously applied spec", func() {
+		_, _, err := reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		changeVFAmount(4)
+		_, _, err = reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		changeVFAmount(2)
+
+		revision, recorded, err := reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeTrue())
+		Expect(revision.Revision).To(BeEquivalentTo(3))
+		Expect(revision.Annotations).To(HaveKeyWithValue(utils.RevisionGenerationAnnotation, "3"))
+		Expect(revisionNumbers(clusterConfigKind, cc.Name)).To(Equal([]int64{2, 3}))
+	})
+
+	It("should keep limited history", func() {
+		for amount := 1; amount <= revisionHistoryLimit+2; amount++ {
+			changeVFAmount(amount)
+			_, _, err := reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		numbers := revisionNumbers(clusterConfigKind, cc.Name)
+		Expect(numbers).To(HaveLen(revisionHistoryLimit))
+		Expect(numbers[0]).To(BeEquivalentTo(3))
+	})
+
+	It("should record results of node and cluster config revisions", func() {
+		node := corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-a", Labels: map[string]string{"fec": "true"}}}
+		nc := &sriovv2.SriovFecNodeConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "node-a", Namespace: NAMESPACE, Generation: 1},
+			Status: sriovv2.SriovFecNodeConfigStatus{Conditions: []v1.Condition{
+				{Type: nodeConfiguredCondition, Status: v1.ConditionFalse, Reason: resultFailed, Message: "pf-bb-config failed", ObservedGeneration: 1},
+			}},
+		}
+		Expect(fakeClient.Create(context.TODO(), nc)).To(Succeed())
+		ccs := []sriovv2.SriovFecClusterConfig{*cc}
+
+		reconciler.recordRevisions(ccs, []corev1.Node{node})
+		reconciler.recordRevisions(ccs, []corev1.Node{node})
+
+		revisions, err := reconciler.listRevisions(nodeConfigKind, nc.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Annotations).To(HaveKeyWithValue(utils.RevisionResultAnnotation, "Failed: pf-bb-config failed"))
+		Expect(revisions[0].Annotations).To(HaveKey(utils.RevisionResultTimeAnnotation))
+
+		revisions, err = reconciler.listRevisions(clusterConfigKind, cc.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Annotations).To(HaveKeyWithValue(utils.RevisionResultAnnotation, "Failed on nodes: node-a"))
+	})
+
+	Context("rollback", func() {
+		BeforeEach(func() {
+			_, _, err := reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+			Expect(err).ToNot(HaveOccurred())
+			changeVFAmount(4)
+			_, _, err = reconciler.recordRevision(cc, clusterConfigKind, cc.Spec)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should restore spec of requested revision", func() {
+			cc.Annotations = map[string]string{utils.RollbackToRevisionAnnotation: strconv.Itoa(1)}
+
+			Expect(reconciler.rollback(cc)).To(Succeed())
+
+			res := new(sriovv2.SriovFecClusterConfig)
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), res)).To(Succeed())
+			Expect(res.Spec.PhysicalFunction.VFAmount).To(Equal(2))
+			Expect(res.Annotations).ToNot(HaveKey(utils.RollbackToRevisionAnnotation))
+		})
+
+		It("should drop the annotation and report an event when revision cannot be restored", func() {
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+			for value, reason := range map[string]string{"7": "revision 7 not found", "latest": `invalid revision "latest"`} {
+				Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), cc)).To(Succeed())
+				cc.Annotations = map[string]string{utils.RollbackToRevisionAnnotation: value}
+				Expect(fakeClient.Update(context.TODO(), cc)).To(Succeed())
+
+				Expect(reconciler.rollback(cc)).To(Succeed())
+
+				res := new(sriovv2.SriovFecClusterConfig)
+				Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), res)).To(Succeed())
+				Expect(res.Spec.PhysicalFunction.VFAmount).To(Equal(4))
+				Expect(res.Annotations).ToNot(HaveKey(utils.RollbackToRevisionAnnotation))
+				Expect(recorder.Events).To(Receive(And(ContainSubstring(rollbackRejected), ContainSubstring(reason))))
+			}
+		})
+
+		It("should do nothing when rollback is not requested", func() {
+			Expect(reconciler.rollback(cc)).To(Succeed())
+			Expect(cc.Spec.PhysicalFunction.VFAmount).To(Equal(4))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// condition types set by the daemon on SriovFecNodeConfig
+	nodeConfiguredCondition      = "Configured"
+	nodePendingApprovalCondition = "PendingApproval"
+
+	rolloutCheckConfigured = "Configured"
+	rolloutCheckVfStatus   = "VfStatus"
+	rolloutCheckDegraded   = "Degraded"
+
+	defaultCanaryCount = 1
+)
+
+var rolloutNow = metav1.Now
+
+// canaryCheck is a RolloutCheck which has not been decided yet when pending is set
+type canaryCheck struct {
+	sriovfecv2.RolloutCheck
+	pending bool
+}
+
+// rollouts holds nodes back from receiving changes of ClusterConfigs which are not rolled out to canary nodes yet
+type rollouts struct {
+	// key: ClusterConfig name
+	inProgress map[string]*sriovfecv2.SriovFecClusterConfig
+}
+
+/*****************************************************************************
+ * Method: rollouts::holdsBack
+ * Description: Returns name of the ClusterConfig whose rollout does not allow
+ * 		the node to be updated yet; empty string if the node can be updated
+ ****************************************************************************/
+func (r rollouts) holdsBack(node corev1.Node) string {
+	for name, cc := range r.inProgress {
+		if !labels.Set(cc.Spec.NodeSelector).AsSelector().Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if !slices.Contains(cc.Status.Rollout.CanaryNodes, node.Name) {
+			return name
+		}
+	}
+	return ""
+}
+
+/*****************************************************************************
+ * Method: SriovFecClusterConfigReconciler::progressRollouts
+ * Description: Starts rollout of new generations of ClusterConfigs, verifies
+ * 		health of canary nodes of rollouts in progress and records the
+ * 		progress in ClusterConfig status. Returns rollouts which are not
+ * 		completed yet.
+ ****************************************************************************/
+func (r *SriovFecClusterConfigReconciler) progressRollouts(clusterConfigs []sriovfecv2.SriovFecClusterConfig, nodes []corev1.Node) rollouts {
+	result := rollouts{inProgress: map[string]*sriovfecv2.SriovFecClusterConfig{}}
+
+	for i := range clusterConfigs {
+		cc := &clusterConfigs[i]
+		if cc.Spec.Rollout == nil {
+			continue
+		}
+
+		previous := cc.Status.Rollout.DeepCopy()
+		if previous == nil || previous.Generation != cc.GetGeneration() {
+			cc.Status.Rollout = r.startRollout(cc, nodes)
+		} else {
+			r.verifyCanaries(cc)
+		}
+
+		if !equality.Semantic.DeepEqual(previous, cc.Status.Rollout) {
+			r.Log.WithField("clusterConfig", cc.Name).WithField("phase", cc.Status.Rollout.Phase).
+				WithField("message", cc.Status.Rollout.Message).Info("rollout progressed")
+			if err := r.Status().Update(context.TODO(), cc); err != nil {
+				r.Log.WithError(err).WithField("clusterConfig", cc.Name).Error("failed to update rollout status")
+				// keep previous state to not update remaining nodes before the progress is recorded
+				if previous != nil && previous.Generation == cc.GetGeneration() {
+					cc.Status.Rollout = previous
+				}
+			}
+		}
+
+		if cc.Status.Rollout.Phase != sriovfecv2.RolloutPhaseCompleted {
+			result.inProgress[cc.Name] = cc
+		}
+	}
+	return result
+}
+
+func (r *SriovFecClusterConfigReconciler) startRollout(cc *sriovfecv2.SriovFecClusterConfig, nodes []corev1.Node) *sriovfecv2.RolloutStatus {
+	status := &sriovfecv2.RolloutStatus{
+		Generation:  cc.GetGeneration(),
+		Phase:       sriovfecv2.RolloutPhaseCanary,
+		CanaryNodes: selectCanaryNodes(cc, nodes),
+	}
+	if len(status.CanaryNodes) == 0 {
+		status.Phase = sriovfecv2.RolloutPhaseCompleted
+		status.Message = "no canary nodes matched; change applied to all nodes"
+		return status
+	}
+	status.Message = fmt.Sprintf("applying generation %d to canary nodes", cc.GetGeneration())
+	return status
+}
+
+// selectCanaryNodes returns sorted names of canary nodes among the nodes matching the ClusterConfig
+func selectCanaryNodes(cc *sriovfecv2.SriovFecClusterConfig, nodes []corev1.Node) []string {
+	nodeSelector := labels.Set(cc.Spec.NodeSelector).AsSelector()
+	canarySelector := labels.Set(cc.Spec.Rollout.CanaryNodeSelector).AsSelector()
+
+	var canaries []string
+	for _, node := range nodes {
+		nodeLabels := labels.Set(node.Labels)
+		if nodeSelector.Matches(nodeLabels) && canarySelector.Matches(nodeLabels) {
+			canaries = append(canaries, node.Name)
+		}
+	}
+	sort.Strings(canaries)
+
+	if len(cc.Spec.Rollout.CanaryNodeSelector) == 0 {
+		count := cc.Spec.Rollout.CanaryCount
+		if count <= 0 {
+			count = defaultCanaryCount
+		}
+		if len(canaries) > count {
+			canaries = canaries[:count]
+		}
+	}
+	return canaries
+}
+
+/*****************************************************************************
+ * Method: SriovFecClusterConfigReconciler::verifyCanaries
+ * Description: Checks health of canary nodes and moves the rollout to the
+ * 		next phase: Canary -> Soaking when canaries are configured,
+ * 		Soaking -> Completed when canaries stayed healthy for the soak period,
+ * 		Canary/Soaking -> Halted when any check fails
+ ****************************************************************************/
+func (r *SriovFecClusterConfigReconciler) verifyCanaries(cc *sriovfecv2.SriovFecClusterConfig) {
+	status := cc.Status.Rollout
+	if status.Phase != sriovfecv2.RolloutPhaseCanary && status.Phase != sriovfecv2.RolloutPhaseSoaking {
+		return
+	}
+
+	var checks []sriovfecv2.RolloutCheck
+	var failed, pending []string
+	configured := true
+	for _, name := range status.CanaryNodes {
+		for _, check := range r.checkCanary(name) {
+			checks = append(checks, check.RolloutCheck)
+			switch {
+			case check.pending:
+				pending = append(pending, fmt.Sprintf("%s/%s", name, check.Check))
+			case !check.Passed:
+				failed = append(failed, fmt.Sprintf("%s/%s: %s", name, check.Check, check.Message))
+			}
+			if check.Check == rolloutCheckConfigured && !check.Passed {
+				configured = false
+			}
+		}
+	}
+	status.Checks = checks
+
+	if len(failed) > 0 {
+		status.Phase = sriovfecv2.RolloutPhaseHalted
+		status.Message = fmt.Sprintf("canary verification failed: %s", strings.Join(failed, "; "))
+		return
+	}
+
+	if status.Phase == sriovfecv2.RolloutPhaseCanary {
+		if !configured {
+			status.Message = fmt.Sprintf("waiting for canary nodes: %s", strings.Join(pending, ", "))
+			return
+		}
+		start := rolloutNow()
+		status.SoakStartTime = &start
+		status.Phase = sriovfecv2.RolloutPhaseSoaking
+	}
+
+	soakEnd := status.SoakStartTime.Add(time.Duration(cc.Spec.Rollout.SoakSeconds) * time.Second)
+	switch {
+	case len(pending) > 0:
+		status.Message = fmt.Sprintf("soaking; waiting for canary nodes: %s", strings.Join(pending, ", "))
+	case rolloutNow().Time.Before(soakEnd):
+		status.Message = fmt.Sprintf("canary nodes healthy; soaking until %s", soakEnd.UTC().Format(time.RFC3339))
+	default:
+		status.Phase = sriovfecv2.RolloutPhaseCompleted
+		status.Message = "canary nodes stayed healthy; change applied to all nodes"
+	}
+}
+
+/*****************************************************************************
+ * Method: SriovFecClusterConfigReconciler::checkCanary
+ * Description: Verifies Configured condition, VF status reported by telemetry
+ * 		and exposed inventory of the canary node
+ ****************************************************************************/
+func (r *SriovFecClusterConfigReconciler) checkCanary(name string) []canaryCheck {
+	newCheck := func(check string, passed, pending bool, msg string) canaryCheck {
+		return canaryCheck{RolloutCheck: sriovfecv2.RolloutCheck{Node: name, Check: check, Passed: passed, Message: msg}, pending: pending}
+	}
+
+	nc := new(sriovfecv2.SriovFecNodeConfig)
+	if err := r.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: NAMESPACE}, nc); err != nil {
+		return []canaryCheck{newCheck(rolloutCheckConfigured, false, true, err.Error())}
+	}
+
+	configured := nc.FindCondition(nodeConfiguredCondition)
+	approval := nc.FindCondition(nodePendingApprovalCondition)
+	switch {
+	case approval != nil && approval.Status == metav1.ConditionTrue:
+		return []canaryCheck{newCheck(rolloutCheckConfigured, false, true, "awaiting approval")}
+	case configured == nil:
+		return []canaryCheck{newCheck(rolloutCheckConfigured, false, true, "configuration not reported yet")}
+	case configured.Reason == string(sriovfecv2.FailedSync):
+		return []canaryCheck{newCheck(rolloutCheckConfigured, false, false, configured.Message)}
+	case configured.Status != metav1.ConditionTrue || configured.ObservedGeneration != nc.GetGeneration():
+		return []canaryCheck{newCheck(rolloutCheckConfigured, false, true, "configuration in progress")}
+	}
+	checks := []canaryCheck{newCheck(rolloutCheckConfigured, true, false, configured.Message)}
+
+	vfStatus := nc.FindCondition(utils.VfStatusReadyCondition)
+	switch {
+	case vfStatus == nil:
+		checks = append(checks, newCheck(rolloutCheckVfStatus, true, false, "vf_status not reported by telemetry"))
+	case vfStatus.ObservedGeneration != nc.GetGeneration():
+		checks = append(checks, newCheck(rolloutCheckVfStatus, false, true, "waiting for telemetry of the current configuration"))
+	default:
+		checks = append(checks, newCheck(rolloutCheckVfStatus, vfStatus.Status == metav1.ConditionTrue, false, vfStatus.Message))
+	}
+
+	requested := map[string]int{}
+	for _, pf := range nc.Spec.PhysicalFunctions {
+		requested[pf.PCIAddress] = pf.VFAmount
+	}
+	var degraded []string
+	for _, acc := range nc.Status.Inventory.SriovAccelerators {
+		if amount, ok := requested[acc.PCIAddress]; ok && len(acc.VFs) != amount {
+			degraded = append(degraded, fmt.Sprintf("%s exposes %d of %d VFs", acc.PCIAddress, len(acc.VFs), amount))
+		}
+	}
+	checks = append(checks, newCheck(rolloutCheckDegraded, len(degraded) == 0, false, strings.Join(degraded, ", ")))
+	return checks
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"time"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("rollout", func() {
+	var (
+		fakeClient client.Client
+		reconciler *SriovFecClusterConfigReconciler
+		cc         *sriovv2.SriovFecClusterConfig
+		nodes      []corev1.Node
+		now        v1.Time
+	)
+
+	newNode := func(name string, labels map[string]string) corev1.Node {
+		return corev1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	newNodeConfig := func(name string, conditions ...v1.Condition) *sriovv2.SriovFecNodeConfig {
+		return &sriovv2.SriovFecNodeConfig{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: NAMESPACE},
+			Spec: sriovv2.SriovFecNodeConfigSpec{
+				PhysicalFunctions: []sriovv2.PhysicalFunctionConfigExt{{PCIAddress: "0000:af:00.0", VFAmount: 2}},
+			},
+			Status: sriovv2.SriovFecNodeConfigStatus{
+				Conditions: conditions,
+				Inventory: sriovv2.NodeInventory{SriovAccelerators: []sriovv2.SriovAccelerator{{
+					PCIAddress: "0000:af:00.0",
+					VFs:        []sriovv2.VF{{PCIAddress: "0000:af:00.1"}, {PCIAddress: "0000:af:00.2"}},
+				}}},
+			},
+		}
+	}
+
+	configured := v1.Condition{Type: nodeConfiguredCondition, Status: v1.ConditionTrue, Reason: string(sriovv2.SucceededSync)}
+
+	progress := func() rollouts {
+		Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), cc)).To(Succeed())
+		result := reconciler.progressRollouts([]sriovv2.SriovFecClusterConfig{*cc}, nodes)
+		Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cc), cc)).To(Succeed())
+		return result
+	}
+
+	setup := func(objects ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, cc)...).Build()
+		reconciler = &SriovFecClusterConfigReconciler{Client: fakeClient, Log: utils.NewLogger()}
+	}
+
+	BeforeEach(func() {
+		now = v1.NewTime(time.Now().Truncate(time.Second))
+		rolloutNow = func() v1.Time { return now }
+
+		nodes = []corev1.Node{
+			newNode("node-a", map[string]string{"fec": "true"}),
+			newNode("node-b", map[string]string{"fec": "true", "canary": "true"}),
+			newNode("node-c", map[string]string{"fec": "true"}),
+		}
+		cc = &sriovv2.SriovFecClusterConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "config", Namespace: NAMESPACE, Generation: 1},
+			Spec: sriovv2.SriovFecClusterConfigSpec{
+				NodeSelector: map[string]string{"fec": "true"},
+				Rollout:      &sriovv2.RolloutStrategy{CanaryCount: 1, SoakSeconds: 60},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		rolloutNow = v1.Now
+	})
+
+	It("should hold back all but canary nodes when rollout starts", func() {
+		setup()
+
+		result := progress()
+		Expect(cc.Status.Rollout).ToNot(BeNil())
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseCanary))
+		Expect(cc.Status.Rollout.CanaryNodes).To(Equal([]string{"node-a"}))
+		Expect(result.holdsBack(nodes[0])).To(BeEmpty())
+		Expect(result.holdsBack(nodes[1])).To(Equal("config"))
+		Expect(result.holdsBack(nodes[2])).To(Equal("config"))
+	})
+
+	It("should select canary nodes by canaryNodeSelector", func() {
+		cc.Spec.Rollout.CanaryNodeSelector = map[string]string{"canary": "true"}
+		setup()
+
+		progress()
+		Expect(cc.Status.Rollout.CanaryNodes).To(Equal([]string{"node-b"}))
+	})
+
+	It("should complete immediately when no canary node matches", func() {
+		cc.Spec.Rollout.CanaryNodeSelector = map[string]string{"canary": "none"}
+		setup()
+
+		result := progress()
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseCompleted))
+		Expect(result.holdsBack(nodes[1])).To(BeEmpty())
+	})
+
+	It("should soak healthy canaries and then complete the rollout", func() {
+		setup(newNodeConfig("node-a"))
+		progress()
+
+		By("waiting for canary to be configured")
+		progress()
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseCanary))
+
+		nc := newNodeConfig("node-a", configured)
+		Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(nc), nc)).To(Succeed())
+		nc.Status.Conditions = []v1.Condition{configured}
+		nc.Status.Conditions[0].ObservedGeneration = nc.Generation
+		Expect(fakeClient.Update(context.TODO(), nc)).To(Succeed())
+
+		By("soaking configured canary")
+		progress()
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseSoaking))
+		Expect(cc.Status.Rollout.SoakStartTime).ToNot(BeNil())
+		Expect(cc.Status.Rollout.Checks).To(HaveLen(3))
+
+		now = v1.NewTime(now.Add(30 * time.Second))
+		Expect(progress().holdsBack(nodes[1])).To(Equal("config"))
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseSoaking))
+
+		By("completing rollout after soak period")
+		now = v1.NewTime(now.Add(30 * time.Second))
+		result := progress()
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseCompleted))
+		Expect(result.holdsBack(nodes[1])).To(BeEmpty())
+	})
+
+	It("should halt when telemetry reports VFs which are not ready", func() {
+		nc := newNodeConfig("node-a", configured, v1.Condition{
+			Type: utils.VfStatusReadyCondition, Status: v1.ConditionFalse, Reason: "VfsNotReady", Message: "VFs not ready: 0000:af:00.2",
+		})
+		setup(nc)
+		progress()
+		progress()
+
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseHalted))
+		Expect(cc.Status.Rollout.Message).To(ContainSubstring("node-a/VfStatus: VFs not ready: 0000:af:00.2"))
+		Expect(progress().holdsBack(nodes[1])).To(Equal("config"))
+	})
+
+	It("should halt when canary is degraded", func() {
+		nc := newNodeConfig("node-a", configured)
+		nc.Status.Inventory.SriovAccelerators[0].VFs = nc.Status.Inventory.SriovAccelerators[0].VFs[:1]
+		setup(nc)
+		progress()
+		progress()
+
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseHalted))
+		Expect(cc.Status.Rollout.Message).To(ContainSubstring("0000:af:00.0 exposes 1 of 2 VFs"))
+	})
+
+	It("should restart rollout for new generation", func() {
+		setup()
+		progress()
+		cc.Status.Rollout.Phase = sriovv2.RolloutPhaseHalted
+		Expect(fakeClient.Status().Update(context.TODO(), cc)).To(Succeed())
+
+		cc.Spec.Rollout.CanaryCount = 2
+		cc.Generation = 2
+		Expect(fakeClient.Update(context.TODO(), cc)).To(Succeed())
+		progress()
+
+		Expect(cc.Status.Rollout.Generation).To(Equal(cc.Generation))
+		Expect(cc.Status.Rollout.Phase).To(Equal(sriovv2.RolloutPhaseCanary))
+		Expect(cc.Status.Rollout.CanaryNodes).To(Equal([]string{"node-a", "node-b"}))
+	})
+})
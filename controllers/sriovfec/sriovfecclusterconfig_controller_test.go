@@ -159,7 +159,7 @@ var _ = Describe("SriovControllerTest", func() {
 		}
 
 		reconcile := func(ccName string) *SriovFecClusterConfigReconciler {
-			reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+			reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 			_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest(ccName))
 			Expect(err).ToNot(HaveOccurred())
 			return &reconciler
@@ -419,7 +419,7 @@ var _ = Describe("SriovControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("cc1"))
 				Expect(err).ToNot(HaveOccurred())
@@ -467,7 +467,7 @@ var _ = Describe("SriovControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 				ccs := []string{"cc1", "cc2"}
 				for i := 0; i < 100; i++ {
 					cc := ccs[i%len(ccs)]
@@ -680,7 +680,7 @@ var _ = Describe("SriovControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("cc"))
 				Expect(err).ToNot(HaveOccurred())
 
@@ -716,7 +716,7 @@ var _ = Describe("SriovControllerTest", func() {
 						}
 					})
 
-					reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+					reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 					_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 					Expect(err).ToNot(HaveOccurred())
 
@@ -771,7 +771,7 @@ var _ = Describe("SriovControllerTest", func() {
 						}
 					})
 
-					reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+					reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 					_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 					Expect(err).ToNot(HaveOccurred())
 
@@ -821,7 +821,7 @@ var _ = Describe("SriovControllerTest", func() {
 					cc.Spec.DrainSkip = &val
 				})
 
-				reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 				Expect(err).ToNot(HaveOccurred())
 
@@ -840,7 +840,7 @@ var _ = Describe("SriovControllerTest", func() {
 				cc.Namespace = v1.NamespaceSystem
 				Expect(k8sClient.Create(context.TODO(), cc)).ToNot(HaveOccurred())
 
-				reconciler := SriovFecClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovFecClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest(clusterConfigPrototype.Name))
 				Expect(err).ToNot(HaveOccurred())
 
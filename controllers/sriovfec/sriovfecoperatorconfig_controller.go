@@ -0,0 +1,357 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/assets"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+)
+
+const (
+	operatorConfigAppliedCondition = "Applied"
+	operatorConfigApplied          = "Applied"
+	operatorConfigInvalid          = "InvalidConfig"
+	operatorConfigDeploymentFailed = "DeploymentFailed"
+
+	// assetDriftCorrected is the reason of events reporting objects of assets which were re-applied due to drift
+	assetDriftCorrected = "DriftCorrected"
+
+	// daemonAssetConfigMapName identifies the asset of the daemon in assets.Manager
+	daemonAssetConfigMapName = "daemon-config"
+	acceleratorNodeLabel     = "fpga.intel.com/intel-accelerator-present"
+)
+
+// SriovFecOperatorConfigReconciler re-renders and redeploys operator assets when SriovFecOperatorConfig changes
+// or when objects deployed from the assets drift from their desired state; it also drives teardown of nodes
+// when the config requesting it is deleted
+type SriovFecOperatorConfigReconciler struct {
+	client.Client
+	Log *logrus.Logger
+
+	// AssetsManager renders and deploys assets of the daemon, labeler and device plugin
+	AssetsManager *assets.Manager
+
+	// OperatorLoggers are loggers of the operator whose level is controlled by logLevels.operator
+	OperatorLoggers []*logrus.Logger
+
+	// Recorder reports objects re-applied due to drift; optional
+	Recorder record.EventRecorder
+
+	// appliedConfig identifies the config whose assets were deployed by the last reconcile
+	appliedConfig string
+}
+
+// +kubebuilder:rbac:groups=sriovfec.intel.com,resources=sriovfecoperatorconfigs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=sriovfec.intel.com,resources=sriovfecoperatorconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=sriovfec.intel.com,resources=sriovfecoperatorconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts;secrets;configmaps,verbs=watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=watch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=sriovfec.intel.com,resources=sriovfecnodeconfigs,verbs=deletecollection
+// +kubebuilder:rbac:groups=sriovvrb.intel.com,resources=sriovvrbnodeconfigs,verbs=deletecollection
+
+func (r *SriovFecOperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.Name != sriovfecv2.OperatorConfigName {
+		r.Log.WithField("name", req.Name).Infof("ignoring SriovFecOperatorConfig, only the one named %s is honored", sriovfecv2.OperatorConfigName)
+		return ctrl.Result{}, nil
+	}
+
+	config, err := GetOperatorConfig(ctx, r)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !config.GetDeletionTimestamp().IsZero() {
+		return r.teardown(ctx, config)
+	}
+	if err := r.syncTeardownFinalizer(ctx, config); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.clearTeardownMarks(ctx, config); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := ConfigureAssets(r.AssetsManager, config); err != nil {
+		r.Log.WithError(err).Error("invalid SriovFecOperatorConfig")
+		return ctrl.Result{}, r.updateAppliedCondition(config, metav1.ConditionFalse, operatorConfigInvalid, err.Error())
+	}
+	r.configureOperatorLoggers(config)
+
+	err = r.AssetsManager.DeployConfigMaps(ctx, false)
+	if err == nil {
+		err = r.AssetsManager.LoadFromConfigMapAndDeploy(ctx)
+	}
+	changed := r.AssetsManager.TakeChanged()
+	if err != nil {
+		r.Log.WithError(err).Error("failed to redeploy assets")
+		if updateErr := r.updateAppliedCondition(config, metav1.ConditionFalse, operatorConfigDeploymentFailed, err.Error()); updateErr != nil {
+			r.Log.WithError(updateErr).Error("failed to update status of SriovFecOperatorConfig")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// changes made while deploying the config which was deployed already to the same node groups are corrections of drift
+	appliedConfig := fmt.Sprintf("%s/%d/%s", config.GetUID(), config.GetGeneration(), r.AssetsManager.NodeGroupsSignature())
+	if r.appliedConfig == appliedConfig {
+		r.reportDrift(changed)
+	}
+	r.appliedConfig = appliedConfig
+
+	// readiness is checked again later instead of waiting for objects which are not ready yet
+	readiness, err := r.AssetsManager.CheckReadiness(ctx)
+	if err != nil {
+		r.Log.WithError(err).Error("failed to check readiness of assets")
+		return ctrl.Result{}, err
+	}
+	result := ctrl.Result{}
+	if !r.reportReadiness(config, readiness) {
+		result.RequeueAfter = assetsReadinessRequeuePeriod
+	}
+
+	return result, r.updateAppliedCondition(config, metav1.ConditionTrue, operatorConfigApplied, "assets of daemon, labeler and device plugin are deployed")
+}
+
+func (r *SriovFecOperatorConfigReconciler) configureOperatorLoggers(config *sriovfecv2.SriovFecOperatorConfig) {
+	level := logrus.InfoLevel
+	if config.Spec.LogLevels != nil && config.Spec.LogLevels.Operator != "" {
+		// validated by ConfigureAssets
+		level, _ = logrus.ParseLevel(string(config.Spec.LogLevels.Operator))
+	}
+	for _, log := range r.OperatorLoggers {
+		if utils.ConfigureLogger(log, level, string(config.Spec.LogFormat)) {
+			log.WithField("logLevel", level).WithField("logFormat", config.Spec.LogFormat).Info("logging configuration changed")
+		}
+	}
+}
+
+func (r *SriovFecOperatorConfigReconciler) updateAppliedCondition(config *sriovfecv2.SriovFecOperatorConfig, status metav1.ConditionStatus, reason, msg string) error {
+	// nothing to report when operator runs with defaults
+	if config.GetUID() == "" {
+		return nil
+	}
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               operatorConfigAppliedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            msg,
+		ObservedGeneration: config.GetGeneration(),
+	})
+	return r.Status().Update(context.TODO(), config)
+}
+
+func (r *SriovFecOperatorConfigReconciler) reportDrift(changed []client.Object) {
+	for _, obj := range changed {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		if gvk, err := apiutil.GVKForObject(obj, r.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+		r.Log.WithField("kind", kind).WithField("name", obj.GetName()).Warn("object drifted from desired state, re-applied")
+		if r.Recorder != nil {
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, assetDriftCorrected,
+				"%s %s drifted from the desired state and was re-applied; annotate it with %s=true to keep manual changes", kind, obj.GetName(), utils.UnmanagedAnnotation)
+		}
+	}
+}
+
+// assetObjectToConfig maps objects deployed from assets, i.e. controlled by the operator deployment, to the honored config
+func (r *SriovFecOperatorConfigReconciler) assetObjectToConfig(obj client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || r.AssetsManager.Owner == nil || owner.Kind != "Deployment" || owner.Name != r.AssetsManager.Owner.GetName() {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: sriovfecv2.OperatorConfigName, Namespace: NAMESPACE}}}
+}
+
+// acceleratorNodeToConfig maps accelerator nodes to the honored config while the daemon is deployed per node group
+func (r *SriovFecOperatorConfigReconciler) acceleratorNodeToConfig(obj client.Object) []reconcile.Request {
+	if r.AssetsManager.NodeGrouping == "" {
+		return nil
+	}
+	if _, ok := obj.GetLabels()[acceleratorNodeLabel]; !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: sriovfecv2.OperatorConfigName, Namespace: NAMESPACE}}}
+}
+
+// nodeGroupChangedPredicate passes changes of nodes which may move them to another node group
+var nodeGroupChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, okOld := e.ObjectOld.(*corev1.Node)
+		newNode, okNew := e.ObjectNew.(*corev1.Node)
+		if !okOld || !okNew {
+			return true
+		}
+		_, wasAccelerator := oldNode.Labels[acceleratorNodeLabel]
+		if _, isAccelerator := newNode.Labels[acceleratorNodeLabel]; wasAccelerator != isAccelerator {
+			return true
+		}
+		return oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion ||
+			!reflect.DeepEqual(oldNode.Labels, newNode.Labels)
+	},
+}
+
+func (r *SriovFecOperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&sriovfecv2.SriovFecOperatorConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	toConfig := handler.EnqueueRequestsFromMapFunc(r.assetObjectToConfig)
+	for _, obj := range []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		b = b.Watches(&source.Kind{Type: obj}, toConfig)
+	}
+	b = b.Watches(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.acceleratorNodeToConfig),
+		builder.WithPredicates(nodeGroupChangedPredicate))
+	// status of DaemonSets is not a part of the desired state
+	return b.Watches(&source.Kind{Type: &appsv1.DaemonSet{}}, toConfig, builder.WithPredicates(predicate.Or(
+		predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
+
+/*****************************************************************************
+ * Function: GetOperatorConfig
+ * Description: Returns the SriovFecOperatorConfig honored by the operator;
+ * 		a config with empty spec is returned when it does not exist
+ ****************************************************************************/
+func GetOperatorConfig(ctx context.Context, c client.Reader) (*sriovfecv2.SriovFecOperatorConfig, error) {
+	config := new(sriovfecv2.SriovFecOperatorConfig)
+	err := c.Get(ctx, client.ObjectKey{Name: sriovfecv2.OperatorConfigName, Namespace: NAMESPACE}, config)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return &sriovfecv2.SriovFecOperatorConfig{}, nil
+	}
+	return config, err
+}
+
+/*****************************************************************************
+ * Function: ConfigureAssets
+ * Description: Validates the SriovFecOperatorConfig and makes the assets
+ * 		manager render assets according to it
+ ****************************************************************************/
+func ConfigureAssets(m *assets.Manager, config *sriovfecv2.SriovFecOperatorConfig) error {
+	overrides, err := operatorConfigTemplateVars(m.EnvPrefix, config.Spec)
+	if err != nil {
+		return err
+	}
+	m.Overrides = overrides
+
+	m.NodeGrouping = ""
+	if grouping := config.Spec.DaemonNodeGrouping; grouping != nil {
+		switch {
+		case grouping.KernelVersion && grouping.Label != "":
+			return fmt.Errorf("daemonNodeGrouping: kernelVersion and label are mutually exclusive")
+		case grouping.KernelVersion:
+			m.NodeGrouping = assets.NodeGroupingKernel
+		case grouping.Label != "":
+			if errs := validation.IsQualifiedName(grouping.Label); len(errs) > 0 {
+				return fmt.Errorf("daemonNodeGrouping: invalid label %q: %s", grouping.Label, strings.Join(errs, "; "))
+			}
+			m.NodeGrouping = grouping.Label
+		}
+	}
+
+	for i := range m.Assets {
+		if m.Assets[i].ConfigMapName == daemonAssetConfigMapName {
+			m.Assets[i].Tolerations = config.Spec.DaemonTolerations
+			m.Assets[i].PerNodeGroup = true
+		}
+	}
+	return nil
+}
+
+// operatorConfigTemplateVars translates the spec into template variables of the assets; unset fields are not overridden
+func operatorConfigTemplateVars(prefix string, spec sriovfecv2.SriovFecOperatorConfigSpec) (map[string]string, error) {
+	vars := map[string]string{}
+
+	nodeSelector := map[string]string{}
+	for k, v := range spec.DaemonNodeSelector {
+		nodeSelector[k] = v
+	}
+	nodeSelector[acceleratorNodeLabel] = ""
+	raw, err := json.Marshal(nodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	vars[prefix+"DAEMON_NODE_SELECTOR"] = string(raw)
+
+	// log levels are applied by the operator and the daemon without redeployment
+	if levels := spec.LogLevels; levels != nil {
+		all := []sriovfecv2.LogLevel{levels.Operator, levels.Daemon}
+		if loggers := levels.DaemonLoggers; loggers != nil {
+			all = append(all, loggers.Reconciler, loggers.DrainHelper, loggers.Telemetry, loggers.PfBbConfigMonitor)
+		}
+		for _, level := range all {
+			if _, err := logrus.ParseLevel(string(level)); level != "" && err != nil {
+				return nil, err
+			}
+		}
+	}
+	if format := spec.LogFormat; format != "" && format != utils.LogFormatJSON && format != utils.LogFormatText {
+		return nil, fmt.Errorf("unsupported logFormat %q", format)
+	}
+
+	if spec.TelemetryInterval != nil {
+		vars[prefix+"METRIC_GATHER_INTERVAL"] = spec.TelemetryInterval.Duration.String()
+	}
+
+	if drain := spec.DrainDefaults; drain != nil {
+		if drain.TimeoutSeconds != nil {
+			vars[prefix+"DRAIN_TIMEOUT_SECONDS"] = strconv.FormatInt(*drain.TimeoutSeconds, 10)
+		}
+		if drain.LeaseDurationSeconds != nil {
+			vars[prefix+"LEASE_DURATION_SECONDS"] = strconv.FormatInt(*drain.LeaseDurationSeconds, 10)
+		}
+	}
+
+	if names := spec.ResourceNames; names != nil {
+		for key, name := range map[string]string{
+			"LTE_RESOURCE_NAME":    names.LTE,
+			"5G_RESOURCE_NAME":     names.FiveG,
+			"ACC100_RESOURCE_NAME": names.ACC100,
+			"ACC200_RESOURCE_NAME": names.ACC200,
+			"VRB2_RESOURCE_NAME":   names.VRB2,
+		} {
+			if name != "" {
+				vars[prefix+key] = name
+			}
+		}
+	}
+
+	if len(spec.FeatureGates) > 0 {
+		gates := utils.FeatureGates(spec.FeatureGates)
+		if err := gates.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid featureGates: %v", err)
+		}
+		vars[prefix+"FEATURE_GATES"] = gates.String()
+	}
+	return vars, nil
+}
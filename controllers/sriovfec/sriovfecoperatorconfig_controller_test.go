@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"time"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/assets"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("SriovFecOperatorConfig", func() {
+	newAssetsManager := func() *assets.Manager {
+		return &assets.Manager{
+			EnvPrefix: utils.SriovPrefix,
+			Assets: []assets.Asset{
+				{ConfigMapName: "labeler-config"},
+				{ConfigMapName: daemonAssetConfigMapName},
+			},
+		}
+	}
+
+	It("should override only template vars which are set", func() {
+		vars, err := operatorConfigTemplateVars(utils.SriovPrefix, sriovv2.SriovFecOperatorConfigSpec{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vars).To(Equal(map[string]string{
+			"SRIOV_FEC_DAEMON_NODE_SELECTOR": `{"fpga.intel.com/intel-accelerator-present":""}`,
+		}))
+	})
+
+	It("should render all fields of the spec", func() {
+		timeout, lease := int64(120), int64(300)
+		vars, err := operatorConfigTemplateVars(utils.SriovPrefix, sriovv2.SriovFecOperatorConfigSpec{
+			DaemonNodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+			LogLevels:          &sriovv2.ComponentLogLevels{Daemon: "debug"},
+			TelemetryInterval:  &v1.Duration{Duration: 15 * time.Second},
+			DrainDefaults:      &sriovv2.DrainDefaults{TimeoutSeconds: &timeout, LeaseDurationSeconds: &lease},
+			ResourceNames:      &sriovv2.ResourceNames{ACC100: "custom_acc100"},
+			FeatureGates:       map[string]bool{utils.FeatureNodeMaintenance: false},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vars).To(Equal(map[string]string{
+			"SRIOV_FEC_DAEMON_NODE_SELECTOR":   `{"fpga.intel.com/intel-accelerator-present":"","node-role.kubernetes.io/worker":""}`,
+			"SRIOV_FEC_METRIC_GATHER_INTERVAL": "15s",
+			"SRIOV_FEC_DRAIN_TIMEOUT_SECONDS":  "120",
+			"SRIOV_FEC_LEASE_DURATION_SECONDS": "300",
+			"SRIOV_FEC_ACC100_RESOURCE_NAME":   "custom_acc100",
+			"SRIOV_FEC_FEATURE_GATES":          "NodeMaintenance=false",
+		}))
+	})
+
+	It("should reject unknown feature gates", func() {
+		_, err := operatorConfigTemplateVars(utils.SriovPrefix, sriovv2.SriovFecOperatorConfigSpec{
+			FeatureGates: map[string]bool{"Teleport": true},
+		})
+		Expect(err).To(MatchError(ContainSubstring("unknown feature gates: Teleport")))
+	})
+
+	It("should add tolerations to daemon asset only", func() {
+		m := newAssetsManager()
+		toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}
+
+		Expect(ConfigureAssets(m, &sriovv2.SriovFecOperatorConfig{
+			Spec: sriovv2.SriovFecOperatorConfigSpec{DaemonTolerations: []corev1.Toleration{toleration}},
+		})).To(Succeed())
+		Expect(m.Assets[0].Tolerations).To(BeEmpty())
+		Expect(m.Assets[1].Tolerations).To(ConsistOf(toleration))
+		Expect(m.Overrides).To(HaveKey("SRIOV_FEC_DAEMON_NODE_SELECTOR"))
+	})
+
+	It("should deploy daemon per node group", func() {
+		m := newAssetsManager()
+		configure := func(grouping *sriovv2.DaemonNodeGrouping) error {
+			return ConfigureAssets(m, &sriovv2.SriovFecOperatorConfig{
+				Spec: sriovv2.SriovFecOperatorConfigSpec{DaemonNodeGrouping: grouping},
+			})
+		}
+
+		Expect(configure(&sriovv2.DaemonNodeGrouping{KernelVersion: true})).To(Succeed())
+		Expect(m.NodeGrouping).To(Equal(assets.NodeGroupingKernel))
+		Expect(m.Assets[0].PerNodeGroup).To(BeFalse())
+		Expect(m.Assets[1].PerNodeGroup).To(BeTrue())
+
+		Expect(configure(&sriovv2.DaemonNodeGrouping{Label: "example.com/pool"})).To(Succeed())
+		Expect(m.NodeGrouping).To(Equal("example.com/pool"))
+
+		Expect(configure(nil)).To(Succeed())
+		Expect(m.NodeGrouping).To(BeEmpty())
+
+		Expect(configure(&sriovv2.DaemonNodeGrouping{KernelVersion: true, Label: "pool"})).To(MatchError(ContainSubstring("mutually exclusive")))
+		Expect(configure(&sriovv2.DaemonNodeGrouping{Label: "bad label"})).To(MatchError(ContainSubstring("invalid label")))
+	})
+
+	It("should map accelerator nodes to config only when daemon is deployed per node group", func() {
+		r := SriovFecOperatorConfigReconciler{AssetsManager: newAssetsManager()}
+		accelerator := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "worker", Labels: map[string]string{acceleratorNodeLabel: ""}}}
+
+		Expect(r.acceleratorNodeToConfig(accelerator)).To(BeEmpty())
+		r.AssetsManager.NodeGrouping = assets.NodeGroupingKernel
+		Expect(r.acceleratorNodeToConfig(accelerator)).To(HaveLen(1))
+		Expect(r.acceleratorNodeToConfig(&corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "control-plane"}})).To(BeEmpty())
+	})
+
+	It("should report readiness of assets", func() {
+		r := SriovFecOperatorConfigReconciler{Log: utils.NewLogger()}
+		config := &sriovv2.SriovFecOperatorConfig{ObjectMeta: v1.ObjectMeta{UID: "config-uid"}}
+
+		Expect(r.reportReadiness(config, []assets.AssetReadiness{
+			{ConfigMapName: "labeler-config", Ready: true},
+			{ConfigMapName: daemonAssetConfigMapName, NotReady: []string{"DaemonSet sriov-fec-daemonset: 1 of 2 pods unavailable"}},
+		})).To(BeFalse())
+		condition := meta.FindStatusCondition(config.Status.Conditions, assetsReadyCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(assetsNotReady))
+		Expect(condition.Message).To(Equal("DaemonSet sriov-fec-daemonset: 1 of 2 pods unavailable"))
+		Expect(testutil.ToFloat64(assetReadyGauge.WithLabelValues("labeler-config"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(assetReadyGauge.WithLabelValues(daemonAssetConfigMapName))).To(Equal(0.0))
+
+		Expect(r.reportReadiness(config, []assets.AssetReadiness{{ConfigMapName: daemonAssetConfigMapName, Ready: true}})).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(config.Status.Conditions, assetsReadyCondition)).To(BeTrue())
+		Expect(testutil.ToFloat64(assetReadyGauge.WithLabelValues(daemonAssetConfigMapName))).To(Equal(1.0))
+	})
+
+	It("should use empty config when it does not exist", func() {
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		config, err := GetOperatorConfig(context.TODO(), c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Spec).To(Equal(sriovv2.SriovFecOperatorConfigSpec{}))
+	})
+
+	It("should reject invalid log level of daemon logger", func() {
+		_, err := operatorConfigTemplateVars(utils.SriovPrefix, sriovv2.SriovFecOperatorConfigSpec{
+			LogLevels: &sriovv2.ComponentLogLevels{DaemonLoggers: &sriovv2.DaemonLoggerLevels{Telemetry: "verbose"}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("not a valid logrus Level")))
+	})
+
+	It("should configure operator loggers", func() {
+		log := utils.NewLogger()
+		r := SriovFecOperatorConfigReconciler{Log: log, OperatorLoggers: []*logrus.Logger{log}}
+
+		r.configureOperatorLoggers(&sriovv2.SriovFecOperatorConfig{
+			Spec: sriovv2.SriovFecOperatorConfigSpec{
+				LogLevels: &sriovv2.ComponentLogLevels{Operator: "debug"},
+				LogFormat: utils.LogFormatText,
+			},
+		})
+		Expect(log.GetLevel()).To(Equal(logrus.DebugLevel))
+		Expect(log.Formatter).To(BeAssignableToTypeOf(&logrus.TextFormatter{}))
+
+		r.configureOperatorLoggers(&sriovv2.SriovFecOperatorConfig{})
+		Expect(log.GetLevel()).To(Equal(logrus.InfoLevel))
+		Expect(log.Formatter).To(BeAssignableToTypeOf(&logrus.JSONFormatter{}))
+	})
+
+	Context("drift of assets", func() {
+		var r *SriovFecOperatorConfigReconciler
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			owner := &appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "sriov-fec-controller-manager", Namespace: NAMESPACE}}
+			r = &SriovFecOperatorConfigReconciler{
+				Client:        fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Log:           utils.NewLogger(),
+				AssetsManager: &assets.Manager{Owner: owner},
+				Recorder:      record.NewFakeRecorder(10),
+			}
+		})
+
+		It("should map objects controlled by operator deployment to config", func() {
+			controlledBy := func(kind, name string) *corev1.ConfigMap {
+				return &corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "sriovdp-config", Namespace: NAMESPACE,
+					OwnerReferences: []v1.OwnerReference{{Kind: kind, Name: name, Controller: pointer.Bool(true)}}}}
+			}
+
+			Expect(r.assetObjectToConfig(controlledBy("Deployment", "sriov-fec-controller-manager"))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: sriovv2.OperatorConfigName, Namespace: NAMESPACE}}))
+			Expect(r.assetObjectToConfig(controlledBy("Deployment", "other"))).To(BeEmpty())
+			Expect(r.assetObjectToConfig(&corev1.ConfigMap{})).To(BeEmpty())
+		})
+
+		It("should report re-applied objects", func() {
+			r.reportDrift([]client.Object{&appsv1.DaemonSet{ObjectMeta: v1.ObjectMeta{Name: "sriov-fec-daemonset", Namespace: NAMESPACE}}})
+
+			events := r.Recorder.(*record.FakeRecorder).Events
+			Expect(events).To(HaveLen(1))
+			Expect(<-events).To(And(ContainSubstring(assetDriftCorrected), ContainSubstring("sriov-fec-daemonset")))
+		})
+	})
+})
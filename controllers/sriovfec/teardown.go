@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+)
+
+const (
+	operatorConfigTearingDown = "TearingDown"
+
+	// teardownRequeuePeriod is the interval of checking whether daemons completed the teardown
+	teardownRequeuePeriod = 10 * time.Second
+	daemonPodLabel        = "sriov-fec-daemonset"
+)
+
+/*****************************************************************************
+ * Method: SriovFecOperatorConfigReconciler::syncTeardownFinalizer
+ * Description: Adds the teardown finalizer to the config requesting teardown
+ * 		and removes it when teardown is no longer requested
+ ****************************************************************************/
+func (r *SriovFecOperatorConfigReconciler) syncTeardownFinalizer(ctx context.Context, config *sriovfecv2.SriovFecOperatorConfig) error {
+	if config.GetUID() == "" {
+		return nil
+	}
+
+	requested := config.Spec.Teardown != nil
+	if controllerutil.ContainsFinalizer(config, utils.TeardownFinalizer) == requested {
+		return nil
+	}
+	if requested {
+		controllerutil.AddFinalizer(config, utils.TeardownFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(config, utils.TeardownFinalizer)
+	}
+	return r.Update(ctx, config)
+}
+
+/*****************************************************************************
+ * Method: SriovFecOperatorConfigReconciler::teardown
+ * Description: Waits until daemons reset accelerators on all their nodes,
+ * 		cleans up node configs and labels as requested and releases
+ * 		the config by removing the teardown finalizer; nodes keep
+ * 		their teardown marks until a new config is created
+ ****************************************************************************/
+func (r *SriovFecOperatorConfigReconciler) teardown(ctx context.Context, config *sriovfecv2.SriovFecOperatorConfig) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(config, utils.TeardownFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	policy := sriovfecv2.Teardown{}
+	if config.Spec.Teardown != nil {
+		policy = *config.Spec.Teardown
+	}
+
+	pending, err := r.nodesPendingTeardown(ctx, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(pending) > 0 {
+		r.Log.WithField("nodes", pending).Info("waiting for daemons to reset accelerators")
+		msg := fmt.Sprintf("waiting for accelerators to be reset on nodes: %s", strings.Join(pending, ", "))
+		return ctrl.Result{RequeueAfter: teardownRequeuePeriod}, r.updateAppliedCondition(config, metav1.ConditionFalse, operatorConfigTearingDown, msg)
+	}
+
+	if policy.DeleteNodeConfigs {
+		r.Log.Info("deleting node configs")
+		for _, obj := range []client.Object{&sriovfecv2.SriovFecNodeConfig{}, &vrbv1.SriovVrbNodeConfig{}} {
+			if err := r.DeleteAllOf(ctx, obj, client.InNamespace(NAMESPACE)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Teardown marks stay on nodes, so daemons and labelers leave them alone until a new config is created
+	if policy.RemoveNodeLabels {
+		if err := r.removeAcceleratorLabels(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.Log.Info("teardown completed, releasing SriovFecOperatorConfig")
+	controllerutil.RemoveFinalizer(config, utils.TeardownFinalizer)
+	return ctrl.Result{}, r.Update(ctx, config)
+}
+
+// nodesPendingTeardown returns names of nodes running the daemon which did not complete teardown of the config yet
+func (r *SriovFecOperatorConfigReconciler) nodesPendingTeardown(ctx context.Context, config *sriovfecv2.SriovFecOperatorConfig) ([]string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(NAMESPACE), client.MatchingLabels{"app": daemonPodLabel}); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := r.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if node.Annotations[utils.TeardownCompletedAnnotation] != string(config.GetUID()) {
+			pending = append(pending, node.Name)
+		}
+	}
+	sort.Strings(pending)
+	return pending, nil
+}
+
+func hasAcceleratorLabels(node *corev1.Node) bool {
+	for label := range node.Labels {
+		if label == acceleratorNodeLabel || utils.IsAcceleratorLabel(label) {
+			return true
+		}
+	}
+	_, ok := node.Annotations[utils.AcceleratorInventoryAnnotation]
+	return ok
+}
+
+// clearTeardownMarks removes marks of teardowns of previous SriovFecOperatorConfigs from nodes, so that daemons and
+// labelers resume their work on nodes torn down before the config was created
+func (r *SriovFecOperatorConfigReconciler) clearTeardownMarks(ctx context.Context, config *sriovfecv2.SriovFecOperatorConfig) error {
+	if config.GetUID() == "" {
+		return nil
+	}
+	stale := func(node *corev1.Node) bool {
+		uid, annotated := node.Annotations[utils.TeardownCompletedAnnotation]
+		return annotated && uid != string(config.GetUID())
+	}
+	return r.patchNodes(ctx, stale, func(node *corev1.Node) {
+		r.Log.WithField("node", node.Name).Info("clearing teardown mark of the node")
+		delete(node.Annotations, utils.TeardownCompletedAnnotation)
+	})
+}
+
+// removeAcceleratorLabels removes labels and the inventory annotation set by the labeler from all nodes
+func (r *SriovFecOperatorConfigReconciler) removeAcceleratorLabels(ctx context.Context) error {
+	return r.patchNodes(ctx, hasAcceleratorLabels, func(node *corev1.Node) {
+		r.Log.WithField("node", node.Name).Info("removing accelerator labels of the node")
+		delete(node.Annotations, utils.AcceleratorInventoryAnnotation)
+		for label := range node.Labels {
+			if label == acceleratorNodeLabel || utils.IsAcceleratorLabel(label) {
+				delete(node.Labels, label)
+			}
+		}
+	})
+}
+
+// patchNodes applies the mutation to every node matching the filter
+func (r *SriovFecOperatorConfigReconciler) patchNodes(ctx context.Context, filter func(*corev1.Node) bool, mutate func(*corev1.Node)) error {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return err
+	}
+
+	for _, n := range nodes.Items {
+		if !filter(&n) {
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			node := &corev1.Node{}
+			if err := r.Get(ctx, client.ObjectKey{Name: n.Name}, node); err != nil {
+				return err
+			}
+			original := node.DeepCopy()
+			mutate(node)
+			return r.Patch(ctx, node, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovfec
+
+import (
+	"context"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Teardown", func() {
+	var (
+		r      *SriovFecOperatorConfigReconciler
+		config *sriovv2.SriovFecOperatorConfig
+	)
+
+	getNode := func(name string) *corev1.Node {
+		node := &corev1.Node{}
+		Expect(r.Get(context.TODO(), client.ObjectKey{Name: name}, node)).To(Succeed())
+		return node
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		Expect(vrbv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		config = &sriovv2.SriovFecOperatorConfig{
+			ObjectMeta: v1.ObjectMeta{Name: sriovv2.OperatorConfigName, Namespace: NAMESPACE, UID: "config-uid"},
+			Spec:       sriovv2.SriovFecOperatorConfigSpec{Teardown: &sriovv2.Teardown{DeleteNodeConfigs: true, RemoveNodeLabels: true}},
+		}
+		labels := map[string]string{acceleratorNodeLabel: "", utils.AcceleratorModelLabelPrefix + "ACC100": "1", "kubernetes.io/hostname": "worker"}
+		annotations := map[string]string{utils.AcceleratorInventoryAnnotation: "[]"}
+		r = &SriovFecOperatorConfigReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				config,
+				&corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "worker-1", Labels: labels, Annotations: annotations}},
+				&corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "worker-2", Labels: labels, Annotations: annotations}},
+				&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "daemon-1", Namespace: NAMESPACE, Labels: map[string]string{"app": daemonPodLabel}},
+					Spec: corev1.PodSpec{NodeName: "worker-1"}},
+				&sriovv2.SriovFecNodeConfig{ObjectMeta: v1.ObjectMeta{Name: "worker-1", Namespace: NAMESPACE}},
+				&vrbv1.SriovVrbNodeConfig{ObjectMeta: v1.ObjectMeta{Name: "worker-1", Namespace: NAMESPACE}},
+			).Build(),
+			Log: utils.NewLogger(),
+		}
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(config), config)).To(Succeed())
+	})
+
+	It("should keep teardown finalizer in sync with the spec", func() {
+		Expect(r.syncTeardownFinalizer(context.TODO(), config)).To(Succeed())
+		Expect(config.Finalizers).To(ConsistOf(utils.TeardownFinalizer))
+
+		config.Spec.Teardown = nil
+		Expect(r.syncTeardownFinalizer(context.TODO(), config)).To(Succeed())
+		Expect(config.Finalizers).To(BeEmpty())
+	})
+
+	It("should wait for daemons and clean up nodes afterwards", func() {
+		Expect(r.syncTeardownFinalizer(context.TODO(), config)).To(Succeed())
+		now := v1.Now()
+		config.DeletionTimestamp = &now
+
+		result, err := r.teardown(context.TODO(), config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(teardownRequeuePeriod))
+		condition := meta.FindStatusCondition(config.Status.Conditions, operatorConfigAppliedCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(operatorConfigTearingDown))
+		Expect(condition.Message).To(ContainSubstring("worker-1"))
+		Expect(condition.Message).ToNot(ContainSubstring("worker-2"))
+
+		node := getNode("worker-1")
+		node.Annotations = map[string]string{utils.TeardownCompletedAnnotation: "config-uid"}
+		Expect(r.Update(context.TODO(), node)).To(Succeed())
+
+		result, err = r.teardown(context.TODO(), config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(config.Finalizers).To(BeEmpty())
+
+		for _, name := range []string{"worker-1", "worker-2"} {
+			node := getNode(name)
+			Expect(node.Labels).ToNot(HaveKey(acceleratorNodeLabel))
+			Expect(node.Labels).ToNot(HaveKey(utils.AcceleratorModelLabelPrefix + "ACC100"))
+			Expect(node.Labels).To(HaveKey("kubernetes.io/hostname"))
+			Expect(node.Annotations).ToNot(HaveKey(utils.AcceleratorInventoryAnnotation))
+		}
+		Expect(getNode("worker-1").Annotations).To(HaveKeyWithValue(utils.TeardownCompletedAnnotation, "config-uid"))
+		fecNodeConfigs, vrbNodeConfigs := &sriovv2.SriovFecNodeConfigList{}, &vrbv1.SriovVrbNodeConfigList{}
+		Expect(r.List(context.TODO(), fecNodeConfigs)).To(Succeed())
+		Expect(r.List(context.TODO(), vrbNodeConfigs)).To(Succeed())
+		Expect(fecNodeConfigs.Items).To(BeEmpty())
+		Expect(vrbNodeConfigs.Items).To(BeEmpty())
+	})
+
+	It("should clear teardown marks of previous configs only", func() {
+		for name, uid := range map[string]string{"worker-1": "previous-config-uid", "worker-2": "config-uid"} {
+			node := getNode(name)
+			node.Annotations = map[string]string{utils.TeardownCompletedAnnotation: uid}
+			Expect(r.Update(context.TODO(), node)).To(Succeed())
+		}
+
+		Expect(r.clearTeardownMarks(context.TODO(), config)).To(Succeed())
+		Expect(getNode("worker-1").Annotations).ToNot(HaveKey(utils.TeardownCompletedAnnotation))
+		Expect(getNode("worker-2").Annotations).To(HaveKeyWithValue(utils.TeardownCompletedAnnotation, "config-uid"))
+	})
+})
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package sriovvrb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterConfigKind = "SriovVrbClusterConfig"
+	nodeConfigKind    = "SriovVrbNodeConfig"
+
+	// revisionHistoryLimit is the number of revisions kept for each config
+	revisionHistoryLimit = 10
+
+	resultInProgress = "InProgress"
+	resultSucceeded  = "Succeeded"
+	resultFailed     = "Failed"
+	resultHalted     = "Halted"
+
+	// rollbackRejected is the reason of events reporting rollback annotations which cannot be honored
+	rollbackRejected = "RollbackRejected"
+)
+
+/*****************************************************************************
+ * Method: SriovVrbClusterConfigReconciler::listRevisions
+ * Description: Returns revisions of the config sorted by revision number
+ ****************************************************************************/
+func (r *SriovVrbClusterConfigReconciler) listRevisions(kind, name string) ([]appsv1.ControllerRevision, error) {
+	list := new(appsv1.ControllerRevisionList)
+	err := r.List(context.TODO(), list, client.InNamespace(NAMESPACE),
+		client.MatchingLabels{utils.RevisionKindLabel: kind, utils.RevisionOfLabel: name})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Revision < list.Items[j].Revision
+	})
+	return list.Items, nil
+}
+
+func revisionName(kind, name string, data []byte) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%s-%s-%s", strings.ToLower(kind), name, rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())))
+}
+
+/*****************************************************************************
+ * Method: SriovVrbClusterConfigReconciler::recordRevision
+ * Description: Stores the spec of the config as its latest revision unless it
+ * 		is stored already. Revision of a spec which was applied before is
+ * 		renumbered instead of being duplicated. Returns the latest revision and
+ * 		true if it was recorded by this call.
+ ****************************************************************************/
+func (r *SriovVrbClusterConfigReconciler) recordRevision(owner client.Object, kind string, spec interface{}) (*appsv1.ControllerRevision, bool, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	revisions, err := r.listRevisions(kind, owner.GetName())
+	if err != nil {
+		return nil, false, err
+	}
+
+	name := revisionName(kind, owner.GetName(), data)
+	var latest int64
+	if n := len(revisions); n > 0 {
+		if revisions[n-1].Name == name {
+			return &revisions[n-1], false, nil
+		}
+		latest = revisions[n-1].Revision
+	}
+	generation := strconv.FormatInt(owner.GetGeneration(), 10)
+
+	for i := range revisions {
+		if revisions[i].Name != name {
+			continue
+		}
+		revision := revisions[i].DeepCopy()
+		revision.Revision = latest + 1
+		revision.Annotations = map[string]string{utils.RevisionGenerationAnnotation: generation}
+		if err := r.Update(context.TODO(), revision); err != nil {
+			return nil, false, err
+		}
+		return revision, true, nil
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       NAMESPACE,
+			Labels:          map[string]string{utils.RevisionKindLabel: kind, utils.RevisionOfLabel: owner.GetName()},
+			Annotations:     map[string]string{utils.RevisionGenerationAnnotation: generation},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, vrbv1.GroupVersion.WithKind(kind))},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: latest + 1,
+	}
+	if err := r.Create(context.TODO(), revision); err != nil {
+		return nil, false, err
+	}
+
+	for i := 0; i < len(revisions)+1-revisionHistoryLimit; i++ {
+		if err := r.Delete(context.TODO(), &revisions[i]); client.IgnoreNotFound(err) != nil {
+			r.Log.WithError(err).WithField("revision", revisions[i].Name).Error("failed to prune revision history")
+		}
+	}
+	return revision, true, nil
+}
+
+/*****************************************************************************
+ * Method: SriovVrbClusterConfigReconciler::recordResult
+ * Description: Stores the result of applying the revision and its timestamp
+ ****************************************************************************/
+func (r *SriovVrbClusterConfigReconciler) recordResult(revision *appsv1.ControllerRevision, result string) error {
+	if result == "" || revision.Annotations[utils.RevisionResultAnnotation] == result {
+		return nil
+	}
+	if revision.Annotations == nil {
+		revision.Annotations = map[string]string{}
+	}
+	revision.Annotations[utils.RevisionResultAnnotation] = result
+	revision.Annotations[utils.RevisionResultTimeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return r.Update(context.TODO(), revision)
+}
+
+// nodeConfigResult returns the outcome of applying given generation of the node config; empty when not known yet
+func nodeConfigResult(nc *vrbv1.SriovVrbNodeConfig, generation string) string {
+	configured := nc.FindCondition(nodeConfiguredCondition)
+	if configured == nil || strconv.FormatInt(configured.ObservedGeneration, 10) != generation {
+		return ""
+	}
+	switch configured.Reason {
+	case resultInProgress, "":
+		return ""
+	case resultFailed:
+		return fmt.Sprintf("%s: %s", resultFailed, configured.Message)
+	}
+	return configured.Reason
+}
+
+/*****************************************************************************
+ * Method: SriovVrbClusterConfigReconciler::recordRevisions
+ * Description: Records revisions of ClusterConfigs and NodeConfigs together
+ * 		with the results of applying them
+ ****************************************************************************/
+func (r *SriovVrbClusterConfigReconciler) recordRevisions(clusterConfigs []vrbv1.SriovVrbClusterConfig, nodes []corev1.Node) {
+	// key: node name, value: result of applying current generation of its node config
+	nodeResults := map[string]string{}
+	for _, node := range nodes {
+		nc := new(vrbv1.SriovVrbNodeConfig)
+		if err := r.Get(context.TODO(), client.ObjectKey{Name: node.Name, Namespace: NAMESPACE}, nc); err != nil {
+			r.Log.WithError(client.IgnoreNotFound(err)).WithField("name", node.Name).Debug("no node config to record revision of")
+			continue
+		}
+		revision, _, err := r.recordRevision(nc, nodeConfigKind, nc.Spec)
+		if err != nil {
+			r.Log.WithError(err).WithField("name", nc.Name).Error("failed to record revision of SriovVrbNodeConfig")
+			continue
+		}
+		nodeResults[node.Name] = nodeConfigResult(nc, revision.Annotations[utils.RevisionGenerationAnnotation])
+		if err := r.recordResult(revision, nodeResults[node.Name]); err != nil {
+			r.Log.WithError(err).WithField("revision", revision.Name).Error("failed to record result of revision")
+		}
+	}
+
+	for i := range clusterConfigs {
+		cc := &clusterConfigs[i]
+		revision, recorded, err := r.recordRevision(cc, clusterConfigKind, cc.Spec)
+		if err != nil {
+			r.Log.WithError(err).WithField("name", cc.Name).Error("failed to record revision of SriovVrbClusterConfig")
+			continue
+		}
+		// node configs are updated in the same reconcile in which the revision is recorded; their results are not known yet
+		if recorded || revision.Annotations[utils.RevisionGenerationAnnotation] != strconv.FormatInt(cc.GetGeneration(), 10) {
+			continue
+		}
+		if err := r.recordResult(revision, clusterConfigResult(cc, nodes, nodeResults)); err != nil {
+			r.Log.WithError(err).WithField("revision", revision.Name).Error("failed to record result of revision")
+		}
+	}
+}
+
+// clusterConfigResult aggregates results of node configs of nodes selected by the ClusterConfig; empty when not known yet
+func clusterConfigResult(cc *vrbv1.SriovVrbClusterConfig, nodes []corev1.Node, nodeResults map[string]string) string {
+	if rollout := cc.Status.Rollout; rollout != nil && rollout.Generation == cc.GetGeneration() {
+		if rollout.Phase == vrbv1.RolloutPhaseHalted {
+			return fmt.Sprintf("%s: %s", resultHalted, rollout.Message)
+		}
+		if rollout.Phase != vrbv1.RolloutPhaseCompleted {
+			return ""
+		}
+	}
+
+	selector := labels.Set(cc.Spec.NodeSelector).AsSelector()
+	var succeeded, selected int
+	var failed []string
+	for _, node := range nodes {
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		selected++
+		switch result := nodeResults[node.Name]; {
+		case result == resultSucceeded:
+			succeeded++
+		case strings.HasPrefix(result, resultFailed):
+			failed = append(failed, node.Name)
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return fmt.Sprintf("%s on nodes: %s", resultFailed, strings.Join(failed, ", "))
+	case succeeded < selected:
+		return ""
+	}
+	return fmt.Sprintf("%s on %d nodes", resultSucceeded, succeeded)
+}
+
+/*****************************************************************************
+ * Method: SriovVrbClusterConfigReconciler::rollback
+ * Description: Restores spec of the ClusterConfig from the revision requested
+ * 		by its rollback annotation. The restored spec is applied by the usual
+ * 		reconcile path as any other change of the ClusterConfig. Annotation
+ * 		requesting unknown revision is removed and reported by an event.
+ ****************************************************************************/
+func (r *SriovVrbClusterConfigReconciler) rollback(cc *vrbv1.SriovVrbClusterConfig) error {
+	value, requested := cc.Annotations[utils.RollbackToRevisionAnnotation]
+	if !requested {
+		return nil
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return r.rejectRollback(cc, fmt.Sprintf("invalid revision %q: %v", value, err))
+	}
+
+	revisions, err := r.listRevisions(clusterConfigKind, cc.Name)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		if revision.Revision != number {
+			continue
+		}
+		spec := vrbv1.SriovVrbClusterConfigSpec{}
+		if err := json.Unmarshal(revision.Data.Raw, &spec); err != nil {
+			return r.rejectRollback(cc, fmt.Sprintf("failed to decode revision %s: %v", revision.Name, err))
+		}
+		cc.Spec = spec
+		delete(cc.Annotations, utils.RollbackToRevisionAnnotation)
+		r.Log.WithField("name", cc.Name).WithField("revision", number).Info("rolling back SriovVrbClusterConfig")
+		return r.Update(context.TODO(), cc)
+	}
+	return r.rejectRollback(cc, fmt.Sprintf("revision %d not found", number))
+}
+
+// rejectRollback drops the rollback annotation which cannot be honored, so that it is not retried, and reports why
+func (r *SriovVrbClusterConfigReconciler) rejectRollback(cc *vrbv1.SriovVrbClusterConfig, reason string) error {
+	r.Log.WithField("name", cc.Name).WithField("reason", reason).Warn("rejecting rollback of SriovVrbClusterConfig")
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cc, corev1.EventTypeWarning, rollbackRejected, "%s annotation removed: %s", utils.RollbackToRevisionAnnotation, reason)
+	}
+	delete(cc.Annotations, utils.RollbackToRevisionAnnotation)
+	return r.Update(context.TODO(), cc)
+}
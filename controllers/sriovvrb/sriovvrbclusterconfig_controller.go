@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +34,9 @@ var NAMESPACE = os.Getenv("SRIOV_FEC_NAMESPACE")
 type SriovVrbClusterConfigReconciler struct {
 	client.Client
 	Log *logrus.Logger
+
+	// Recorder reports rollbacks which cannot be honored; optional
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=sriovvrb.intel.com,resources=sriovvrbclusterconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -42,10 +46,13 @@ type SriovVrbClusterConfigReconciler struct {
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=list;get;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=namespaces;serviceaccounts;secrets;configmaps,verbs=get;list;create;update
 // +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;deployments/finalizers,verbs=get;list;create;update
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=get;list;create;update
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=use,resourceNames=privileged
 // +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=nodemaintenance.medik8s.io,resources=nodemaintenances,verbs=get;create;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -66,8 +73,21 @@ func (r *SriovVrbClusterConfigReconciler) Reconcile(ctx context.Context, req ctr
 		return reconcile.Result{}, err
 	}
 
+	for i := range clusterConfigList.Items {
+		if err := r.rollback(&clusterConfigList.Items[i]); err != nil {
+			r.Log.WithError(err).WithField("name", clusterConfigList.Items[i].Name).Error("failed to roll back SriovVrbClusterConfig")
+		}
+	}
+
+	rollouts := r.progressRollouts(clusterConfigList.Items, nodes)
+
 	clusterConfigurationMatcher := createClusterConfigMatcher(r.getOrInitializeSriovVrbNodeConfig, r.Log)
 	for _, node := range nodes {
+		if cc := rollouts.holdsBack(node); cc != "" {
+			r.Log.WithField("node", node.Name).WithField("clusterConfig", cc).Info("node is held back until canary nodes are verified")
+			continue
+		}
+
 		configurationContextProvider, err := clusterConfigurationMatcher.match(node, clusterConfigList.Items)
 		if err != nil {
 			r.Log.WithField("node", node.Name).WithField("error", err).Info("Error when matching SriovVrbClusterConfigs")
@@ -97,6 +117,8 @@ func (r *SriovVrbClusterConfigReconciler) Reconcile(ctx context.Context, req ctr
 		}
 	}
 
+	r.recordRevisions(clusterConfigList.Items, nodes)
+
 	return r.requeueIfClusterConfigExists(req.NamespacedName)
 }
 
@@ -127,6 +149,21 @@ func (r *SriovVrbClusterConfigReconciler) synchronizeNodeConfigSpec(ncc NodeConf
 
 	newNodeConfig := copyWithEmptySpec(ncc.SriovVrbNodeConfig)
 
+	// drainPolicy is propagated into NodeConfig only if any of matching ClusterConfigs requests it explicitly
+	var drainPolicy vrbv1.DrainPolicy
+	drainPolicyRequested := false
+
+	// drainSettings are taken from the highest prioritized ClusterConfig providing them
+	var drainSettings *vrbv1.DrainSettings
+	drainSettingsPriority := 0
+
+	// approvalPolicy is Manual if any of matching ClusterConfigs requires it
+	var approvalPolicy vrbv1.ApprovalPolicy
+
+	// hooks are taken from the highest prioritized ClusterConfig providing them
+	var hooks *vrbv1.ReconfigurationHooks
+	hooksPriority := 0
+
 	// Use orderedmap for iteration
 	for _, pciAddress := range acceleratorConfigContext.Keys() {
 		cc, _ := acceleratorConfigContext.Get(pciAddress)
@@ -137,18 +174,48 @@ func (r *SriovVrbClusterConfigReconciler) synchronizeNodeConfigSpec(ncc NodeConf
 			VFAmount:        cc.Spec.PhysicalFunction.VFAmount,
 			BBDevConfig:     cc.Spec.PhysicalFunction.BBDevConfig,
 			VrbResourceName: cc.Spec.VrbResourceName,
+			VFGroups:        cc.Spec.PhysicalFunction.VFGroups,
 		}
 		if cc.Spec.DrainSkip == nil {
 			newNodeConfig.Spec.DrainSkip = true
 		} else if cc.Spec.DrainSkip != nil {
 			newNodeConfig.Spec.DrainSkip = newNodeConfig.Spec.DrainSkip || *cc.Spec.DrainSkip
 		}
+		if cc.Spec.DrainPolicy != "" {
+			drainPolicyRequested = true
+		}
+		if drainPolicy == "" {
+			drainPolicy = cc.Spec.EffectiveDrainPolicy()
+		} else {
+			drainPolicy = drainPolicy.LeastDisruptive(cc.Spec.EffectiveDrainPolicy())
+		}
+		if cc.Spec.DrainSettings != nil && (drainSettings == nil || cc.Spec.Priority > drainSettingsPriority) {
+			drainSettings = cc.Spec.DrainSettings.DeepCopy()
+			drainSettingsPriority = cc.Spec.Priority
+		}
+		approvalPolicy = approvalPolicy.MostRestrictive(cc.Spec.ApprovalPolicy)
+		if cc.Spec.Hooks != nil && (hooks == nil || cc.Spec.Priority > hooksPriority) {
+			hooks = cc.Spec.Hooks.DeepCopy()
+			hooksPriority = cc.Spec.Priority
+		}
 		newNodeConfig.Spec.PhysicalFunctions = append(newNodeConfig.Spec.PhysicalFunctions, pf)
 	}
 
-	// Copy latest known drainSkip from NodeConfig for cleanup
+	newNodeConfig.Spec.DrainSettings = drainSettings
+	newNodeConfig.Spec.Hooks = hooks
+	newNodeConfig.Spec.ApprovalPolicy = approvalPolicy
+
+	if drainPolicyRequested {
+		newNodeConfig.Spec.DrainPolicy = drainPolicy
+	}
+
+	// Copy latest known drain configuration from NodeConfig for cleanup
 	if acceleratorConfigContext.Len() == 0 {
 		newNodeConfig.Spec.DrainSkip = ncc.Spec.DrainSkip
+		newNodeConfig.Spec.DrainPolicy = ncc.Spec.DrainPolicy
+		newNodeConfig.Spec.DrainSettings = ncc.Spec.DrainSettings
+		newNodeConfig.Spec.Hooks = ncc.Spec.Hooks
+		newNodeConfig.Spec.ApprovalPolicy = ncc.Spec.ApprovalPolicy
 	}
 
 	// Sort the physical functions by PCI address to ensure consistent order
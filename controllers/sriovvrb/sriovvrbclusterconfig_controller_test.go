@@ -157,7 +157,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 		}
 
 		reconcile := func(ccName string) *SriovVrbClusterConfigReconciler {
-			reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+			reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 			_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest(ccName))
 			Expect(err).ToNot(HaveOccurred())
 			return &reconciler
@@ -417,7 +417,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("cc1"))
 				Expect(err).ToNot(HaveOccurred())
@@ -465,7 +465,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 				ccs := []string{"cc1", "cc2"}
 				for i := 0; i < 100; i++ {
 					cc := ccs[i%len(ccs)]
@@ -678,7 +678,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 					}
 				})
 
-				reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("cc"))
 				Expect(err).ToNot(HaveOccurred())
 
@@ -714,7 +714,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 						}
 					})
 
-					reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+					reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 					_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 					Expect(err).ToNot(HaveOccurred())
 
@@ -769,7 +769,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 						}
 					})
 
-					reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+					reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 					_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 					Expect(err).ToNot(HaveOccurred())
 
@@ -819,7 +819,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 					cc.Spec.DrainSkip = &tmp
 				})
 
-				reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest("config"))
 				Expect(err).ToNot(HaveOccurred())
 
@@ -838,7 +838,7 @@ var _ = Describe("SriovVrbControllerTest", func() {
 				cc.Namespace = v1.NamespaceSystem
 				Expect(k8sClient.Create(context.TODO(), cc)).ToNot(HaveOccurred())
 
-				reconciler := SriovVrbClusterConfigReconciler{k8sClient, log}
+				reconciler := SriovVrbClusterConfigReconciler{Client: k8sClient, Log: log}
 				_, err := reconciler.Reconcile(context.TODO(), createDummyReconcileRequest(clusterConfigPrototype.Name))
 				Expect(err).ToNot(HaveOccurred())
 
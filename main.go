@@ -25,9 +25,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"time"
+	"path/filepath"
 
 	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -57,6 +58,8 @@ import (
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = utils.NewLogger()
+	// operatorLoggers are loggers whose level is controlled by SriovFecOperatorConfig
+	operatorLoggers = []*logrus.Logger{setupLog}
 )
 
 func init() {
@@ -70,6 +73,14 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderCommand {
+		if err := renderManifests(os.Args[2:], os.Stdout); err != nil {
+			setupLog.WithError(err).Error("failed to render manifests")
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var healthProbeAddr string
 	var enableLeaderElection bool
@@ -87,6 +98,7 @@ func main() {
 
 	initializeSriovFecClusterConfigReconciler(mgr)
 	initializeVrbClusterConfigReconciler(mgr)
+	initializeDevicePluginConfigReconciler(mgr)
 	// +kubebuilder:scaffold:builder
 
 	c := createClient(config)
@@ -97,6 +109,8 @@ func main() {
 
 	deployOperatorAssets(c, operatorDeployment)
 
+	initializeOperatorConfigReconciler(mgr, c, operatorDeployment)
+
 	isSingleNode, err := utils.IsSingleNodeCluster(c)
 	if err != nil {
 		setupLog.WithError(err).Error("failed to get Nodes information")
@@ -118,30 +132,51 @@ func main() {
 	}
 }
 
-func deployOperatorAssets(c client.Client, operatorDeployment *appsv1.Deployment) {
+// newAssetsManager creates manager of daemon, labeler and device plugin assets; deployment does not wait until
+// objects of the assets are ready, their readiness is reported by the SriovFecOperatorConfig reconciler
+func newAssetsManager(c client.Client, operatorDeployment *appsv1.Deployment) *assets.Manager {
 	logger := utils.NewLogger()
-	assetsManager := &assets.Manager{
+	operatorLoggers = append(operatorLoggers, logger)
+
+	return &assets.Manager{
 		Client:    c,
 		Namespace: controllers.NAMESPACE,
 		Log:       logger,
 		EnvPrefix: utils.SriovPrefix,
 		Scheme:    scheme,
 		Owner:     operatorDeployment,
-		Assets: []assets.Asset{
-			{
-				ConfigMapName: "labeler-config",
-				Path:          "assets/100-labeler.yaml",
-			},
-			{
-				ConfigMapName: "device-plugin-config",
-				Path:          "assets/200-device-plugin.yaml",
-			},
-			{
-				ConfigMapName:     "daemon-config",
-				Path:              "assets/300-daemon.yaml",
-				BlockingReadiness: assets.ReadinessPollConfig{Retries: 30, Delay: 20 * time.Second},
-			},
+		Assets:    operatorAssets("assets"),
+	}
+}
+
+// operatorAssets lists assets of the daemon, labeler and device plugin located in given directory
+func operatorAssets(dir string) []assets.Asset {
+	return []assets.Asset{
+		{
+			ConfigMapName: "labeler-config",
+			Path:          filepath.Join(dir, "100-labeler.yaml"),
+		},
+		{
+			ConfigMapName: "device-plugin-config",
+			Path:          filepath.Join(dir, "200-device-plugin.yaml"),
 		},
+		{
+			ConfigMapName: "daemon-config",
+			Path:          filepath.Join(dir, "300-daemon.yaml"),
+		},
+	}
+}
+
+func deployOperatorAssets(c client.Client, operatorDeployment *appsv1.Deployment) {
+	assetsManager := newAssetsManager(c, operatorDeployment)
+
+	operatorConfig, err := controllers.GetOperatorConfig(context.Background(), c)
+	if err != nil {
+		setupLog.WithError(err).Error("failed to get SriovFecOperatorConfig")
+		os.Exit(1)
+	}
+	if err := controllers.ConfigureAssets(assetsManager, operatorConfig); err != nil {
+		setupLog.WithError(err).Error("invalid SriovFecOperatorConfig, deploying assets with defaults")
 	}
 
 	if err := assetsManager.DeployConfigMaps(context.Background(), false); err != nil {
@@ -155,6 +190,24 @@ func deployOperatorAssets(c client.Client, operatorDeployment *appsv1.Deployment
 	}
 }
 
+// initializeOperatorConfigReconciler redeploys assets whenever SriovFecOperatorConfig changes or objects deployed
+// from them drift; it uses uncached client to read ConfigMaps of assets right after they are updated
+func initializeOperatorConfigReconciler(mgr manager.Manager, c client.Client, operatorDeployment *appsv1.Deployment) {
+	log := utils.NewLogger()
+	operatorLoggers = append(operatorLoggers, log)
+	assetsManager := newAssetsManager(c, operatorDeployment)
+	if err := (&controllers.SriovFecOperatorConfigReconciler{
+		Client:          mgr.GetClient(),
+		Log:             log,
+		AssetsManager:   assetsManager,
+		OperatorLoggers: operatorLoggers,
+		Recorder:        mgr.GetEventRecorderFor("sriov-fec-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.WithField("controller", "SriovFecOperatorConfig").WithError(err).Error("unable to create controller")
+		os.Exit(1)
+	}
+}
+
 func determineClusterType(config *rest.Config) {
 	if err := getClusterType(config); err != nil {
 		setupLog.Error(err, "unable to determine cluster type")
@@ -173,9 +226,11 @@ func createClient(config *rest.Config) client.Client {
 
 func initializeSriovFecClusterConfigReconciler(mgr manager.Manager) {
 	log := utils.NewLogger()
+	operatorLoggers = append(operatorLoggers, log)
 	if err := (&controllers.SriovFecClusterConfigReconciler{
-		Client: mgr.GetClient(),
-		Log:    log,
+		Client:   mgr.GetClient(),
+		Log:      log,
+		Recorder: mgr.GetEventRecorderFor("sriov-fec-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.WithField("controller", "SriovFecClusterConfig").WithError(err).Error("unable to create controller")
 		os.Exit(1)
@@ -188,9 +243,11 @@ func initializeSriovFecClusterConfigReconciler(mgr manager.Manager) {
 
 func initializeVrbClusterConfigReconciler(mgr manager.Manager) {
 	log := utils.NewLogger()
+	operatorLoggers = append(operatorLoggers, log)
 	if err := (&vrbcontrollers.SriovVrbClusterConfigReconciler{
-		Client: mgr.GetClient(),
-		Log:    log,
+		Client:   mgr.GetClient(),
+		Log:      log,
+		Recorder: mgr.GetEventRecorderFor("sriov-fec-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.WithField("controller", "SriovVrbClusterConfig").WithError(err).Error("unable to create controller")
 		os.Exit(1)
@@ -201,6 +258,19 @@ func initializeVrbClusterConfigReconciler(mgr manager.Manager) {
 	}
 }
 
+// initializeDevicePluginConfigReconciler generates node specific configs of sriov-device-plugin serving custom resource names
+func initializeDevicePluginConfigReconciler(mgr manager.Manager) {
+	log := utils.NewLogger()
+	operatorLoggers = append(operatorLoggers, log)
+	if err := (&controllers.DevicePluginConfigReconciler{
+		Client: mgr.GetClient(),
+		Log:    log,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.WithField("controller", "DevicePluginConfig").WithError(err).Error("unable to create controller")
+		os.Exit(1)
+	}
+}
+
 func createAndConfigureManager(config *rest.Config, metricsAddr string, healthProbeAddr string, enableLeaderElection bool) manager.Manager {
 	ws := webhook.Server{
 		TLSMinVersion: "1.2",
@@ -7,14 +7,18 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
 	secv1 "github.com/openshift/api/security/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -65,10 +69,19 @@ type Asset struct {
 	// BlockingReadiness stores polling configuration.
 	BlockingReadiness ReadinessPollConfig
 
+	// Tolerations added to DaemonSets of the asset on top of tolerations propagated from the operator
+	Tolerations []corev1.Toleration
+
+	// PerNodeGroup renders DaemonSets of the asset once per group of accelerator nodes when the manager groups nodes
+	PerNodeGroup bool
+
 	substitutions map[string]string
 
 	objects []client.Object
 
+	// changed holds objects created or updated by the last createOrUpdate
+	changed []client.Object
+
 	log *logrus.Logger
 }
 
@@ -89,7 +102,9 @@ func (a *Asset) loadFromFile() error {
 		return err
 	}
 
-	t, err := template.New("asset").Funcs(template.FuncMap{"ToLower": strings.ToLower}).Option("missingkey=error").Parse(string(content))
+	a.clearAllObjects()
+
+	t, err := template.New("asset").Funcs(template.FuncMap{"ToLower": strings.ToLower, "Contains": strings.Contains}).Option("missingkey=error").Parse(string(content))
 	if err != nil {
 		return err
 	}
@@ -137,6 +152,59 @@ func (a *Asset) loadFromConfigMap(ctx context.Context, c client.Client, ns strin
 	return nil
 }
 
+// renderObjects decodes objects held by ConfigMaps of the asset, as LoadFromConfigMap does, and adds
+// the reconfiguring toleration and tolerations of the asset to DaemonSets
+func (a *Asset) renderObjects() ([]*unstructured.Unstructured, error) {
+	var rendered []*unstructured.Unstructured
+	for _, obj := range a.objects {
+		configMap, ok := obj.(*unstructured.Unstructured)
+		if !ok || configMap.GetKind() != "ConfigMap" {
+			return nil, fmt.Errorf("%s %s is not a ConfigMap", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+		}
+		data, _, err := unstructured.NestedStringMap(configMap.Object, "data")
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(data[key]), 4096)
+			obj := new(unstructured.Unstructured)
+			if err := decoder.Decode(obj); err != nil {
+				return nil, fmt.Errorf("failed to decode %s of ConfigMap %s: %w", key, configMap.GetName(), err)
+			}
+			if obj.GetKind() == "DaemonSet" {
+				if err := addTolerations(obj, append([]corev1.Toleration{reconfiguringToleration}, a.Tolerations...)); err != nil {
+					return nil, err
+				}
+			}
+			rendered = append(rendered, obj)
+		}
+	}
+	return rendered, nil
+}
+
+func addTolerations(ds *unstructured.Unstructured, tolerations []corev1.Toleration) error {
+	path := []string{"spec", "template", "spec", "tolerations"}
+	existing, _, err := unstructured.NestedSlice(ds.Object, path...)
+	if err != nil {
+		return err
+	}
+	for i := range tolerations {
+		toleration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&tolerations[i])
+		if err != nil {
+			return err
+		}
+		existing = append(existing, toleration)
+	}
+	return unstructured.SetNestedSlice(ds.Object, existing, path...)
+}
+
 func (a *Asset) clearAllObjects() {
 	a.objects = nil
 }
@@ -163,6 +231,7 @@ func (a *Asset) setOwner(owner metav1.Object, obj runtime.Object, s *runtime.Sch
 }
 
 func (a *Asset) createOrUpdate(ctx context.Context, c client.Client, owner metav1.Object, s *runtime.Scheme) error {
+	a.changed = nil
 	for _, obj := range a.objects {
 		if err := a.createOrUpdateObject(ctx, c, obj, owner, s); err != nil {
 			return err
@@ -186,7 +255,7 @@ func (a *Asset) createOrUpdateObject(ctx context.Context, c client.Client, toBeC
 	key := client.ObjectKeyFromObject(toBeCreated)
 
 	if strings.EqualFold(gvk.Kind, "daemonset") {
-		toBeCreated, err = propagateTolerations(c, a.log, toBeCreated)
+		toBeCreated, err = propagateTolerations(c, a.log, toBeCreated, a.Tolerations)
 		if err != nil {
 			return err
 		}
@@ -199,7 +268,11 @@ func (a *Asset) createOrUpdateObject(ctx context.Context, c client.Client, toBeC
 		}
 		return a.createObject(ctx, c, toBeCreated, key, gvk)
 	} else {
-		if strings.EqualFold(old.GetObjectKind().GroupVersionKind().Kind, "configmap") {
+		if old.GetAnnotations()[utils.UnmanagedAnnotation] == "true" {
+			a.log.WithField("key", key).WithField("GroupVersionKind", gvk).Info("Skipping update because it is marked as unmanaged")
+			return nil
+		}
+		if kind := old.GetObjectKind().GroupVersionKind().Kind; strings.EqualFold(kind, "configmap") || strings.EqualFold(kind, "secret") {
 			isImmutable, ok := old.Object["immutable"].(bool)
 			if !ok {
 				a.log.WithField("key", key).WithField("GroupVersionKind", gvk).
@@ -219,24 +292,80 @@ func (a *Asset) createObject(ctx context.Context, c client.Client, toBeCreated c
 		return err
 	}
 	a.log.WithField("key", key).WithField("GroupVersionKind", gvk).Info("Object created")
+	a.changed = append(a.changed, toBeCreated)
 	return nil
 }
 
-func (a *Asset) updateObject(ctx context.Context, c client.Client, toBeCreated, old client.Object, key client.ObjectKey, gvk schema.GroupVersionKind) error {
-	if !equality.Semantic.DeepDerivative(toBeCreated, old) {
+func (a *Asset) updateObject(ctx context.Context, c client.Client, toBeCreated client.Object, old *unstructured.Unstructured, key client.ObjectKey, gvk schema.GroupVersionKind) error {
+	matches, err := matchesDesiredState(toBeCreated, old)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		toBeCreated = withDevicePluginNodeConfigs(toBeCreated, old)
 		toBeCreated.SetResourceVersion(old.GetResourceVersion())
 		if err := c.Update(ctx, toBeCreated); err != nil {
 			a.log.WithError(err).WithField("key", key).WithField("GroupVersionKind", gvk).Error("Update failed")
 			return err
 		}
 		a.log.WithField("key", key).WithField("GroupVersionKind", gvk).Info("Object updated")
+		a.changed = append(a.changed, toBeCreated)
 	} else {
 		a.log.WithField("key", key).WithField("GroupVersionKind", gvk).Info("Object has not changed")
 	}
 	return nil
 }
 
-func propagateTolerations(c client.Client, log *logrus.Logger, toBeCreated client.Object) (client.Object, error) {
+// matchesDesiredState compares unstructured representations of objects, so that typed desired object (e.g. DaemonSet
+// with propagated tolerations) is comparable with the current one; fields not set in desired object are not compared
+func matchesDesiredState(desired client.Object, current *unstructured.Unstructured) (bool, error) {
+	desiredState, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired.DeepCopyObject())
+	if err != nil {
+		return false, err
+	}
+	delete(desiredState, "status")
+	return equality.Semantic.DeepDerivative(desiredState, current.Object), nil
+}
+
+// withDevicePluginNodeConfigs returns a copy of re-applied sriovdp-config keeping node specific configs generated by
+// the operator, which are not part of the asset; other objects are returned as they are
+func withDevicePluginNodeConfigs(desired client.Object, current *unstructured.Unstructured) client.Object {
+	cm, ok := desired.(*unstructured.Unstructured)
+	if !ok || !strings.EqualFold(cm.GetKind(), "configmap") || cm.GetName() != deviceplugin.ConfigMapName {
+		return desired
+	}
+	currentData, _, _ := unstructured.NestedStringMap(current.Object, "data")
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	if data == nil {
+		data = map[string]string{}
+	}
+	preserved := false
+	for key, value := range currentData {
+		if _, desired := data[key]; !desired && deviceplugin.IsNodeConfigKey(key) {
+			data[key] = value
+			preserved = true
+		}
+	}
+	if !preserved {
+		return desired
+	}
+
+	cm = cm.DeepCopy()
+	if err := unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+		return desired
+	}
+	return cm
+}
+
+// reconfiguringToleration allows operands (e.g. restarted sriov-device-plugin) to be scheduled on the node
+// which is tainted by sriov-fec-daemon for the time of accelerators reconfiguration
+var reconfiguringToleration = corev1.Toleration{
+	Key:      utils.ReconfiguringTaintKey,
+	Operator: corev1.TolerationOpExists,
+	Effect:   corev1.TaintEffectNoSchedule,
+}
+
+func propagateTolerations(c client.Client, log *logrus.Logger, toBeCreated client.Object, additional []corev1.Toleration) (client.Object, error) {
 	managerDeployment := FetchOperatorDeployment(c, log)
 	log.WithField("name", toBeCreated.GetName()).WithField("tolerations", managerDeployment.Spec.Template.Spec.Tolerations).
 		Info("propagating tolerations to daemonset")
@@ -249,45 +378,35 @@ func propagateTolerations(c client.Client, log *logrus.Logger, toBeCreated clien
 	if err != nil {
 		return nil, err
 	}
-	ds.Spec.Template.Spec.Tolerations = managerDeployment.Spec.Template.Spec.Tolerations
+	ds.Spec.Template.Spec.Tolerations = append(managerDeployment.Spec.Template.Spec.Tolerations, reconfiguringToleration)
+	ds.Spec.Template.Spec.Tolerations = append(ds.Spec.Template.Spec.Tolerations, additional...)
 	return ds, nil
 }
 
-func (a *Asset) waitUntilReady(ctx context.Context, apiReader client.Reader) error {
+// waitUntilReady polls readiness checks of objects of the asset as configured by BlockingReadiness
+func (a *Asset) waitUntilReady(ctx context.Context, apiReader client.Reader, checkFor func(kind string) ReadinessCheck) error {
 	if a.BlockingReadiness.Retries == 0 {
 		return nil
 	}
 
-	for _, obj := range a.objects {
-		if obj.GetObjectKind().GroupVersionKind().Kind == "DaemonSet" {
-			a.log.WithField("asset", a.Path).Info("waiting until daemonset is ready")
-
-			backoff := wait.Backoff{
-				Steps:    a.BlockingReadiness.Retries,
-				Duration: a.BlockingReadiness.Delay,
-				Factor:   1,
-			}
-			f := func() (bool, error) {
-				objKey := client.ObjectKeyFromObject(obj)
-				ds := &appsv1.DaemonSet{}
-				err := apiReader.Get(ctx, objKey, ds)
-				if err != nil {
-					return false, err
-				}
-
-				a.log.WithFields(logrus.Fields{"name": ds.GetName(),
-					"NumberUnavailable":      ds.Status.NumberUnavailable,
-					"DesiredNumberScheduled": ds.Status.DesiredNumberScheduled}).
-					Info("daemonset status")
-
-				return ds.Status.NumberUnavailable == 0, nil
-			}
-
-			if err := wait.ExponentialBackoff(backoff, f); err != nil {
-				a.log.WithError(err).Error("wait for daemonset failed")
-				return err
-			}
+	a.log.WithField("asset", a.Path).Info("waiting until objects of the asset are ready")
+	backoff := wait.Backoff{
+		Steps:    a.BlockingReadiness.Retries,
+		Duration: a.BlockingReadiness.Delay,
+		Factor:   1,
+	}
+	f := func() (bool, error) {
+		notReady, err := a.notReady(ctx, apiReader, checkFor)
+		if err != nil {
+			return false, err
 		}
+		a.log.WithField("asset", a.Path).WithField("notReady", notReady).Info("asset readiness")
+		return len(notReady) == 0, nil
+	}
+
+	if err := wait.ExponentialBackoff(backoff, f); err != nil {
+		a.log.WithError(err).Error("wait for asset readiness failed")
+		return err
 	}
 	return nil
 }
@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/intel/sriov-fec-operator/pkg/common/utils"
@@ -17,10 +19,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// externalDevicePluginName names the DaemonSet of sriov-network-device-plugin
+const externalDevicePluginName = "sriov-device-plugin"
+
 // Manager loads & deploys assets specified in the Asset field
 type Manager struct {
 	Client    client.Client
@@ -32,16 +38,58 @@ type Manager struct {
 	// Prefix used to gather environment variables for the templating the assets
 	EnvPrefix string
 
+	// Template variables taking precedence over environment variables
+	Overrides map[string]string
+
 	// Can be removed after sigs.k8s.io/controller-runtime v0.7.0 release where client.Scheme() is available
 	Scheme *runtime.Scheme
 
 	Owner metav1.Object
+
+	// NodeGrouping groups accelerator nodes for assets rendered per node group: NodeGroupingKernel groups them by
+	// kernel version, any other value by the value of the node label with that key; nodes are not grouped when empty
+	NodeGrouping string
+
+	// ReadinessChecks override checks of objects of given kinds used by CheckReadiness and BlockingReadiness of assets
+	ReadinessChecks map[string]ReadinessCheck
+
+	// nodeGroupsSignature identifies node groups the assets were rendered for by the last LoadFromFile
+	nodeGroupsSignature string
+
+	// featureGates parsed from template variables by the last LoadFromFile or Render
+	featureGates utils.FeatureGates
+
+	// objects created or updated by Deploy since the last TakeChanged
+	changed []client.Object
 }
 
 // buildTemplateVars creates map with variables for templating.
 // Template variables are env variables with specified prefix and additional information
 // from cluster such as kernel
 func (m *Manager) buildTemplateVars(ctx context.Context, setKernelVar bool) (map[string]string, error) {
+	tp, err := m.templateVars()
+	if err != nil || !setKernelVar {
+		return tp, err
+	}
+
+	nodes := &corev1.NodeList{}
+	err = m.Client.List(ctx, nodes, &client.MatchingLabels{acceleratorNodeLabel: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes.Items) == 0 {
+		m.Log.Error("received empty node list")
+		return nil, errors.New("empty node list while building template vars")
+	}
+
+	tp["kernel"] = nodes.Items[0].Status.NodeInfo.KernelVersion
+
+	return tp, nil
+}
+
+// templateVars creates map with variables for templating which do not depend on the cluster
+func (m *Manager) templateVars() (map[string]string, error) {
 	tp := make(map[string]string)
 
 	for _, pair := range os.Environ() {
@@ -51,28 +99,48 @@ func (m *Manager) buildTemplateVars(ctx context.Context, setKernelVar bool) (map
 		}
 	}
 
+	for key, value := range m.Overrides {
+		tp[key] = value
+	}
+
 	m.setDefaultValues(&tp)
 	if err := m.validateUUID(tp); err != nil {
 		return tp, err
 	}
-	if !setKernelVar {
-		return tp, nil
+	if err := m.setFeatureGateVars(tp); err != nil {
+		return tp, err
 	}
+	return tp, nil
+}
 
-	nodes := &corev1.NodeList{}
-	err := m.Client.List(ctx, nodes, &client.MatchingLabels{"fpga.intel.com/intel-accelerator-present": ""})
+// setFeatureGateVars parses feature gates the same way the daemon does and exposes the state of every known gate
+// as <prefix>FEATURE_<NAME> template variable, e.g. SRIOV_FEC_FEATURE_DEVICE_PLUGIN="true"; gates passed to the
+// daemon are normalized
+func (m *Manager) setFeatureGateVars(tp map[string]string) error {
+	gatesName := m.EnvPrefix + "FEATURE_GATES"
+	gates, err := utils.ParseFeatureGates(tp[gatesName])
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("invalid %s: %w", gatesName, err)
 	}
+	m.featureGates = gates
 
-	if len(nodes.Items) == 0 {
-		m.Log.Error("received empty node list")
-		return nil, errors.New("empty node list while building template vars")
+	tp[gatesName] = gates.String()
+	for name := range utils.KnownFeatureGates {
+		tp[m.EnvPrefix+"FEATURE_"+featureGateVarName(name)] = strconv.FormatBool(gates.Enabled(name))
 	}
+	return nil
+}
 
-	tp["kernel"] = nodes.Items[0].Status.NodeInfo.KernelVersion
-
-	return tp, nil
+// featureGateVarName converts the name of the feature gate to upper snake case, e.g. DevicePlugin to DEVICE_PLUGIN
+func featureGateVarName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
 }
 
 func (m *Manager) validateUUID(tp map[string]string) error {
@@ -92,6 +160,15 @@ func (m *Manager) setDefaultValues(tp *map[string]string) {
 		m.EnvPrefix + "ACC100_RESOURCE_NAME": "intel_fec_acc100",
 		m.EnvPrefix + "ACC200_RESOURCE_NAME": "intel_fec_acc200",
 		m.EnvPrefix + "VRB2_RESOURCE_NAME":   "intel_vrb_vrb2",
+
+		m.EnvPrefix + "METRIC_GATHER_INTERVAL": "0s",
+		m.EnvPrefix + "DRAIN_TIMEOUT_SECONDS":  "90",
+		m.EnvPrefix + "LEASE_DURATION_SECONDS": "600",
+		m.EnvPrefix + "DAEMON_NODE_SELECTOR":   `{"fpga.intel.com/intel-accelerator-present": ""}`,
+		m.EnvPrefix + "FEATURE_GATES":          "",
+
+		m.EnvPrefix + "LABELER_RESCAN_INTERVAL":  "60s",
+		m.EnvPrefix + "LABELER_WATCH_PCI_EVENTS": "true",
 	}
 
 	for key, value := range defaults {
@@ -122,6 +199,17 @@ func (m *Manager) LoadFromFile(ctx context.Context, setKernelVar bool) error {
 	}
 	m.Log.WithField("tv", tv).Info("template vars")
 
+	var groups []nodeGroup
+	m.nodeGroupsSignature = ""
+	if m.NodeGrouping != "" {
+		if groups, err = m.nodeGroups(ctx); err != nil {
+			m.Log.WithError(err).Error("failed to group accelerator nodes")
+			return err
+		}
+		m.nodeGroupsSignature = nodeGroupsSignature(groups)
+		m.Log.WithField("nodeGrouping", m.NodeGrouping).WithField("groups", m.nodeGroupsSignature).Info("accelerator nodes grouped")
+	}
+
 	for idx := range m.Assets {
 		m.Log.WithField("path", m.Assets[idx].Path).Info("loading asset")
 
@@ -130,7 +218,12 @@ func (m *Manager) LoadFromFile(ctx context.Context, setKernelVar bool) error {
 		m.Assets[idx].log = assetLogger
 		m.Assets[idx].substitutions = tv
 
-		if err := m.Assets[idx].loadFromFile(); err != nil {
+		if m.NodeGrouping != "" && m.Assets[idx].PerNodeGroup {
+			err = m.Assets[idx].loadPerNodeGroup(tv, groups)
+		} else {
+			err = m.Assets[idx].loadFromFile()
+		}
+		if err != nil {
 			m.Log.WithError(err).WithField("path", m.Assets[idx].Path).Error("failed to loadFromFile asset")
 			return err
 		}
@@ -152,6 +245,14 @@ func (m *Manager) LoadFromConfigMapAndDeploy(ctx context.Context) error {
 	if err := m.Deploy(ctx); err != nil {
 		return err
 	}
+	if err := m.pruneNodeGroups(ctx); err != nil {
+		m.Log.WithError(err).Error("failed to delete DaemonSets of removed node groups")
+		return err
+	}
+	if err := m.pruneExternalDevicePlugin(ctx); err != nil {
+		m.Log.WithError(err).Error("failed to delete DaemonSet of sriov-network-device-plugin")
+		return err
+	}
 
 	return nil
 }
@@ -165,6 +266,9 @@ func (m *Manager) LoadFromConfigMap(ctx context.Context) error {
 			m.Log.WithError(err).WithField("ConfigMap name", m.Assets[idx].ConfigMapName).Error("failed to loadFromConfigMap")
 			return err
 		}
+		if m.Assets[idx].PerNodeGroup {
+			m.Assets[idx].labelNodeGroupDaemonSets()
+		}
 
 		m.Log.WithFields(logrus.Fields{
 			"ConfigMap name": m.Assets[idx].ConfigMapName,
@@ -177,7 +281,8 @@ func (m *Manager) LoadFromConfigMap(ctx context.Context) error {
 
 // Deploy will create (or update) each asset
 func (m *Manager) Deploy(ctx context.Context) error {
-	for _, asset := range m.Assets {
+	for i := range m.Assets {
+		asset := &m.Assets[i]
 		m.Log.WithFields(logrus.Fields{
 			"path":    asset.Path,
 			"retries": asset.BlockingReadiness.Retries,
@@ -185,14 +290,16 @@ func (m *Manager) Deploy(ctx context.Context) error {
 			"objects": len(asset.objects),
 		}).Info("deploying asset")
 
-		if err := asset.createOrUpdate(ctx, m.Client, m.Owner, m.Scheme); err != nil {
+		err := asset.createOrUpdate(ctx, m.Client, m.Owner, m.Scheme)
+		m.changed = append(m.changed, asset.changed...)
+		if err != nil {
 			m.Log.WithError(err).WithField("path", asset.Path).Error("failed to create asset")
 			return err
 		}
 
 		m.Log.WithField("path", asset.Path).Info("asset created successfully")
 
-		if err := asset.waitUntilReady(ctx, m.Client); err != nil {
+		if err := asset.waitUntilReady(ctx, m.Client, m.readinessCheck); err != nil {
 			m.Log.WithError(err).Error("waitUntilReady")
 			return err
 		}
@@ -201,6 +308,64 @@ func (m *Manager) Deploy(ctx context.Context) error {
 	return nil
 }
 
+// Render renders assets into objects deployed from their ConfigMaps without contacting the API server.
+// Template variables are built and validated as by LoadFromFile, with the given kernel version instead of
+// the one of cluster nodes. Returned objects are grouped by asset. Assets rendered per node group are
+// rejected, node groups are made of cluster nodes.
+func (m *Manager) Render(kernel string) ([][]*unstructured.Unstructured, error) {
+	if m.NodeGrouping != "" {
+		for idx := range m.Assets {
+			if m.Assets[idx].PerNodeGroup {
+				return nil, fmt.Errorf("asset %s is rendered per node group, node grouping %q requires nodes of the cluster", m.Assets[idx].Path, m.NodeGrouping)
+			}
+		}
+	}
+
+	tv, err := m.templateVars()
+	if err != nil {
+		return nil, err
+	}
+	if kernel != "" {
+		tv["kernel"] = kernel
+	}
+
+	rendered := make([][]*unstructured.Unstructured, len(m.Assets))
+	for idx := range m.Assets {
+		m.Assets[idx].log = m.Log
+		m.Assets[idx].substitutions = tv
+
+		if err := m.Assets[idx].loadFromFile(); err != nil {
+			return nil, fmt.Errorf("failed to render asset %s: %w", m.Assets[idx].Path, err)
+		}
+		if rendered[idx], err = m.Assets[idx].renderObjects(); err != nil {
+			return nil, fmt.Errorf("failed to render asset %s: %w", m.Assets[idx].Path, err)
+		}
+	}
+	return rendered, nil
+}
+
+// pruneExternalDevicePlugin deletes the DaemonSet of sriov-network-device-plugin, which is not rendered while the
+// DevicePlugin feature gate is enabled; otherwise both would serve the same resources to the kubelet
+func (m *Manager) pruneExternalDevicePlugin(ctx context.Context) error {
+	if !m.featureGates.Enabled(utils.FeatureDevicePlugin) {
+		return nil
+	}
+
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: externalDevicePluginName, Namespace: m.Namespace}}
+	err := m.Client.Delete(ctx, ds)
+	if err == nil {
+		m.Log.WithField("name", ds.Name).Info("deleted DaemonSet of sriov-network-device-plugin replaced by the DevicePlugin feature")
+	}
+	return client.IgnoreNotFound(err)
+}
+
+// TakeChanged returns objects created or updated by Deploy since the previous call
+func (m *Manager) TakeChanged() []client.Object {
+	changed := m.changed
+	m.changed = nil
+	return changed
+}
+
 func FetchOperatorDeployment(c client.Client, log *logrus.Logger) *appsv1.Deployment {
 	n := os.Getenv("NAME")
 	operatorDeploymentName := n[:strings.LastIndex(n[:strings.LastIndex(n, "-")], "-")]
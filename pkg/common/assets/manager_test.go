@@ -10,9 +10,11 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -386,7 +388,7 @@ var _ = Describe("Asset Tests", func() {
 			}
 			Expect(ds.Spec.Template.Spec.Tolerations).To(BeEmpty())
 
-			newObj, err := propagateTolerations(k8sClient, log, ds)
+			newObj, err := propagateTolerations(k8sClient, log, ds, nil)
 
 			Expect(err).To(Succeed())
 			uns, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
@@ -400,6 +402,398 @@ var _ = Describe("Asset Tests", func() {
 			Expect(toleration.Key).To(Equal(tolerationKey))
 			Expect(toleration.Effect).To(Equal(tolerationEffect))
 			Expect(toleration.Operator).To(Equal(tolerationOperator))
+			Expect(newDs.Spec.Template.Spec.Tolerations).To(ContainElement(reconfiguringToleration))
+		})
+	})
+
+	var _ = Describe("drift of deployed objects", func() {
+		const driftConfigMap = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: drift-test\n  namespace: default\nimmutable: false\ndata:\n  key: desired"
+
+		var manager *Manager
+		key := client.ObjectKey{Name: "drift-test", Namespace: "default"}
+
+		modifyDeployed := func(annotations map[string]string) {
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.TODO(), key, cm)).To(Succeed())
+			cm.Annotations = annotations
+			cm.Data["key"] = "manual"
+			Expect(k8sClient.Update(context.TODO(), cm)).To(Succeed())
+		}
+
+		deployedValue := func() string {
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.TODO(), key, cm)).To(Succeed())
+			return cm.Data["key"]
+		}
+
+		BeforeEach(func() {
+			getConfigMap = func(ctx context.Context, c client.Client, cmName string, ns string) (corev1.ConfigMap, error) {
+				return corev1.ConfigMap{Data: map[string]string{"configMap": driftConfigMap}}, nil
+			}
+			manager = &Manager{Client: k8sClient,
+				Log:    log,
+				Assets: []Asset{{log: log, ConfigMapName: fakeConfigMapName}},
+				Owner:  fakeOwner,
+				Scheme: scheme.Scheme}
+
+			Expect(manager.LoadFromConfigMapAndDeploy(context.TODO())).To(Succeed())
+			manager.TakeChanged()
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(context.TODO(), &corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}})).To(Succeed())
+		})
+
+		var _ = It("should not change objects in desired state", func() {
+			Expect(manager.LoadFromConfigMapAndDeploy(context.TODO())).To(Succeed())
+			Expect(manager.TakeChanged()).To(BeEmpty())
+		})
+
+		var _ = It("should re-apply drifted object", func() {
+			modifyDeployed(nil)
+
+			Expect(manager.LoadFromConfigMapAndDeploy(context.TODO())).To(Succeed())
+			changed := manager.TakeChanged()
+			Expect(changed).To(HaveLen(1))
+			Expect(changed[0].GetName()).To(Equal(key.Name))
+			Expect(deployedValue()).To(Equal("desired"))
+		})
+
+		var _ = It("should not re-apply object marked as unmanaged", func() {
+			modifyDeployed(map[string]string{utils.UnmanagedAnnotation: "true"})
+
+			Expect(manager.LoadFromConfigMapAndDeploy(context.TODO())).To(Succeed())
+			Expect(manager.TakeChanged()).To(BeEmpty())
+			Expect(deployedValue()).To(Equal("manual"))
+		})
+
+		var _ = It("should compare typed desired object with current state", func() {
+			ds := &appsv1.DaemonSet{
+				TypeMeta:   v1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+				ObjectMeta: v1.ObjectMeta{Name: "test-ds", Namespace: "default"},
+				Spec: appsv1.DaemonSetSpec{
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "test", Image: "test"}},
+					}},
+				},
+			}
+			current := ds.DeepCopy()
+			current.Status.NumberReady = 3
+			current.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+			uns, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+			Expect(err).To(Succeed())
+
+			Expect(matchesDesiredState(ds, &unstructured.Unstructured{Object: uns})).To(BeTrue())
+
+			ds.Spec.Template.Spec.Containers[0].Image = "new"
+			Expect(matchesDesiredState(ds, &unstructured.Unstructured{Object: uns})).To(BeFalse())
+		})
+	})
+
+	var _ = Describe("re-applied sriovdp-config", func() {
+		var _ = It("should keep node specific device plugin configs when re-applying sriovdp-config", func() {
+			desired := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1", "kind": "ConfigMap",
+				"metadata": map[string]interface{}{"name": "sriovdp-config", "namespace": "default"},
+				"data":     map[string]interface{}{"config.json": "desired"},
+			}}
+			current := desired.DeepCopy()
+			Expect(unstructured.SetNestedStringMap(current.Object, map[string]string{
+				"config.json": "manual", "config_worker.json": "generated", "other": "manual",
+			}, "data")).To(Succeed())
+
+			reapplied := withDevicePluginNodeConfigs(desired, current).(*unstructured.Unstructured)
+			data, _, err := unstructured.NestedStringMap(reapplied.Object, "data")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal(map[string]string{"config.json": "desired", "config_worker.json": "generated"}))
+			Expect(desired.Object["data"]).To(Equal(map[string]interface{}{"config.json": "desired"}), "asset must not be modified")
+
+			desired.SetName("drift-test")
+			Expect(withDevicePluginNodeConfigs(desired, current)).To(BeIdenticalTo(desired))
+		})
+	})
+
+	var _ = Describe("rendering of assets", func() {
+		var _ = It("should render objects of asset ConfigMaps without API server", func() {
+			manager := Manager{
+				Log:       log,
+				EnvPrefix: utils.SriovPrefix,
+				Assets:    []Asset{{Path: fakeAssetFile, Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}}},
+			}
+
+			rendered, err := manager.Render("5.14.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered).To(HaveLen(1))
+
+			var kinds []string
+			for _, obj := range rendered[0] {
+				kinds = append(kinds, obj.GetKind())
+			}
+			Expect(kinds).To(Equal([]string{"ClusterRole", "ClusterRoleBinding", "ConfigMap", "DaemonSet", "Namespace", "ServiceAccount"}))
+
+			ds := &appsv1.DaemonSet{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(rendered[0][3].Object, ds)).To(Succeed())
+			Expect(ds.Spec.Template.Spec.Tolerations).To(ContainElements(reconfiguringToleration,
+				corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}))
+		})
+
+		var _ = It("should validate template vars as operator does", func() {
+			manager := Manager{
+				Log:       log,
+				EnvPrefix: utils.SriovPrefix,
+				Overrides: map[string]string{utils.SriovPrefix + "VFIO_TOKEN": "invalid"},
+				Assets:    []Asset{{Path: fakeAssetFile}},
+			}
+
+			_, err := manager.Render("")
+			Expect(err).To(MatchError(ContainSubstring("is not a valid UUID")))
+		})
+
+		var _ = It("should interpret feature gates as the daemon does", func() {
+			externalDevicePlugin := func(gates string) bool {
+				manager := Manager{
+					Log:       log,
+					EnvPrefix: utils.SriovPrefix,
+					Overrides: map[string]string{
+						utils.SriovPrefix + "FEATURE_GATES":               gates,
+						utils.SriovPrefix + "NAMESPACE":                   "default",
+						utils.SriovPrefix + "NETWORK_DEVICE_PLUGIN_IMAGE": "sriov-network-device-plugin:latest",
+					},
+					Assets: []Asset{{Path: "../../../assets/200-device-plugin.yaml"}},
+				}
+				rendered, err := manager.Render("")
+				Expect(err).ToNot(HaveOccurred())
+				for _, obj := range rendered[0] {
+					if obj.GetKind() == "DaemonSet" && obj.GetName() == externalDevicePluginName {
+						return true
+					}
+				}
+				return false
+			}
+
+			Expect(externalDevicePlugin("")).To(BeTrue())
+			Expect(externalDevicePlugin("DevicePlugin=false")).To(BeTrue())
+			Expect(externalDevicePlugin("DevicePlugin=1")).To(BeFalse())
+			Expect(externalDevicePlugin("NodeMaintenance=false, DevicePlugin= true")).To(BeFalse())
+
+			manager := Manager{
+				Log:       log,
+				EnvPrefix: utils.SriovPrefix,
+				Overrides: map[string]string{utils.SriovPrefix + "FEATURE_GATES": "DevicePlugin=yes"},
+				Assets:    []Asset{{Path: fakeAssetFile}},
+			}
+			_, err := manager.Render("")
+			Expect(err).To(MatchError(ContainSubstring("invalid SRIOV_FEC_FEATURE_GATES")))
+		})
+
+		var _ = It("should delete sriov-network-device-plugin when the DevicePlugin feature gate is enabled", func() {
+			ds := &appsv1.DaemonSet{ObjectMeta: v1.ObjectMeta{Name: externalDevicePluginName, Namespace: "default"}}
+			c := fake.NewClientBuilder().WithObjects(ds).Build()
+			manager := &Manager{Client: c, Log: log, Namespace: "default", EnvPrefix: utils.SriovPrefix}
+
+			Expect(manager.setFeatureGateVars(map[string]string{utils.SriovPrefix + "FEATURE_GATES": "DevicePlugin=false"})).To(Succeed())
+			Expect(manager.pruneExternalDevicePlugin(context.TODO())).To(Succeed())
+			Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(ds), &appsv1.DaemonSet{})).To(Succeed())
+
+			Expect(manager.setFeatureGateVars(map[string]string{utils.SriovPrefix + "FEATURE_GATES": "DevicePlugin=true"})).To(Succeed())
+			Expect(manager.pruneExternalDevicePlugin(context.TODO())).To(Succeed())
+			err := c.Get(context.TODO(), client.ObjectKeyFromObject(ds), &appsv1.DaemonSet{})
+			Expect(err).To(HaveOccurred())
+			Expect(manager.pruneExternalDevicePlugin(context.TODO())).To(Succeed(), "missing DaemonSet is not an error")
+		})
+	})
+	var _ = Describe("node groups", func() {
+		node := func(name, kernel string, labels map[string]string) *corev1.Node {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[acceleratorNodeLabel] = ""
+			return &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+				Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KernelVersion: kernel}},
+			}
+		}
+
+		groupedDaemonSets := func(manager *Manager) map[string]*appsv1.DaemonSet {
+			Expect(manager.Assets[0].objects).To(HaveLen(1))
+			configMap := manager.Assets[0].objects[0].(*unstructured.Unstructured)
+			data, _, err := unstructured.NestedStringMap(configMap.Object, "data")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).ToNot(HaveKey("daemonSet"))
+			Expect(data).To(HaveKey("configMap"))
+
+			daemonSets := map[string]*appsv1.DaemonSet{}
+			for key, def := range data {
+				obj, err := decodeObject(def)
+				Expect(err).ToNot(HaveOccurred())
+				if obj.GetKind() != "DaemonSet" {
+					continue
+				}
+				ds := &appsv1.DaemonSet{}
+				Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ds)).To(Succeed())
+				Expect(key).To(Equal("daemonSet-" + ds.Labels[utils.NodeGroupLabel]))
+				daemonSets[ds.Annotations[utils.NodeGroupAnnotation]] = ds
+			}
+			return daemonSets
+		}
+
+		var _ = It("should render DaemonSet per kernel version of accelerator nodes", func() {
+			manager := &Manager{
+				Client: fake.NewClientBuilder().WithObjects(
+					node("worker-1", "5.14.0", nil), node("worker-2", "6.1.0", nil), node("worker-3", "5.14.0", nil),
+					&corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "control-plane"}, Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KernelVersion: "4.18.0"}}},
+				).Build(),
+				Log:          log,
+				EnvPrefix:    utils.SriovPrefix,
+				NodeGrouping: NodeGroupingKernel,
+				Assets:       []Asset{{Path: fakeAssetFile, PerNodeGroup: true}},
+			}
+
+			Expect(manager.LoadFromFile(context.TODO(), false)).To(Succeed())
+			daemonSets := groupedDaemonSets(manager)
+			Expect(daemonSets).To(HaveLen(2))
+
+			ds := daemonSets["5.14.0"]
+			Expect(ds).ToNot(BeNil())
+			id := ds.Labels[utils.NodeGroupLabel]
+			Expect(ds.Name).To(Equal("accelerator-discovery-" + id))
+			Expect(ds.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "accelerator-discovery", utils.NodeGroupLabel: id}))
+			Expect(ds.Spec.Template.Labels).To(Equal(ds.Spec.Selector.MatchLabels))
+			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal(
+				[]corev1.NodeSelectorTerm{{MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-1", "worker-3"}},
+				}}}))
+			Expect(daemonSets["6.1.0"].Name).ToNot(Equal(ds.Name))
+
+			signature := manager.NodeGroupsSignature()
+			Expect(signature).ToNot(BeEmpty())
+			Expect(manager.Client.Delete(context.TODO(), node("worker-2", "6.1.0", nil))).To(Succeed())
+			Expect(manager.LoadFromFile(context.TODO(), false)).To(Succeed())
+			Expect(groupedDaemonSets(manager)).To(HaveLen(1))
+			Expect(manager.NodeGroupsSignature()).ToNot(Equal(signature))
+		})
+
+		var _ = It("should render DaemonSet per value of the grouping label", func() {
+			const label = "example.com/pool"
+			manager := &Manager{
+				Client: fake.NewClientBuilder().WithObjects(
+					node("worker-1", "5.14.0", map[string]string{label: "a"}), node("worker-2", "6.1.0", nil),
+				).Build(),
+				Log:          log,
+				EnvPrefix:    utils.SriovPrefix,
+				NodeGrouping: label,
+				Assets:       []Asset{{Path: fakeAssetFile, PerNodeGroup: true}},
+			}
+
+			Expect(manager.LoadFromFile(context.TODO(), false)).To(Succeed())
+			daemonSets := groupedDaemonSets(manager)
+			Expect(daemonSets).To(HaveLen(2))
+
+			terms := func(value string) []corev1.NodeSelectorTerm {
+				return daemonSets[value].Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			}
+			Expect(terms("a")).To(Equal([]corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: label, Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+			}}}))
+			Expect(terms("")).To(Equal([]corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: label, Operator: corev1.NodeSelectorOpDoesNotExist},
+			}}}))
+		})
+
+		var _ = It("should delete DaemonSets of node groups which no longer exist", func() {
+			daemonSet := func(name string) *appsv1.DaemonSet {
+				return &appsv1.DaemonSet{
+					TypeMeta:   v1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+					ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{utils.NodeGroupAssetLabel: fakeConfigMapName}},
+				}
+			}
+			c := fake.NewClientBuilder().WithObjects(daemonSet("daemon-a"), daemonSet("daemon-b")).Build()
+			manager := &Manager{
+				Client:    c,
+				Log:       log,
+				Namespace: "default",
+				Assets:    []Asset{{ConfigMapName: fakeConfigMapName, PerNodeGroup: true, objects: []client.Object{daemonSet("daemon-a")}}},
+			}
+
+			Expect(manager.pruneNodeGroups(context.TODO())).To(Succeed())
+			daemonSets := &appsv1.DaemonSetList{}
+			Expect(c.List(context.TODO(), daemonSets)).To(Succeed())
+			Expect(daemonSets.Items).To(HaveLen(1))
+			Expect(daemonSets.Items[0].Name).To(Equal("daemon-a"))
+		})
+	})
+	var _ = Describe("readiness of assets", func() {
+		object := func(kind string, status map[string]interface{}) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+			obj.SetAPIVersion("apps/v1")
+			obj.SetKind(kind)
+			obj.SetName("test")
+			obj.SetNamespace("default")
+			obj.SetGeneration(2)
+			return obj
+		}
+		ready := func(check ReadinessCheck, obj *unstructured.Unstructured) bool {
+			isReady, _, err := check(obj)
+			Expect(err).ToNot(HaveOccurred())
+			return isReady
+		}
+
+		var _ = It("should check readiness of objects by their kind", func() {
+			Expect(ready(DaemonSetReady, object("DaemonSet", map[string]interface{}{
+				"observedGeneration": int64(2), "desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2)}))).To(BeTrue())
+			Expect(ready(DaemonSetReady, object("DaemonSet", map[string]interface{}{
+				"observedGeneration": int64(1), "desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2)}))).To(BeFalse())
+			Expect(ready(DaemonSetReady, object("DaemonSet", map[string]interface{}{
+				"observedGeneration": int64(2), "desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2), "numberUnavailable": int64(1)}))).To(BeFalse())
+
+			Expect(ready(DeploymentReady, object("Deployment", map[string]interface{}{
+				"updatedReplicas": int64(1), "availableReplicas": int64(1)}))).To(BeTrue())
+			Expect(ready(DeploymentReady, object("Deployment", map[string]interface{}{"updatedReplicas": int64(1)}))).To(BeFalse())
+
+			conditions := func(conditionType, status string) map[string]interface{} {
+				return map[string]interface{}{"conditions": []interface{}{
+					map[string]interface{}{"type": conditionType, "status": status, "message": "details"},
+				}}
+			}
+			Expect(ready(JobReady, object("Job", conditions("Complete", "True")))).To(BeTrue())
+			Expect(ready(JobReady, object("Job", map[string]interface{}{}))).To(BeFalse())
+			_, reason, err := JobReady(object("Job", conditions("Failed", "True")))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reason).To(Equal("failed: details"))
+
+			Expect(ready(ReadyConditionTrue, object("Custom", map[string]interface{}{}))).To(BeTrue())
+			Expect(ready(ReadyConditionTrue, object("Custom", conditions("Ready", "True")))).To(BeTrue())
+			Expect(ready(ReadyConditionTrue, object("Custom", conditions("Ready", "False")))).To(BeFalse())
+		})
+
+		var _ = It("should report objects which are not ready without waiting", func() {
+			ds := object("DaemonSet", map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberUnavailable": int64(1)})
+			deployment := object("Deployment", map[string]interface{}{"updatedReplicas": int64(1), "availableReplicas": int64(1)})
+			missing := object("DaemonSet", nil)
+			missing.SetName("missing")
+
+			manager := &Manager{
+				Client: fake.NewClientBuilder().WithObjects(ds.DeepCopy(), deployment.DeepCopy()).Build(),
+				Log:    log,
+				Assets: []Asset{
+					{ConfigMapName: "daemon-config", objects: []client.Object{ds, missing}},
+					{ConfigMapName: "device-plugin-config", objects: []client.Object{deployment}},
+				},
+			}
+
+			readiness, err := manager.CheckReadiness(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readiness).To(Equal([]AssetReadiness{
+				{ConfigMapName: "daemon-config", NotReady: []string{"DaemonSet missing: not found", "DaemonSet test: 1 of 3 pods unavailable"}},
+				{ConfigMapName: "device-plugin-config", Ready: true},
+			}))
+
+			manager.ReadinessChecks = map[string]ReadinessCheck{"Deployment": func(*unstructured.Unstructured) (bool, string, error) {
+				return false, "custom check", nil
+			}}
+			readiness, err = manager.CheckReadiness(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readiness[1].NotReady).To(ConsistOf("Deployment test: custom check"))
 		})
 	})
 })
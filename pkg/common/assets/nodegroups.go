@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NodeGroupingKernel groups accelerator nodes by their kernel version
+	NodeGroupingKernel = "kernel"
+
+	acceleratorNodeLabel = "fpga.intel.com/intel-accelerator-present"
+)
+
+// nodeGroup is a set of accelerator nodes sharing the kernel version or the value of the grouping label
+type nodeGroup struct {
+	// label is the key of the grouping label; empty when nodes are grouped by kernel version
+	label string
+	// value is the kernel version or the value of the grouping label shared by nodes of the group
+	value string
+	// unlabeled is set for the group of nodes without the grouping label
+	unlabeled bool
+	// kernel is the kernel version of the first node of the group
+	kernel string
+	nodes  []string
+}
+
+// id identifies the group in names and labels of objects rendered for it
+func (g *nodeGroup) id() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%t", g.label, g.value, g.unlabeled)))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// requirement restricts a node selector term to nodes of the group
+func (g *nodeGroup) requirement(term *corev1.NodeSelectorTerm) {
+	switch {
+	case g.label == "":
+		// kernel version is not exposed as a node label, nodes are selected by name instead
+		term.MatchFields = append(term.MatchFields, corev1.NodeSelectorRequirement{
+			Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: g.nodes,
+		})
+	case g.unlabeled:
+		term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+			Key: g.label, Operator: corev1.NodeSelectorOpDoesNotExist,
+		})
+	default:
+		term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+			Key: g.label, Operator: corev1.NodeSelectorOpIn, Values: []string{g.value},
+		})
+	}
+}
+
+// nodeGroups groups accelerator nodes according to NodeGrouping of the manager; groups are sorted by id
+func (m *Manager) nodeGroups(ctx context.Context) ([]nodeGroup, error) {
+	nodes := &corev1.NodeList{}
+	if err := m.Client.List(ctx, nodes, &client.MatchingLabels{acceleratorNodeLabel: ""}); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool {
+		return nodes.Items[i].Name < nodes.Items[j].Name
+	})
+
+	byID := map[string]*nodeGroup{}
+	for _, node := range nodes.Items {
+		g := &nodeGroup{value: node.Status.NodeInfo.KernelVersion, kernel: node.Status.NodeInfo.KernelVersion}
+		if m.NodeGrouping != NodeGroupingKernel {
+			value, labeled := node.Labels[m.NodeGrouping]
+			g.label, g.value, g.unlabeled = m.NodeGrouping, value, !labeled
+		}
+		if existing, ok := byID[g.id()]; ok {
+			g = existing
+		} else {
+			byID[g.id()] = g
+		}
+		g.nodes = append(g.nodes, node.Name)
+	}
+
+	groups := make([]nodeGroup, 0, len(byID))
+	for _, g := range byID {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].id() < groups[j].id()
+	})
+	return groups, nil
+}
+
+// NodeGroupsSignature identifies node groups the assets were rendered for by the last LoadFromFile;
+// it changes whenever a group is added or removed or its nodes change
+func (m *Manager) NodeGroupsSignature() string {
+	return m.nodeGroupsSignature
+}
+
+func nodeGroupsSignature(groups []nodeGroup) string {
+	parts := make([]string, 0, len(groups))
+	for i := range groups {
+		parts = append(parts, groups[i].id()+"="+strings.Join(groups[i].nodes, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// loadPerNodeGroup loads the asset with given substitutions and replaces its DaemonSets, plain or held by
+// ConfigMaps of the asset, with one DaemonSet per node group rendered with the kernel version of the group
+func (a *Asset) loadPerNodeGroup(substitutions map[string]string, groups []nodeGroup) error {
+	renderings := make([][]*unstructured.Unstructured, len(groups))
+	for i := range groups {
+		a.substitutions = renderingVars(substitutions, groups[i].kernel)
+		if err := a.loadFromFile(); err != nil {
+			return err
+		}
+		for _, obj := range a.objects {
+			renderings[i] = append(renderings[i], obj.(*unstructured.Unstructured))
+		}
+	}
+
+	// objects other than DaemonSets are taken from the rendering for the first group
+	a.substitutions = substitutions
+	if len(groups) > 0 {
+		a.substitutions = renderingVars(substitutions, groups[0].kernel)
+	}
+	if err := a.loadFromFile(); err != nil {
+		return err
+	}
+	for i := range renderings {
+		if len(renderings[i]) != len(a.objects) {
+			return fmt.Errorf("asset %s renders different objects for kernel %s", a.Path, groups[i].kernel)
+		}
+	}
+
+	var objects []client.Object
+	for idx, obj := range a.objects {
+		u := obj.(*unstructured.Unstructured)
+		switch u.GetKind() {
+		case "DaemonSet":
+			for i := range groups {
+				ds := renderings[i][idx]
+				if err := restrictToNodeGroup(ds, &groups[i]); err != nil {
+					return err
+				}
+				objects = append(objects, ds)
+			}
+		case "ConfigMap":
+			if err := a.splitConfigMapDaemonSets(u, renderings, idx, groups); err != nil {
+				return err
+			}
+			objects = append(objects, u)
+		default:
+			objects = append(objects, u)
+		}
+	}
+	a.objects = objects
+	return nil
+}
+
+func renderingVars(substitutions map[string]string, kernel string) map[string]string {
+	vars := map[string]string{"kernel": kernel}
+	for key, value := range substitutions {
+		if key != "kernel" {
+			vars[key] = value
+		}
+	}
+	return vars
+}
+
+// splitConfigMapDaemonSets replaces DaemonSets held by the ConfigMap with their renderings for each node group
+func (a *Asset) splitConfigMapDaemonSets(configMap *unstructured.Unstructured, renderings [][]*unstructured.Unstructured, idx int, groups []nodeGroup) error {
+	data, found, err := unstructured.NestedStringMap(configMap.Object, "data")
+	if err != nil || !found {
+		return err
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		obj, err := decodeObject(data[key])
+		if err != nil {
+			return fmt.Errorf("failed to decode %s of ConfigMap %s: %w", key, configMap.GetName(), err)
+		}
+		if obj.GetKind() != "DaemonSet" {
+			continue
+		}
+
+		delete(data, key)
+		for i := range groups {
+			groupData, _, err := unstructured.NestedStringMap(renderings[i][idx].Object, "data")
+			if err != nil {
+				return err
+			}
+			ds, err := decodeObject(groupData[key])
+			if err != nil {
+				return fmt.Errorf("failed to decode %s of ConfigMap %s: %w", key, configMap.GetName(), err)
+			}
+			if err := restrictToNodeGroup(ds, &groups[i]); err != nil {
+				return err
+			}
+			raw, err := ds.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			data[key+"-"+groups[i].id()] = string(raw)
+		}
+	}
+	return unstructured.SetNestedStringMap(configMap.Object, data, "data")
+}
+
+func decodeObject(def string) (*unstructured.Unstructured, error) {
+	obj := new(unstructured.Unstructured)
+	err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(def), 4096).Decode(obj)
+	return obj, err
+}
+
+// restrictToNodeGroup renames the DaemonSet after the node group, labels it and its pods with the group
+// and adds node affinity to nodes of the group on top of the affinity of the DaemonSet
+func restrictToNodeGroup(ds *unstructured.Unstructured, g *nodeGroup) error {
+	id := g.id()
+	ds.SetName(ds.GetName() + "-" + id)
+
+	labels := ds.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[utils.NodeGroupLabel] = id
+	ds.SetLabels(labels)
+	annotations := ds.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[utils.NodeGroupAnnotation] = g.value
+	ds.SetAnnotations(annotations)
+
+	// pods of DaemonSets of different groups must not match selectors of each other
+	for _, path := range [][]string{{"spec", "selector", "matchLabels"}, {"spec", "template", "metadata", "labels"}} {
+		if err := unstructured.SetNestedField(ds.Object, id, append(path, utils.NodeGroupLabel)...); err != nil {
+			return err
+		}
+	}
+
+	path := []string{"spec", "template", "spec", "affinity"}
+	affinity := &corev1.Affinity{}
+	if existing, found, err := unstructured.NestedMap(ds.Object, path...); err != nil {
+		return err
+	} else if found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing, affinity); err != nil {
+			return err
+		}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		required = &corev1.NodeSelector{}
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	}
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// terms are ORed, the group has to be required by each of them
+	for i := range required.NodeSelectorTerms {
+		g.requirement(&required.NodeSelectorTerms[i])
+	}
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(affinity)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedMap(ds.Object, raw, path...)
+}
+
+// labelNodeGroupDaemonSets marks DaemonSets of the asset rendered per node group, so that DaemonSets of groups
+// which no longer exist can be found and deleted
+func (a *Asset) labelNodeGroupDaemonSets() {
+	for _, obj := range a.objects {
+		if obj.GetObjectKind().GroupVersionKind().Kind != "DaemonSet" {
+			continue
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[utils.NodeGroupAssetLabel] = a.ConfigMapName
+		obj.SetLabels(labels)
+	}
+}
+
+// pruneNodeGroups deletes DaemonSets deployed from assets rendered per node group which were not deployed
+// by the last Deploy, i.e. DaemonSets of node groups which no longer exist
+func (m *Manager) pruneNodeGroups(ctx context.Context) error {
+	for i := range m.Assets {
+		asset := &m.Assets[i]
+		if !asset.PerNodeGroup {
+			continue
+		}
+
+		deployed := map[client.ObjectKey]bool{}
+		for _, obj := range asset.objects {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "DaemonSet" {
+				deployed[client.ObjectKeyFromObject(obj)] = true
+			}
+		}
+
+		daemonSets := &appsv1.DaemonSetList{}
+		if err := m.Client.List(ctx, daemonSets, client.InNamespace(m.Namespace),
+			client.MatchingLabels{utils.NodeGroupAssetLabel: asset.ConfigMapName}); err != nil {
+			return err
+		}
+		for j := range daemonSets.Items {
+			ds := &daemonSets.Items[j]
+			if deployed[client.ObjectKeyFromObject(ds)] {
+				continue
+			}
+			m.Log.WithField("name", ds.Name).WithField("nodeGroup", ds.Annotations[utils.NodeGroupAnnotation]).
+				Info("deleting DaemonSet of node group which no longer exists")
+			if err := m.Client.Delete(ctx, ds); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
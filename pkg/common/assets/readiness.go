@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReadinessCheck tells whether the deployed object is ready; reason explains why it is not
+type ReadinessCheck func(obj *unstructured.Unstructured) (ready bool, reason string, err error)
+
+// AssetReadiness reports readiness of objects deployed from an asset
+type AssetReadiness struct {
+	// ConfigMapName identifies the asset
+	ConfigMapName string
+	Ready         bool
+	// NotReady describes objects of the asset which are not ready
+	NotReady []string
+}
+
+// defaultReadinessChecks are used for kinds without a check in ReadinessChecks of the manager; objects of other kinds
+// are ready when they have no Ready condition or the condition is True
+var defaultReadinessChecks = map[string]ReadinessCheck{
+	"DaemonSet":  DaemonSetReady,
+	"Deployment": DeploymentReady,
+	"Job":        JobReady,
+}
+
+// readinessCheck returns the check of objects of given kind
+func (m *Manager) readinessCheck(kind string) ReadinessCheck {
+	if check, ok := m.ReadinessChecks[kind]; ok {
+		return check
+	}
+	if check, ok := defaultReadinessChecks[kind]; ok {
+		return check
+	}
+	return ReadyConditionTrue
+}
+
+// CheckReadiness checks once whether objects deployed from assets by the last Deploy are ready, without waiting for them
+func (m *Manager) CheckReadiness(ctx context.Context) ([]AssetReadiness, error) {
+	readiness := make([]AssetReadiness, 0, len(m.Assets))
+	for i := range m.Assets {
+		notReady, err := m.Assets[i].notReady(ctx, m.Client, m.readinessCheck)
+		if err != nil {
+			return nil, err
+		}
+		readiness = append(readiness, AssetReadiness{
+			ConfigMapName: m.Assets[i].ConfigMapName,
+			Ready:         len(notReady) == 0,
+			NotReady:      notReady,
+		})
+	}
+	return readiness, nil
+}
+
+// notReady describes objects of the asset which are not ready according to checks of their kinds
+func (a *Asset) notReady(ctx context.Context, c client.Reader, checkFor func(kind string) ReadinessCheck) ([]string, error) {
+	var notReady []string
+	for _, obj := range a.objects {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(gvk)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierr.IsNotFound(err) {
+				notReady = append(notReady, fmt.Sprintf("%s %s: not found", gvk.Kind, obj.GetName()))
+				continue
+			}
+			return nil, err
+		}
+
+		ready, reason, err := checkFor(gvk.Kind)(current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check readiness of %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s %s: %s", gvk.Kind, obj.GetName(), reason))
+		}
+	}
+	sort.Strings(notReady)
+	return notReady, nil
+}
+
+// observedLatest tells whether the controller of the object did not report an older generation than the latest one
+func observedLatest(obj *unstructured.Unstructured) (bool, error) {
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	return !found || observed >= obj.GetGeneration(), err
+}
+
+// statusInt64 returns integer field of the status; missing fields are 0
+func statusInt64(obj *unstructured.Unstructured, field string) (int64, error) {
+	value, _, err := unstructured.NestedInt64(obj.Object, "status", field)
+	return value, err
+}
+
+// DaemonSetReady checks that pods of the latest DaemonSet revision run and are available on all nodes
+func DaemonSetReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if latest, err := observedLatest(obj); err != nil || !latest {
+		return false, "rollout not observed yet", err
+	}
+	values := map[string]int64{}
+	for _, field := range []string{"desiredNumberScheduled", "updatedNumberScheduled", "numberUnavailable"} {
+		value, err := statusInt64(obj, field)
+		if err != nil {
+			return false, "", err
+		}
+		values[field] = value
+	}
+	if values["updatedNumberScheduled"] < values["desiredNumberScheduled"] {
+		return false, fmt.Sprintf("%d of %d pods updated", values["updatedNumberScheduled"], values["desiredNumberScheduled"]), nil
+	}
+	if values["numberUnavailable"] > 0 {
+		return false, fmt.Sprintf("%d of %d pods unavailable", values["numberUnavailable"], values["desiredNumberScheduled"]), nil
+	}
+	return true, "", nil
+}
+
+// DeploymentReady checks that all replicas of the Deployment are updated and available
+func DeploymentReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if latest, err := observedLatest(obj); err != nil || !latest {
+		return false, "rollout not observed yet", err
+	}
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return false, "", err
+	}
+	if !found {
+		replicas = 1
+	}
+	updated, err := statusInt64(obj, "updatedReplicas")
+	if err != nil {
+		return false, "", err
+	}
+	available, err := statusInt64(obj, "availableReplicas")
+	if err != nil {
+		return false, "", err
+	}
+	if updated < replicas {
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	}
+	if available < replicas {
+		return false, fmt.Sprintf("%d of %d replicas available", available, replicas), nil
+	}
+	return true, "", nil
+}
+
+// JobReady checks that the Job completed
+func JobReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if status, msg, err := condition(obj, "Failed"); err != nil || status == "True" {
+		return false, "failed: " + msg, err
+	}
+	status, _, err := condition(obj, "Complete")
+	if err != nil || status != "True" {
+		return false, "not completed", err
+	}
+	return true, "", nil
+}
+
+// ReadyConditionTrue checks the Ready condition of objects, e.g. custom resources; objects without it are ready
+func ReadyConditionTrue(obj *unstructured.Unstructured) (bool, string, error) {
+	status, msg, err := condition(obj, "Ready")
+	if err != nil || status == "" || status == "True" {
+		return err == nil, "", err
+	}
+	return false, msg, nil
+}
+
+// condition returns status and message of the condition of given type; status is empty when the condition is missing
+func condition(obj *unstructured.Unstructured, conditionType string) (string, string, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return "", "", err
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != conditionType {
+			continue
+		}
+		status, _ := cond["status"].(string)
+		msg, _ := cond["message"].(string)
+		return status, msg, nil
+	}
+	return "", "", nil
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package deviceplugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the config of sriov-network-device-plugin
+	ConfigMapName = "sriovdp-config"
+	// CommonConfigKey is the key of the config used by nodes without node specific config
+	CommonConfigKey = "config.json"
+	// DefaultResourcePrefix is the prefix of resources which do not specify one
+	DefaultResourcePrefix = "intel.com"
+	// PFAddressInfo is the additional information pinning a resource to VFs of one PF
+	PFAddressInfo = "PF_PCI_ADDR"
+	// VfioTokenInfo is the additional information carrying the VFIO token of VFs
+	VfioTokenInfo = "VFIO_TOKEN"
+
+	// allDevices is the key of additional information applying to every device of a resource
+	allDevices = "*"
+)
+
+// Config is a subset of sriov-network-device-plugin config used to serve accelerator VFs
+type Config struct {
+	ResourceList []Resource `json:"resourceList"`
+}
+
+// Resource is a resource pool of sriov-network-device-plugin
+type Resource struct {
+	ResourceName   string                       `json:"resourceName"`
+	ResourcePrefix string                       `json:"resourcePrefix,omitempty"`
+	DeviceType     string                       `json:"deviceType,omitempty"`
+	Selectors      Selectors                    `json:"selectors"`
+	AdditionalInfo map[string]map[string]string `json:"additionalInfo,omitempty"`
+}
+
+// Selectors select devices of a resource; accelerator resources support generic selectors only
+type Selectors struct {
+	Vendors      []string `json:"vendors,omitempty"`
+	Devices      []string `json:"devices,omitempty"`
+	Drivers      []string `json:"drivers,omitempty"`
+	PciAddresses []string `json:"pciAddresses,omitempty"`
+}
+
+// NodeConfigKey returns the key of the config specific to the node, which takes precedence over CommonConfigKey
+func NodeConfigKey(nodeName string) string {
+	return fmt.Sprintf("config_%s.json", nodeName)
+}
+
+// IsNodeConfigKey returns true if the key holds the config specific to a node
+func IsNodeConfigKey(key string) bool {
+	return strings.HasPrefix(key, "config_") && strings.HasSuffix(key, ".json")
+}
+
+// FullName returns the name of the resource qualified with its prefix, e.g. intel.com/intel_fec_acc100
+func (r Resource) FullName() string {
+	prefix := r.ResourcePrefix
+	if prefix == "" {
+		prefix = DefaultResourcePrefix
+	}
+	return prefix + "/" + r.ResourceName
+}
+
+// PFAddress returns the PCI address of the PF the resource is pinned to; empty when it is not pinned
+func (r Resource) PFAddress() string {
+	return strings.TrimSpace(r.AdditionalInfo[allDevices][PFAddressInfo])
+}
+
+// VfioToken returns the VFIO token of devices of the resource
+func (r Resource) VfioToken() string {
+	return r.AdditionalInfo[allDevices][VfioTokenInfo]
+}
+
+// DeepCopy returns a copy of the resource which does not share selectors and additional information
+func (r Resource) DeepCopy() Resource {
+	c := r
+	c.Selectors = Selectors{
+		Vendors:      append([]string(nil), r.Selectors.Vendors...),
+		Devices:      append([]string(nil), r.Selectors.Devices...),
+		Drivers:      append([]string(nil), r.Selectors.Drivers...),
+		PciAddresses: append([]string(nil), r.Selectors.PciAddresses...),
+	}
+	if r.AdditionalInfo != nil {
+		c.AdditionalInfo = make(map[string]map[string]string, len(r.AdditionalInfo))
+		for device, info := range r.AdditionalInfo {
+			c.AdditionalInfo[device] = make(map[string]string, len(info))
+			for k, v := range info {
+				c.AdditionalInfo[device][k] = v
+			}
+		}
+	}
+	return c
+}
+
+// Parse unmarshals the config of sriov-network-device-plugin
+func Parse(data string) (Config, error) {
+	config := Config{}
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return config, fmt.Errorf("failed to unmarshal device plugin config: %w", err)
+	}
+	return config, nil
+}
+
+// Marshal returns the config in the format kept in sriovdp-config
+func (c Config) Marshal() (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal device plugin config: %w", err)
+	}
+	return string(data), nil
+}
+
+/*****************************************************************************
+ * Function: ForNode
+ * Description: Returns the config of sriovdp-config used by the node; the node
+ * 		specific config takes precedence over the common one
+ ****************************************************************************/
+func ForNode(cm *corev1.ConfigMap, nodeName string) (Config, error) {
+	data, ok := cm.Data[NodeConfigKey(nodeName)]
+	if !ok {
+		if data, ok = cm.Data[CommonConfigKey]; !ok {
+			return Config{}, fmt.Errorf("%s not found in %s", CommonConfigKey, ConfigMapName)
+		}
+	}
+	return Parse(data)
+}
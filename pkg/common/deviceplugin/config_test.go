@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package deviceplugin
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Config", func() {
+	const common = `{"resourceList": [
+		{"resourceName": "intel_fec_acc100", "deviceType": "accelerator",
+			"selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"]},
+			"additionalInfo": {"*": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510"}}},
+		{"resourceName": "intel_vrb_vrb1", "deviceType": "accelerator", "selectors": {"vendors": ["8086"], "devices": ["57c1"]}}
+	]}`
+
+	It("should read the node specific config or fall back to the common one", func() {
+		cm := &corev1.ConfigMap{Data: map[string]string{
+			CommonConfigKey:         common,
+			NodeConfigKey("worker"): `{"resourceList": [{"resourceName": "custom", "resourcePrefix": "example.com", "selectors": {}}]}`,
+		}}
+
+		config, err := ForNode(cm, "worker")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.ResourceList).To(HaveLen(1))
+		Expect(config.ResourceList[0].FullName()).To(Equal("example.com/custom"))
+
+		config, err = ForNode(cm, "other")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.ResourceList).To(HaveLen(2))
+		Expect(config.ResourceList[0].FullName()).To(Equal("intel.com/intel_fec_acc100"))
+		Expect(config.ResourceList[0].VfioToken()).To(Equal("02bddbbf-bbb0-4d79-886b-91bad3fbb510"))
+
+		_, err = ForNode(&corev1.ConfigMap{}, "worker")
+		Expect(err).To(MatchError(ContainSubstring("config.json not found")))
+
+		Expect(IsNodeConfigKey(NodeConfigKey("worker"))).To(BeTrue())
+		Expect(IsNodeConfigKey(CommonConfigKey)).To(BeFalse())
+	})
+
+	It("should pin custom resources to VFs of their PFs ahead of common resources", func() {
+		config, err := Parse(common)
+		Expect(err).ToNot(HaveOccurred())
+
+		generated, unresolved := NodeConfig(config, []PinnedResource{
+			{ResourceName: "fec_b", PFAddress: "0000:b2:00.0", VFDeviceID: "0D5D", VFs: []string{"0000:b3:00.1", "0000:b3:00.0"}},
+			{ResourceName: "fec_a", PFAddress: "0000:a2:00.0", VFDeviceID: "0d5d", VFs: []string{"0000:a3:00.0"}},
+			{ResourceName: "unknown", PFAddress: "0000:c2:00.0", VFDeviceID: "ffff", VFs: []string{"0000:c3:00.0"}},
+		})
+		Expect(unresolved).To(Equal([]string{"unknown"}))
+
+		data, err := generated.Marshal()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"resourceList": [
+			{"resourceName": "fec_a", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"], "pciAddresses": ["0000:a3:00.0"]},
+				"additionalInfo": {"*": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510", "PF_PCI_ADDR": "0000:a2:00.0"}}},
+			{"resourceName": "fec_b", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"], "pciAddresses": ["0000:b3:00.0", "0000:b3:00.1"]},
+				"additionalInfo": {"*": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510", "PF_PCI_ADDR": "0000:b2:00.0"}}},
+			{"resourceName": "intel_fec_acc100", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"]},
+				"additionalInfo": {"*": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510"}}},
+			{"resourceName": "intel_vrb_vrb1", "deviceType": "accelerator", "selectors": {"vendors": ["8086"], "devices": ["57c1"]}}
+		]}`))
+		Expect(generated.ResourceList[0].PFAddress()).To(Equal("0000:a2:00.0"))
+		Expect(config.ResourceList[0].PFAddress()).To(BeEmpty(), "common config must not be modified")
+	})
+
+	It("should merge pinned resources of the same name", func() {
+		config, err := Parse(common)
+		Expect(err).ToNot(HaveOccurred())
+
+		generated, unresolved := NodeConfig(config, []PinnedResource{
+			{ResourceName: "vrb", PFAddress: "0000:f7:00.0", VFDeviceID: "57c1", VFs: []string{"0000:f7:00.1"}},
+			{ResourceName: "vrb", PFAddress: "0000:f8:00.0", VFDeviceID: "57c1", VFs: []string{"0000:f8:00.1"}},
+		})
+		Expect(unresolved).To(BeEmpty())
+		Expect(generated.ResourceList).To(HaveLen(3))
+		Expect(generated.ResourceList[0].ResourceName).To(Equal("vrb"))
+		Expect(generated.ResourceList[0].Selectors.PciAddresses).To(Equal([]string{"0000:f7:00.1", "0000:f8:00.1"}))
+		Expect(generated.ResourceList[0].PFAddress()).To(BeEmpty())
+	})
+
+	It("should replace resources renamed in place by previous versions of the daemon", func() {
+		config, err := Parse(`{"resourceList": [
+			{"resourceName": "intel_fec_acc100", "deviceType": "accelerator", "selectors": {"vendors": ["8086"], "devices": ["0d5d"]}},
+			{"resourceName": "vrb_old", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["57c1"], "pciAddresses": ["0000:f7:00.1"]},
+				"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:f7:00.0"}}},
+			{"resourceName": "vrb_cell", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["57c1"], "pciAddresses": ["0000:f8:00.1"]},
+				"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:f8:00.0"}}}
+		]}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		generated, unresolved := NodeConfig(config, []PinnedResource{
+			{ResourceName: "vrb_cell", PFAddress: "0000:f7:00.0", VFDeviceID: "57c1", VFs: []string{"0000:f7:00.1", "0000:f7:00.2"}},
+		})
+		Expect(unresolved).To(BeEmpty())
+
+		data, err := generated.Marshal()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"resourceList": [
+			{"resourceName": "vrb_cell", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["57c1"], "pciAddresses": ["0000:f7:00.1", "0000:f7:00.2"]},
+				"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:f7:00.0"}}},
+			{"resourceName": "intel_fec_acc100", "deviceType": "accelerator", "selectors": {"vendors": ["8086"], "devices": ["0d5d"]}}
+		]}`))
+	})
+
+	It("should prefer resources not pinned to a PF as templates", func() {
+		config, err := Parse(`{"resourceList": [
+			{"resourceName": "vrb_old", "deviceType": "accelerator",
+				"selectors": {"vendors": ["8086"], "devices": ["57c1"], "pciAddresses": ["0000:f8:00.1"]},
+				"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:f8:00.0"}}},
+			{"resourceName": "intel_vrb_vrb1", "deviceType": "accelerator", "selectors": {"vendors": ["8086"], "devices": ["57c1"], "drivers": ["vfio-pci"]}}
+		]}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		generated, _ := NodeConfig(config, []PinnedResource{
+			{ResourceName: "vrb_cell", PFAddress: "0000:f7:00.0", VFDeviceID: "57c1", VFs: []string{"0000:f7:00.1"}},
+		})
+		Expect(generated.ResourceList).To(HaveLen(3))
+		Expect(generated.ResourceList[0].ResourceName).To(Equal("vrb_cell"))
+		Expect(generated.ResourceList[0].Selectors.Drivers).To(Equal([]string{"vfio-pci"}))
+		Expect(generated.ResourceList[1].ResourceName).To(Equal("vrb_old"), "resource pinned to another PF is kept")
+	})
+})
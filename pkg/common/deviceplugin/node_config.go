@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package deviceplugin
+
+import (
+	"sort"
+	"strings"
+)
+
+// PinnedResource requests VFs of a PF to be served as a resource of custom name instead of the common one
+type PinnedResource struct {
+	ResourceName string
+	PFAddress    string
+	VFDeviceID   string
+	VFs          []string
+}
+
+/*****************************************************************************
+ * Function: NodeConfig
+ * Description: Generates the node specific config from the common one. Every
+ * 		pinned resource is a copy of the first common resource selecting
+ * 		its VF device ID, renamed and restricted to its VFs. Pinned
+ * 		resources precede common ones, so VFs are served by the pinned
+ * 		resource only. Pinned resources of the same name are merged; such
+ * 		resource is not pinned to a single PF. Common resources sharing
+ * 		a name with a pinned resource or pinned to one of its PFs, e.g.
+ * 		renamed in place by previous versions of the daemon, are left
+ * 		out. Returns names of pinned resources no common resource serves
+ * 		VFs of.
+ ****************************************************************************/
+func NodeConfig(common Config, pinned []PinnedResource) (Config, []string) {
+	var (
+		resources  []Resource
+		byName     = map[string]int{}
+		pinnedPFs  = map[string]bool{}
+		unresolved []string
+	)
+
+	sorted := append([]PinnedResource(nil), pinned...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PFAddress < sorted[j].PFAddress })
+
+	for _, p := range sorted {
+		template, ok := common.resourceServing(p.VFDeviceID)
+		if !ok {
+			unresolved = append(unresolved, p.ResourceName)
+			continue
+		}
+		pinnedPFs[p.PFAddress] = true
+
+		if i, ok := byName[p.ResourceName]; ok {
+			resources[i].Selectors.PciAddresses = append(resources[i].Selectors.PciAddresses, p.VFs...)
+			sort.Strings(resources[i].Selectors.PciAddresses)
+			delete(resources[i].AdditionalInfo[allDevices], PFAddressInfo)
+			continue
+		}
+
+		resource := template.DeepCopy()
+		resource.ResourceName = p.ResourceName
+		resource.Selectors.PciAddresses = append([]string(nil), p.VFs...)
+		sort.Strings(resource.Selectors.PciAddresses)
+		if resource.AdditionalInfo == nil {
+			resource.AdditionalInfo = map[string]map[string]string{}
+		}
+		if resource.AdditionalInfo[allDevices] == nil {
+			resource.AdditionalInfo[allDevices] = map[string]string{}
+		}
+		resource.AdditionalInfo[allDevices][PFAddressInfo] = p.PFAddress
+
+		byName[p.ResourceName] = len(resources)
+		resources = append(resources, resource)
+	}
+
+	for _, r := range common.ResourceList {
+		if _, ok := byName[r.ResourceName]; ok || pinnedPFs[r.PFAddress()] {
+			continue
+		}
+		resources = append(resources, r)
+	}
+
+	return Config{ResourceList: resources}, unresolved
+}
+
+// resourceServing returns the first resource selecting the VF device ID; resources not pinned to a PF are preferred
+func (c Config) resourceServing(vfDeviceID string) (Resource, bool) {
+	if vfDeviceID == "" {
+		return Resource{}, false
+	}
+	var (
+		pinned Resource
+		found  bool
+	)
+	for _, r := range c.ResourceList {
+		for _, device := range r.Selectors.Devices {
+			if !strings.EqualFold(device, vfDeviceID) {
+				continue
+			}
+			if r.PFAddress() == "" && len(r.Selectors.PciAddresses) == 0 {
+				return r, true
+			}
+			if !found {
+				pinned, found = r, true
+			}
+		}
+	}
+	return pinned, found
+}
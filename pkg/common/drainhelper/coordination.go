@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package drainhelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DrainLockAnnotationEnvVarName names the Node annotation used as a drain-lock shared with other
+	// node-level operators. Setting it to an empty value disables the drain-lock.
+	DrainLockAnnotationEnvVarName = "DRAIN_LOCK_ANNOTATION"
+	DrainLockAnnotationDefault    = "sriovfec.intel.com/drain-lock"
+	// CordonOwnerAnnotation is set on the Node cordoned by the daemon; the daemon uncordons only nodes it owns
+	CordonOwnerAnnotation = "sriovfec.intel.com/cordon-owner"
+
+	drainOwner = "sriov-fec-daemon"
+)
+
+var (
+	nodeMaintenanceGVR = schema.GroupVersionResource{
+		Group:    "nodemaintenance.medik8s.io",
+		Version:  "v1beta1",
+		Resource: "nodemaintenances",
+	}
+	nodeMaintenancePollInterval = 10 * time.Second
+)
+
+// drainCoordinator implements the drain-lock protocol shared with other operators draining nodes,
+// tracks the ownership of the cordon and delegates drain to NodeMaintenance operator when it is deployed
+type drainCoordinator struct {
+	log            *logrus.Logger
+	client         kubernetes.Interface
+	dynamicClient  dynamic.Interface
+	nodeName       string
+	lockAnnotation string
+}
+
+func (c *drainCoordinator) getNode(ctx context.Context) (*corev1.Node, error) {
+	return c.client.CoreV1().Nodes().Get(ctx, c.nodeName, metav1.GetOptions{})
+}
+
+// patchAnnotation sets (or removes when value is nil) the annotation of the node. When resourceVersion is not empty,
+// the patch fails with conflict if the node has been modified in the meantime.
+func (c *drainCoordinator) patchAnnotation(ctx context.Context, key string, value *string, resourceVersion string) error {
+	metadata := map[string]interface{}{
+		"annotations": map[string]interface{}{key: value},
+	}
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.CoreV1().Nodes().Patch(ctx, c.nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::acquireLock
+ * Description: Waits until the drain-lock annotation of the node is free and
+ * 		takes it over. Returns an error if the lock is still held by another
+ * 		component when backoff is exhausted.
+ ****************************************************************************/
+func (c *drainCoordinator) acquireLock(ctx context.Context, backoff wait.Backoff) error {
+	if c.lockAnnotation == "" {
+		return nil
+	}
+
+	var holder string
+	owner := drainOwner
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+		node, err := c.getNode(ctx)
+		if err != nil {
+			return false, err
+		}
+		holder = node.Annotations[c.lockAnnotation]
+		if holder != "" && holder != drainOwner {
+			c.log.WithField("lock", c.lockAnnotation).WithField("holder", holder).Info("node drain-lock is held by another component - waiting")
+			return false, nil
+		}
+		if err := c.patchAnnotation(ctx, c.lockAnnotation, &owner, node.ResourceVersion); err != nil {
+			if k8serrors.IsConflict(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("node drain-lock %s is held by %s", c.lockAnnotation, holder)
+	}
+	if err == nil {
+		c.log.WithField("lock", c.lockAnnotation).Info("node drain-lock acquired")
+	}
+	return err
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::releaseLock
+ * Description: Releases the drain-lock if it is held by the daemon
+ ****************************************************************************/
+func (c *drainCoordinator) releaseLock(ctx context.Context) error {
+	if c.lockAnnotation == "" {
+		return nil
+	}
+
+	node, err := c.getNode(ctx)
+	if err != nil {
+		return err
+	}
+	if holder := node.Annotations[c.lockAnnotation]; holder != drainOwner {
+		c.log.WithField("lock", c.lockAnnotation).WithField("holder", holder).Info("node drain-lock is not held by the daemon - nothing to release")
+		return nil
+	}
+	if err := c.patchAnnotation(ctx, c.lockAnnotation, nil, node.ResourceVersion); err != nil {
+		return err
+	}
+	c.log.WithField("lock", c.lockAnnotation).Info("node drain-lock released")
+	return nil
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::claimCordon
+ * Description: Marks the daemon as the owner of the cordon unless the node
+ * 		is already cordoned by someone else
+ ****************************************************************************/
+func (c *drainCoordinator) claimCordon(ctx context.Context) error {
+	node, err := c.getNode(ctx)
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable && node.Annotations[CordonOwnerAnnotation] != drainOwner {
+		c.log.WithField("nodeName", c.nodeName).Info("node is already cordoned by someone else - it will not be uncordoned by the daemon")
+		return nil
+	}
+	owner := drainOwner
+	return c.patchAnnotation(ctx, CordonOwnerAnnotation, &owner, "")
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::ownsCordon
+ * Description: Returns true if the node is not cordoned or it is cordoned
+ * 		by the daemon
+ ****************************************************************************/
+func (c *drainCoordinator) ownsCordon(ctx context.Context) (bool, error) {
+	node, err := c.getNode(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !node.Spec.Unschedulable || node.Annotations[CordonOwnerAnnotation] == drainOwner, nil
+}
+
+func (c *drainCoordinator) releaseCordon(ctx context.Context) error {
+	node, err := c.getNode(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := node.Annotations[CordonOwnerAnnotation]; !ok {
+		return nil
+	}
+	return c.patchAnnotation(ctx, CordonOwnerAnnotation, nil, "")
+}
+
+func (c *drainCoordinator) nodeMaintenanceName() string {
+	return fmt.Sprintf("%s-%s", drainOwner, c.nodeName)
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::nodeMaintenanceAvailable
+ * Description: Returns true if NodeMaintenance CRD is served by the cluster
+ ****************************************************************************/
+func (c *drainCoordinator) nodeMaintenanceAvailable() bool {
+	if c.dynamicClient == nil {
+		return false
+	}
+	resources, err := c.client.Discovery().ServerResourcesForGroupVersion(nodeMaintenanceGVR.GroupVersion().String())
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			c.log.WithError(err).Warn("failed to discover NodeMaintenance API")
+		}
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == nodeMaintenanceGVR.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::startNodeMaintenance
+ * Description: Creates NodeMaintenance CR for the node and waits until the
+ * 		NodeMaintenance operator cordons and drains the node
+ ****************************************************************************/
+func (c *drainCoordinator) startNodeMaintenance(ctx context.Context, timeout time.Duration) error {
+	nm := &unstructured.Unstructured{}
+	nm.SetGroupVersionKind(nodeMaintenanceGVR.GroupVersion().WithKind("NodeMaintenance"))
+	nm.SetName(c.nodeMaintenanceName())
+	nm.Object["spec"] = map[string]interface{}{
+		"nodeName": c.nodeName,
+		"reason":   "accelerators reconfiguration by " + drainOwner,
+	}
+
+	resource := c.dynamicClient.Resource(nodeMaintenanceGVR)
+	if _, err := resource.Create(ctx, nm, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create NodeMaintenance: %w", err)
+	}
+	c.log.WithField("name", nm.GetName()).Info("NodeMaintenance created - waiting for the node to be drained")
+
+	err := wait.PollImmediateWithContext(ctx, nodeMaintenancePollInterval, timeout, func(ctx context.Context) (bool, error) {
+		current, err := resource.Get(ctx, nm.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase, _, _ := unstructured.NestedString(current.Object, "status", "phase")
+		switch phase {
+		case "Succeeded":
+			return true, nil
+		case "Failed":
+			lastError, _, _ := unstructured.NestedString(current.Object, "status", "lastError")
+			return false, fmt.Errorf("NodeMaintenance failed: %s", lastError)
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("NodeMaintenance %s did not succeed in %v", nm.GetName(), timeout)
+	}
+	return err
+}
+
+/*****************************************************************************
+ * Method: drainCoordinator::stopNodeMaintenance
+ * Description: Deletes NodeMaintenance CR, what makes the NodeMaintenance
+ * 		operator uncordon the node
+ ****************************************************************************/
+func (c *drainCoordinator) stopNodeMaintenance(ctx context.Context) error {
+	err := c.dynamicClient.Resource(nodeMaintenanceGVR).Delete(ctx, c.nodeMaintenanceName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete NodeMaintenance: %w", err)
+	}
+	c.log.WithField("name", c.nodeMaintenanceName()).Info("NodeMaintenance deleted")
+	return nil
+}
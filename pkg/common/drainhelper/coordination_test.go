@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package drainhelper
+
+import (
+	"context"
+	"time"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("drainCoordinator", func() {
+	var (
+		coordinator *drainCoordinator
+		clientSet   *fake.Clientset
+		backoff     = wait.Backoff{Steps: 2, Duration: 10 * time.Millisecond, Factor: 1}
+	)
+
+	newNode := func(unschedulable bool, annotations map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: "worker", Annotations: annotations},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		}
+	}
+
+	annotations := func() map[string]string {
+		node, err := clientSet.CoreV1().Nodes().Get(context.TODO(), "worker", v1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return node.Annotations
+	}
+
+	setup := func(node *corev1.Node) {
+		clientSet = fake.NewSimpleClientset(node)
+		coordinator = &drainCoordinator{
+			log:            utils.NewLogger(),
+			client:         clientSet,
+			nodeName:       "worker",
+			lockAnnotation: DrainLockAnnotationDefault,
+		}
+	}
+
+	Context("drain-lock", func() {
+		It("should acquire and release free lock", func() {
+			setup(newNode(false, nil))
+
+			Expect(coordinator.acquireLock(context.TODO(), backoff)).To(Succeed())
+			Expect(annotations()).To(HaveKeyWithValue(DrainLockAnnotationDefault, drainOwner))
+
+			Expect(coordinator.releaseLock(context.TODO())).To(Succeed())
+			Expect(annotations()).ToNot(HaveKey(DrainLockAnnotationDefault))
+		})
+
+		It("should fail when lock is held by another component", func() {
+			setup(newNode(false, map[string]string{DrainLockAnnotationDefault: "other-operator"}))
+
+			err := coordinator.acquireLock(context.TODO(), backoff)
+			Expect(err).To(MatchError(ContainSubstring("held by other-operator")))
+		})
+
+		It("should not release lock held by another component", func() {
+			setup(newNode(false, map[string]string{DrainLockAnnotationDefault: "other-operator"}))
+
+			Expect(coordinator.releaseLock(context.TODO())).To(Succeed())
+			Expect(annotations()).To(HaveKeyWithValue(DrainLockAnnotationDefault, "other-operator"))
+		})
+
+		It("should do nothing when lock is disabled", func() {
+			setup(newNode(false, map[string]string{DrainLockAnnotationDefault: "other-operator"}))
+			coordinator.lockAnnotation = ""
+
+			Expect(coordinator.acquireLock(context.TODO(), backoff)).To(Succeed())
+			Expect(coordinator.releaseLock(context.TODO())).To(Succeed())
+		})
+	})
+
+	Context("cordon ownership", func() {
+		It("should own cordon of schedulable node", func() {
+			setup(newNode(false, nil))
+
+			Expect(coordinator.claimCordon(context.TODO())).To(Succeed())
+			Expect(annotations()).To(HaveKeyWithValue(CordonOwnerAnnotation, drainOwner))
+			Expect(coordinator.ownsCordon(context.TODO())).To(BeTrue())
+
+			Expect(coordinator.releaseCordon(context.TODO())).To(Succeed())
+			Expect(annotations()).ToNot(HaveKey(CordonOwnerAnnotation))
+		})
+
+		It("should not own cordon of node cordoned by someone else", func() {
+			setup(newNode(true, nil))
+
+			Expect(coordinator.claimCordon(context.TODO())).To(Succeed())
+			Expect(annotations()).ToNot(HaveKey(CordonOwnerAnnotation))
+			Expect(coordinator.ownsCordon(context.TODO())).To(BeFalse())
+		})
+	})
+
+	Context("NodeMaintenance", func() {
+		var dynamicClient *dynamicfake.FakeDynamicClient
+
+		BeforeEach(func() {
+			setup(newNode(false, nil))
+			dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			coordinator.dynamicClient = dynamicClient
+			nodeMaintenancePollInterval = 10 * time.Millisecond
+		})
+
+		It("should not be available when API is not served", func() {
+			Expect(coordinator.nodeMaintenanceAvailable()).To(BeFalse())
+		})
+
+		It("should be available when API is served", func() {
+			clientSet.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*v1.APIResourceList{{
+				GroupVersion: nodeMaintenanceGVR.GroupVersion().String(),
+				APIResources: []v1.APIResource{{Name: nodeMaintenanceGVR.Resource}},
+			}}
+			Expect(coordinator.nodeMaintenanceAvailable()).To(BeTrue())
+		})
+
+		It("should create NodeMaintenance, wait for its success and delete it", func() {
+			go func() {
+				defer GinkgoRecover()
+				Eventually(func() error {
+					nm, err := dynamicClient.Resource(nodeMaintenanceGVR).Get(context.TODO(), coordinator.nodeMaintenanceName(), v1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					Expect(unstructured.SetNestedField(nm.Object, "Succeeded", "status", "phase")).To(Succeed())
+					_, err = dynamicClient.Resource(nodeMaintenanceGVR).Update(context.TODO(), nm, v1.UpdateOptions{})
+					return err
+				}, time.Second, 10*time.Millisecond).Should(Succeed())
+			}()
+
+			Expect(coordinator.startNodeMaintenance(context.TODO(), time.Second)).To(Succeed())
+
+			nm, err := dynamicClient.Resource(nodeMaintenanceGVR).Get(context.TODO(), coordinator.nodeMaintenanceName(), v1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			nodeName, _, _ := unstructured.NestedString(nm.Object, "spec", "nodeName")
+			Expect(nodeName).To(Equal("worker"))
+
+			Expect(coordinator.stopNodeMaintenance(context.TODO())).To(Succeed())
+			_, err = dynamicClient.Resource(nodeMaintenanceGVR).Get(context.TODO(), coordinator.nodeMaintenanceName(), v1.GetOptions{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should time out when NodeMaintenance does not succeed", func() {
+			err := coordinator.startNodeMaintenance(context.TODO(), 50*time.Millisecond)
+			Expect(err).To(MatchError(ContainSubstring("did not succeed")))
+		})
+	})
+})
@@ -14,7 +14,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -38,12 +41,57 @@ func (w logWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// DrainOptions controls cordon & drain performed before the worker function is executed
+type DrainOptions struct {
+	// Drain enables cordon & drain of the node
+	Drain bool
+
+	// ConsumersOnly limits the eviction to pods requesting any of ResourceNames and keeps the node schedulable.
+	// Drain is skipped entirely when there are no such pods on the node.
+	ConsumersOnly bool
+
+	// ResourceNames lists extended resources (e.g. intel.com/intel_fec_acc100) served from reconfigured accelerators
+	ResourceNames []string
+
+	// Timeout overrides the timeout of a single drain attempt set by DRAIN_TIMEOUT_SECONDS
+	Timeout *time.Duration
+
+	// GracePeriodSeconds overrides the grace period given to evicted pods
+	GracePeriodSeconds *int
+
+	// DeleteOnEvictionFailure deletes pods which cannot be evicted e.g. because of PodDisruptionBudget
+	DeleteOnEvictionFailure bool
+
+	// ExcludedPods selects pods which are never evicted
+	ExcludedPods labels.Selector
+
+	// Backoff overrides the backoff between failed (un)cordon & drain attempts
+	Backoff *wait.Backoff
+
+	// Lease overrides leader election timings
+	Lease *LeaseTimings
+
+	// BeforeDrain is invoked by the leader before the node is drained, also when the drain is skipped;
+	// neither the drain nor the worker function is executed when it fails
+	BeforeDrain func(context.Context) error
+}
+
+// LeaseTimings of the leader election serializing drains across the cluster
+type LeaseTimings struct {
+	Duration      time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+var defaultBackoff = wait.Backoff{Steps: 5, Duration: 15 * time.Second, Factor: 2}
+
 type DrainHelper struct {
 	log       *logrus.Logger
 	clientSet *clientset.Clientset
 	nodeName  string
 
 	drainer              *drain.Helper
+	coordinator          *drainCoordinator
 	leaseLock            *resourcelock.LeaseLock
 	leaderElectionConfig leaderelection.LeaderElectionConfig
 }
@@ -74,6 +122,12 @@ func NewDrainHelper(log *logrus.Logger, cs *clientset.Clientset, nodeName, names
 	}
 	log.WithField("duration seconds", leaseDur).Info("lease settings")
 
+	lockAnnotation := DrainLockAnnotationDefault
+	if val, ok := os.LookupEnv(DrainLockAnnotationEnvVarName); ok {
+		lockAnnotation = val
+	}
+	log.WithField("annotation", lockAnnotation).Info("drain-lock settings")
+
 	lock := &resourcelock.LeaseLock{
 		LeaseMeta: metav1.ObjectMeta{
 			Name:      "n3000-daemon-lease",
@@ -109,12 +163,24 @@ func NewDrainHelper(log *logrus.Logger, cs *clientset.Clientset, nodeName, names
 			Out:    logWriter{log},
 			ErrOut: logWriter{log},
 		},
+		coordinator: &drainCoordinator{
+			log:            log,
+			client:         cs,
+			nodeName:       nodeName,
+			lockAnnotation: lockAnnotation,
+		},
 
 		leaseLock:            lock,
 		leaderElectionConfig: CustomizedLeaderElectionConfig(lock, leaseDur, isSingleNodeCluster),
 	}
 }
 
+// EnableNodeMaintenance makes the DrainHelper delegate full node drains to the NodeMaintenance operator
+// whenever NodeMaintenance API is served by the cluster
+func (dh *DrainHelper) EnableNodeMaintenance(dynamicClient dynamic.Interface) {
+	dh.coordinator.dynamicClient = dynamicClient
+}
+
 // More details about values are available here:
 // https://github.com/openshift/library-go/commit/2612981f3019479805ac8448b997266fc07a236a#diff-61dd95c7fd45fa18038e825205fbfab8a803f1970068157608b6b1e9e6c27248R127-R150
 func CustomizedLeaderElectionConfig(lock *resourcelock.LeaseLock, leaseDur int64, isSingleNodeCluster bool) leaderelection.LeaderElectionConfig {
@@ -133,13 +199,18 @@ func CustomizedLeaderElectionConfig(lock *resourcelock.LeaseLock, leaseDur int64
 	return lec
 }
 
-// Run joins leader election and drains(only if drain is set) the node if becomes a leader.
+// Run joins leader election and drains(only if opts.Drain is set) the node if becomes a leader.
+// opts.BeforeDrain is invoked first, so that steps preceding the drain are serialized across nodes as well.
 //
 // f is a function that takes a context and returns a bool.
 // It should return true if uncordon should be performed(Only applicable if drain is set to true).
 // If `f` returns false, the uncordon does not take place. This is useful in 2-step scenario like sriov-fec-daemon where
 // reboot must be performed without loosing the leadership and without the uncordon.
-func (dh *DrainHelper) Run(f func(context.Context) bool, drain bool) error {
+// When opts.ConsumersOnly is set, only pods requesting opts.ResourceNames are evicted, the node is never cordoned
+// and the drain is skipped if there are no such pods.
+// Before the node is cordoned, the drain-lock annotation shared with other node-level operators is acquired.
+// It is released together with the uncordon, so it is kept over the reboot in the 2-step scenario.
+func (dh *DrainHelper) Run(f func(context.Context) bool, opts DrainOptions) error {
 	defer func() {
 		// Following mitigation is needed because of the bug in the leader election's release functionality
 		// Release fails because the input (leader election record) is created incomplete (missing fields):
@@ -169,6 +240,11 @@ func (dh *DrainHelper) Run(f func(context.Context) bool, drain bool) error {
 	var innerErr error
 
 	lec := dh.leaderElectionConfig
+	if opts.Lease != nil {
+		lec.LeaseDuration = opts.Lease.Duration
+		lec.RenewDeadline = opts.Lease.RenewDeadline
+		lec.RetryPeriod = opts.Lease.RetryPeriod
+	}
 	lec.Callbacks = leaderelection.LeaderCallbacks{
 		OnStartedLeading: func(ctx context.Context) {
 			defer func() {
@@ -178,19 +254,72 @@ func (dh *DrainHelper) Run(f func(context.Context) bool, drain bool) error {
 
 			dh.log.Info("started leading")
 
+			if opts.BeforeDrain != nil {
+				if err := opts.BeforeDrain(ctx); err != nil {
+					dh.log.WithError(err).Error("pre-drain step failed")
+					innerErr = err
+					return
+				}
+			}
+
+			useNodeMaintenance := false
 			uncordon := func() {
 				// always try to uncordon the node
 				// e.g. when cordoning succeeds, but draining fails
 				dh.log.Info("uncordoning node")
-				if err := dh.uncordon(ctx); err != nil {
+				uncordonFunc := dh.uncordon
+				if useNodeMaintenance {
+					uncordonFunc = func(ctx context.Context, _ DrainOptions) error {
+						return dh.coordinator.stopNodeMaintenance(ctx)
+					}
+				}
+				if err := uncordonFunc(ctx, opts); err != nil {
 					dh.log.WithError(err).Error("uncordon failed")
 					innerErr = err
 				}
+				if err := dh.coordinator.releaseLock(ctx); err != nil {
+					dh.log.WithError(err).Error("failed to release the node drain-lock")
+					if innerErr == nil {
+						innerErr = err
+					}
+				}
+			}
+
+			doDrain := opts.Drain
+			if doDrain && opts.ConsumersOnly {
+				consumers, err := dh.acceleratorConsumers(ctx, opts.ResourceNames)
+				if err != nil {
+					dh.log.WithError(err).Error("failed to find accelerator consumers")
+					innerErr = err
+					return
+				}
+				if len(consumers) == 0 {
+					dh.log.WithField("resourceNames", opts.ResourceNames).Info("no accelerator consumers on the node - skipping drain")
+					doDrain = false
+				} else {
+					dh.log.WithField("pods", consumers).Info("accelerator consumers to be evicted")
+				}
 			}
 
-			if drain {
+			if doDrain {
+				if err := dh.coordinator.acquireLock(ctx, backoffFor(opts)); err != nil {
+					dh.log.WithError(err).Error("failed to acquire the node drain-lock")
+					innerErr = err
+					return
+				}
+
+				// targeted drain of accelerator consumers cannot be expressed by NodeMaintenance
+				useNodeMaintenance = !opts.ConsumersOnly && dh.coordinator.nodeMaintenanceAvailable()
+				drainFunc := dh.cordonAndDrain
+				if useNodeMaintenance {
+					dh.log.Info("delegating cordon & drain to NodeMaintenance operator")
+					drainFunc = func(ctx context.Context, opts DrainOptions) error {
+						return dh.coordinator.startNodeMaintenance(ctx, dh.drainerFor(opts).Timeout*time.Duration(backoffFor(opts).Steps))
+					}
+				}
+
 				dh.log.Info("cordoning & draining node")
-				if err := dh.cordonAndDrain(ctx); err != nil {
+				if err := drainFunc(ctx, opts); err != nil {
 					dh.log.WithError(err).Error("cordonAndDrain failed")
 					innerErr = err
 					uncordon()
@@ -201,7 +330,7 @@ func (dh *DrainHelper) Run(f func(context.Context) bool, drain bool) error {
 			dh.log.Info("worker function - start")
 			performUncordon := f(ctx)
 			dh.log.WithField("performUncordon", performUncordon).Info("worker function - end")
-			if drain && performUncordon {
+			if doDrain && performUncordon {
 				uncordon()
 			}
 		},
@@ -232,26 +361,127 @@ func (dh *DrainHelper) onNewLeaderFunction(id string) {
 	}
 }
 
-func (dh *DrainHelper) cordonAndDrain(ctx context.Context) error {
+// acceleratorConsumers returns names of pods running on the node which request any of resourceNames
+func (dh *DrainHelper) acceleratorConsumers(ctx context.Context, resourceNames []string) ([]string, error) {
+	pods, err := dh.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": dh.nodeName}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var consumers []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if IsAcceleratorConsumer(pod, resourceNames) {
+			consumers = append(consumers, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+	return consumers, nil
+}
+
+// acceleratorConsumersFilter skips eviction of pods which do not request any of resourceNames
+func acceleratorConsumersFilter(resourceNames []string) drain.PodFilter {
+	return func(pod corev1.Pod) drain.PodDeleteStatus {
+		if IsAcceleratorConsumer(pod, resourceNames) {
+			return drain.MakePodDeleteStatusOkay()
+		}
+		return drain.MakePodDeleteStatusSkip()
+	}
+}
+
+// excludedPodsFilter skips eviction of pods matching the selector
+func excludedPodsFilter(selector labels.Selector) drain.PodFilter {
+	return func(pod corev1.Pod) drain.PodDeleteStatus {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return drain.MakePodDeleteStatusSkip()
+		}
+		return drain.MakePodDeleteStatusOkay()
+	}
+}
+
+// drainerFor returns a copy of the drainer customized with opts
+func (dh *DrainHelper) drainerFor(opts DrainOptions) *drain.Helper {
+	drainer := *dh.drainer
+	drainer.AdditionalFilters = append([]drain.PodFilter{}, dh.drainer.AdditionalFilters...)
+	if opts.ConsumersOnly {
+		drainer.AdditionalFilters = append(drainer.AdditionalFilters, acceleratorConsumersFilter(opts.ResourceNames))
+	}
+	if opts.ExcludedPods != nil && !opts.ExcludedPods.Empty() {
+		drainer.AdditionalFilters = append(drainer.AdditionalFilters, excludedPodsFilter(opts.ExcludedPods))
+	}
+	if opts.Timeout != nil {
+		drainer.Timeout = *opts.Timeout
+	}
+	if opts.GracePeriodSeconds != nil {
+		drainer.GracePeriodSeconds = *opts.GracePeriodSeconds
+	}
+	return &drainer
+}
+
+func backoffFor(opts DrainOptions) wait.Backoff {
+	if opts.Backoff != nil {
+		return *opts.Backoff
+	}
+	return defaultBackoff
+}
+
+// IsAcceleratorConsumer returns true if any container of the pod requests or limits any of resourceNames
+func IsAcceleratorConsumer(pod corev1.Pod, resourceNames []string) bool {
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, rn := range resourceNames {
+			name := corev1.ResourceName(rn)
+			if _, ok := c.Resources.Requests[name]; ok {
+				return true
+			}
+			if _, ok := c.Resources.Limits[name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (dh *DrainHelper) cordonAndDrain(ctx context.Context, opts DrainOptions) error {
 	node, nodeGetErr := dh.clientSet.CoreV1().Nodes().Get(ctx, dh.nodeName, metav1.GetOptions{})
 	if nodeGetErr != nil {
 		dh.log.WithError(nodeGetErr).Error("failed to get the node object")
 		return nodeGetErr
 	}
 
+	drainer := dh.drainerFor(opts)
+
+	// targeted drain keeps the node schedulable; sriov-fec-daemon taints the node for the time of reconfiguration
+	cordon := !opts.ConsumersOnly
+	if cordon {
+		if err := dh.coordinator.claimCordon(ctx); err != nil {
+			dh.log.WithError(err).Error("failed to mark the owner of the cordon")
+			return err
+		}
+	}
+
 	var e error
-	backoff := wait.Backoff{Steps: 5, Duration: 15 * time.Second, Factor: 2}
+	backoff := backoffFor(opts)
 	f := func() (bool, error) {
-		if err := drain.RunCordonOrUncordon(dh.drainer, node, true); err != nil {
-			dh.log.WithField("nodeName", dh.nodeName).WithField("reason", err.Error()).
-				Info("failed to cordon the node - retrying")
-			e = err
-			return false, nil
+		if cordon {
+			if err := drain.RunCordonOrUncordon(drainer, node, true); err != nil {
+				dh.log.WithField("nodeName", dh.nodeName).WithField("reason", err.Error()).
+					Info("failed to cordon the node - retrying")
+				e = err
+				return false, nil
+			}
 		}
 
-		if err := drain.RunNodeDrain(dh.drainer, dh.nodeName); err != nil {
+		if err := drain.RunNodeDrain(drainer, dh.nodeName); err != nil {
 			dh.log.WithField("nodeName", dh.nodeName).WithField("reason", err.Error()).
 				Info("failed to drain the node - retrying")
+			if opts.DeleteOnEvictionFailure && !drainer.DisableEviction {
+				dh.log.Info("falling back to pod deletion")
+				drainer.DisableEviction = true
+			}
 			e = err
 			return false, nil
 		}
@@ -273,15 +503,30 @@ func (dh *DrainHelper) cordonAndDrain(ctx context.Context) error {
 	return nil
 }
 
-func (dh *DrainHelper) uncordon(ctx context.Context) error {
+func (dh *DrainHelper) uncordon(ctx context.Context, opts DrainOptions) error {
+	if opts.ConsumersOnly {
+		// node was not cordoned by the targeted drain
+		return nil
+	}
+
 	node, err := dh.clientSet.CoreV1().Nodes().Get(ctx, dh.nodeName, metav1.GetOptions{})
 	if err != nil {
 		dh.log.WithError(err).Error("failed to get the node object")
 		return err
 	}
 
+	owned, err := dh.coordinator.ownsCordon(ctx)
+	if err != nil {
+		dh.log.WithError(err).Error("failed to check the owner of the cordon")
+		return err
+	}
+	if !owned {
+		dh.log.WithField("nodeName", dh.nodeName).Info("node is cordoned by someone else - skipping uncordon")
+		return nil
+	}
+
 	var e error
-	backoff := wait.Backoff{Steps: 5, Duration: 15 * time.Second, Factor: 2}
+	backoff := backoffFor(opts)
 	f := func() (bool, error) {
 		if err := drain.RunCordonOrUncordon(dh.drainer, node, false); err != nil {
 			dh.log.WithField("nodeName", dh.nodeName).WithError(err).Error("failed to uncordon the node - retrying")
@@ -303,5 +548,9 @@ func (dh *DrainHelper) uncordon(ctx context.Context) error {
 	}
 	dh.log.Info("node uncordoned")
 
+	if err := dh.coordinator.releaseCordon(ctx); err != nil {
+		dh.log.WithError(err).Warn("failed to remove the owner of the cordon")
+	}
+
 	return nil
 }
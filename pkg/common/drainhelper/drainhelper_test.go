@@ -15,6 +15,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 )
@@ -80,7 +82,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "node", "namespace", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.Run(func(c context.Context) bool { return true }, true)
+			err = dh.Run(func(c context.Context) bool { return true }, DrainOptions{Drain: true})
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -94,7 +96,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "node", "namespace", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.cordonAndDrain(context.Background())
+			err = dh.cordonAndDrain(context.Background(), DrainOptions{})
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -108,7 +110,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "node", "namespace", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.uncordon(context.Background())
+			err = dh.uncordon(context.Background(), DrainOptions{})
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -166,7 +168,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "dummy", "namespace", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.cordonAndDrain(context.Background())
+			err = dh.cordonAndDrain(context.Background(), DrainOptions{})
 			Expect(err).ToNot(HaveOccurred())
 
 			// Cleanup
@@ -193,10 +195,10 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "dummy", "namespace", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.cordonAndDrain(context.Background())
+			err = dh.cordonAndDrain(context.Background(), DrainOptions{})
 			Expect(err).ToNot(HaveOccurred())
 
-			err = dh.uncordon(context.Background())
+			err = dh.uncordon(context.Background(), DrainOptions{})
 			Expect(err).ToNot(HaveOccurred())
 
 			// Cleanup
@@ -204,6 +206,26 @@ var _ = Describe("DrainHelper Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		var _ = It("Drain accelerator consumers without cordoning the node", func() {
+			node := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "dummy"}}
+			Expect(k8sClient.Create(context.Background(), node)).To(Succeed())
+
+			cset, err := clientset.NewForConfig(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			dh := NewDrainHelper(log, cset, "dummy", "namespace", false)
+			opts := DrainOptions{Drain: true, ConsumersOnly: true, ResourceNames: []string{"intel.com/intel_fec_acc100"}}
+			Expect(dh.cordonAndDrain(context.Background(), opts)).To(Succeed())
+
+			node, err = cset.CoreV1().Nodes().Get(context.Background(), "dummy", v1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Spec.Unschedulable).To(BeFalse())
+			Expect(dh.uncordon(context.Background(), opts)).To(Succeed())
+
+			// Cleanup
+			Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+		})
+
 		var _ = It("Create and run simple DrainHelper with drain true", func() {
 			var err error
 			// Create a Node
@@ -228,7 +250,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "dummy", "default", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.Run(func(c context.Context) bool { return true }, true)
+			err = dh.Run(func(c context.Context) bool { return true }, DrainOptions{Drain: true})
 			Expect(err).ToNot(HaveOccurred())
 
 			// Cleanup
@@ -260,7 +282,7 @@ var _ = Describe("DrainHelper Tests", func() {
 			dh := NewDrainHelper(log, cset, "dummy", "default", false)
 			Expect(dh).ToNot(Equal(nil))
 
-			err = dh.Run(func(c context.Context) bool { return true }, false)
+			err = dh.Run(func(c context.Context) bool { return true }, DrainOptions{Drain: false})
 			Expect(err).ToNot(HaveOccurred())
 
 			// Cleanup
@@ -268,4 +290,35 @@ var _ = Describe("DrainHelper Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	var _ = Describe("drainerFor", func() {
+		var _ = It("applies drain options on top of the defaults", func() {
+			dh := NewDrainHelper(log, &clientSet, "node", "namespace", false)
+			timeout := 30 * time.Second
+			gracePeriod := 10
+			selector, err := labels.Parse("app=critical")
+			Expect(err).ToNot(HaveOccurred())
+
+			drainer := dh.drainerFor(DrainOptions{
+				Timeout:            &timeout,
+				GracePeriodSeconds: &gracePeriod,
+				ExcludedPods:       selector,
+			})
+			Expect(drainer.Timeout).To(Equal(timeout))
+			Expect(drainer.GracePeriodSeconds).To(Equal(gracePeriod))
+			Expect(drainer.AdditionalFilters).To(HaveLen(1))
+			Expect(dh.drainer.AdditionalFilters).To(BeEmpty())
+
+			critical := corev1.Pod{ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "critical"}}}
+			Expect(drainer.AdditionalFilters[0](critical).Delete).To(BeFalse())
+			other := corev1.Pod{ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "other"}}}
+			Expect(drainer.AdditionalFilters[0](other).Delete).To(BeTrue())
+		})
+
+		var _ = It("falls back to default backoff", func() {
+			Expect(backoffFor(DrainOptions{})).To(Equal(defaultBackoff))
+			backoff := wait.Backoff{Steps: 2, Duration: time.Second, Factor: 1}
+			Expect(backoffFor(DrainOptions{Backoff: &backoff})).To(Equal(backoff))
+		})
+	})
 })
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FeatureGatesEnvVarName names env variable holding feature gates of the daemon, e.g. "NodeMaintenance=false"
+	FeatureGatesEnvVarName = "FEATURE_GATES"
+
+	// FeatureNodeMaintenance allows the daemon to drain nodes through NodeMaintenance objects when the API is served
+	FeatureNodeMaintenance = "NodeMaintenance"
+	// FeatureNodeFeatureDiscovery makes the daemon publish its accelerators as local features of Node Feature Discovery
+	FeatureNodeFeatureDiscovery = "NodeFeatureDiscovery"
+	// FeatureDevicePlugin makes the daemon serve VFs to the kubelet instead of sriov-network-device-plugin
+	FeatureDevicePlugin = "DevicePlugin"
+	// FeatureContainerDeviceInterface makes the daemon generate Container Device Interface specs of VFs
+	FeatureContainerDeviceInterface = "ContainerDeviceInterface"
+)
+
+// KnownFeatureGates holds the default state of every supported feature
+var KnownFeatureGates = map[string]bool{
+	FeatureNodeMaintenance:          true,
+	FeatureNodeFeatureDiscovery:     false,
+	FeatureDevicePlugin:             false,
+	FeatureContainerDeviceInterface: false,
+}
+
+// FeatureGates holds explicitly enabled or disabled features
+type FeatureGates map[string]bool
+
+// ParseFeatureGates parses comma separated list of <feature>=<bool> pairs
+func ParseFeatureGates(value string) (FeatureGates, error) {
+	gates := FeatureGates{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid feature gate %q, expected <feature>=<bool>", pair)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %q: %v", pair, err)
+		}
+		gates[strings.TrimSpace(kv[0])] = enabled
+	}
+	return gates, gates.Validate()
+}
+
+// Validate returns an error if any of the gates is not known
+func (f FeatureGates) Validate() error {
+	var unknown []string
+	for name := range f {
+		if _, known := KnownFeatureGates[name]; !known {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown feature gates: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Enabled returns state of the feature; the default state is used when it is not set explicitly
+func (f FeatureGates) Enabled(name string) bool {
+	if enabled, ok := f[name]; ok {
+		return enabled
+	}
+	return KnownFeatureGates[name]
+}
+
+// String returns gates in the format accepted by ParseFeatureGates
+func (f FeatureGates) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, enabled := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
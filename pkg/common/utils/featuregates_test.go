@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FeatureGates", func() {
+	var _ = It("should parse gates and fall back to defaults", func() {
+		gates, err := ParseFeatureGates(" NodeMaintenance=false ,")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gates.Enabled(FeatureNodeMaintenance)).To(BeFalse())
+		Expect(gates.String()).To(Equal("NodeMaintenance=false"))
+
+		gates, err = ParseFeatureGates("")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gates.Enabled(FeatureNodeMaintenance)).To(BeTrue())
+	})
+
+	var _ = It("should reject malformed and unknown gates", func() {
+		_, err := ParseFeatureGates("NodeMaintenance")
+		Expect(err).To(MatchError(ContainSubstring("expected <feature>=<bool>")))
+
+		_, err = ParseFeatureGates("NodeMaintenance=maybe")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseFeatureGates("Teleport=true")
+		Expect(err).To(MatchError("unknown feature gates: Teleport"))
+	})
+})
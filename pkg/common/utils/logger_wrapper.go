@@ -86,3 +86,28 @@ func NewLogWrapper() *logrusWrapper {
 		log: NewLogger(),
 	}
 }
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// ConfigureLogger sets level and format (json if empty) of the logger; returns true if any of them changed
+func ConfigureLogger(log *logrus.Logger, level logrus.Level, format string) bool {
+	changed := false
+	if log.GetLevel() != level {
+		log.SetLevel(level)
+		changed = true
+	}
+
+	_, isJSON := log.Formatter.(*logrus.JSONFormatter)
+	switch {
+	case format == LogFormatText && isJSON:
+		log.SetFormatter(&logrus.TextFormatter{})
+		changed = true
+	case format != LogFormatText && !isJSON:
+		log.SetFormatter(&logrus.JSONFormatter{})
+		changed = true
+	}
+	return changed
+}
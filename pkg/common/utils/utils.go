@@ -33,8 +33,58 @@ const (
 	VfioPci                    = "vfio-pci"
 	VfioPciUnderscore          = "vfio_pci"
 	IgbUio                     = "igb_uio"
+
+	// ReconfiguringTaintKey marks nodes whose accelerators are being reconfigured
+	ReconfiguringTaintKey = "sriovfec.intel.com/reconfiguring"
+	// ReconfigurationProgressAnnotation exposes the progress of accelerator reconfiguration on the node
+	ReconfigurationProgressAnnotation = "sriovfec.intel.com/reconfiguration-progress"
+	// ApprovedGenerationAnnotation approves reconfiguration of the node config with given generation
+	ApprovedGenerationAnnotation = "sriovfec.intel.com/approved-generation"
+	// VfStatusReadyCondition of node configs reflects vf_status telemetry reported by pf-bb-config
+	VfStatusReadyCondition = "VfStatusReady"
+
+	// RevisionKindLabel and RevisionOfLabel identify the config a ControllerRevision was recorded for
+	RevisionKindLabel = "sriovfec.intel.com/revision-kind"
+	RevisionOfLabel   = "sriovfec.intel.com/revision-of"
+	// RevisionGenerationAnnotation holds the generation of the config the revision was recorded for
+	RevisionGenerationAnnotation = "sriovfec.intel.com/generation"
+	// RevisionResultAnnotation and RevisionResultTimeAnnotation hold the result of applying the revision and when it was observed
+	RevisionResultAnnotation     = "sriovfec.intel.com/result"
+	RevisionResultTimeAnnotation = "sriovfec.intel.com/result-time"
+	// RollbackToRevisionAnnotation requests rollback of the cluster config to the revision with given number
+	RollbackToRevisionAnnotation = "sriovfec.intel.com/rollback-to-revision"
+	// UnmanagedAnnotation set to "true" stops the operator from re-applying the desired state of the object
+	UnmanagedAnnotation = "sriovfec.intel.com/unmanaged"
+	// TeardownFinalizer blocks deletion of SriovFecOperatorConfig until accelerators are reset on all nodes
+	TeardownFinalizer = "sriovfec.intel.com/teardown"
+	// TeardownCompletedAnnotation holds the UID of the SriovFecOperatorConfig whose teardown the daemon completed on the node
+	TeardownCompletedAnnotation = "sriovfec.intel.com/teardown-completed"
+	// NodeGroupLabel identifies the group of nodes a DaemonSet rendered per node group and its pods belong to
+	NodeGroupLabel = "sriovfec.intel.com/node-group"
+	// NodeGroupAnnotation holds the kernel version or the label value shared by nodes of the group
+	NodeGroupAnnotation = "sriovfec.intel.com/node-group-value"
+	// NodeGroupAssetLabel identifies the asset a DaemonSet rendered per node group was deployed from
+	NodeGroupAssetLabel = "sriovfec.intel.com/node-group-asset"
+
+	// AcceleratorFamilyLabelPrefix prefixes labeler labels of detected accelerator families, e.g. sriovfec.intel.com/family.vrb=true
+	AcceleratorFamilyLabelPrefix = "sriovfec.intel.com/family."
+	// AcceleratorModelLabelPrefix prefixes labeler labels with the number of accelerators of each model, e.g. sriovfec.intel.com/accelerator.ACC100=2
+	AcceleratorModelLabelPrefix = "sriovfec.intel.com/accelerator."
+	// AcceleratorNumaNodesLabel lists NUMA nodes accelerators of the node are local to, separated by dots, e.g. 0.1
+	AcceleratorNumaNodesLabel = "sriovfec.intel.com/accelerator-numa-nodes"
+	// SriovCapableLabel marks nodes with an accelerator supporting SR-IOV
+	SriovCapableLabel = "sriovfec.intel.com/sriov-capable"
+	// AcceleratorInventoryAnnotation holds the JSON list of accelerators the labeler found on the node
+	AcceleratorInventoryAnnotation = "sriovfec.intel.com/accelerator-inventory"
 )
 
+// IsAcceleratorLabel tells whether the node label describing accelerators is set by the labeler, apart from the
+// node label of discovery configs
+func IsAcceleratorLabel(label string) bool {
+	return strings.HasPrefix(label, AcceleratorFamilyLabelPrefix) || strings.HasPrefix(label, AcceleratorModelLabelPrefix) ||
+		label == AcceleratorNumaNodesLabel || label == SriovCapableLabel
+}
+
 func LoadDiscoveryConfig(cfgPath string) (AcceleratorDiscoveryConfig, error) {
 	var cfg AcceleratorDiscoveryConfig
 	file, err := os.Open(filepath.Clean(cfgPath))
@@ -102,17 +152,26 @@ var GetPCIDevices = func() ([]*ghw.PCIDevice, error) {
 }
 
 func FindAccelerator(cfgPath string) (bool, string, error) {
+	cfg, devices, err := FindAcceleratorDevices(cfgPath)
+	if err != nil || len(devices) == 0 {
+		return false, "", err
+	}
+	return true, cfg.NodeLabel, nil
+}
 
+// FindAcceleratorDevices returns the discovery config loaded from the path and all PCI devices matching it
+func FindAcceleratorDevices(cfgPath string) (AcceleratorDiscoveryConfig, []*ghw.PCIDevice, error) {
 	cfg, err := LoadDiscoveryConfig(cfgPath)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to load config: %v", err)
+		return cfg, nil, fmt.Errorf("failed to load config: %v", err)
 	}
 
 	devices, err := GetPCIDevices()
 	if err != nil {
-		return false, "", fmt.Errorf("failed to get PCI devices: %v", err)
+		return cfg, nil, fmt.Errorf("failed to get PCI devices: %v", err)
 	}
 
+	var found []*ghw.PCIDevice
 	for _, device := range devices {
 		_, exist := cfg.VendorID[device.Vendor.ID]
 		if !(exist &&
@@ -123,10 +182,10 @@ func FindAccelerator(cfgPath string) (bool, string, error) {
 
 		if _, ok := cfg.Devices[device.Product.ID]; ok {
 			fmt.Printf("[%s]Accelerator found %v\n", cfgPath, device)
-			return true, cfg.NodeLabel, nil
+			found = append(found, device)
 		}
 	}
-	return false, "", nil
+	return cfg, found, nil
 }
 
 // Function to find all VFs associated with a given PF PCI address
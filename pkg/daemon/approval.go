@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ConditionPendingApproval string                       = "PendingApproval"
+	ApprovalRequired         ConfigurationConditionReason = "ApprovalRequired"
+)
+
+/*****************************************************************************
+ * Function: isGenerationApproved
+ * Description: Returns true if the approval annotation of the node config
+ * 		carries its current generation
+ ****************************************************************************/
+func isGenerationApproved(nc metav1.Object) bool {
+	return nc.GetAnnotations()[utils.ApprovedGenerationAnnotation] == strconv.FormatInt(nc.GetGeneration(), 10)
+}
+
+/*****************************************************************************
+ * Function: describePfChanges
+ * Description: Returns human readable differences between previously applied
+ * 		and requested configurations of physical functions, keyed by PCI address
+ ****************************************************************************/
+func describePfChanges[T any](applied, requested map[string]T) []string {
+	var changes []string
+	for _, pci := range sortedUnion(applied, requested) {
+		previous, wasApplied := applied[pci]
+		current, isRequested := requested[pci]
+		switch {
+		case !wasApplied:
+			changes = append(changes, fmt.Sprintf("%s: added", pci))
+		case !isRequested:
+			changes = append(changes, fmt.Sprintf("%s: removed", pci))
+		default:
+			for _, field := range changedFields(previous, current) {
+				changes = append(changes, fmt.Sprintf("%s: %s", pci, field))
+			}
+		}
+	}
+	return changes
+}
+
+func sortedUnion[T any](a, b map[string]T) []string {
+	keys := map[string]struct{}{}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// changedFields compares JSON representations of both objects; changes of scalar fields are described
+// with their values, changes of nested structures only by field name
+func changedFields(previous, current interface{}) []string {
+	toMap := func(v interface{}) map[string]interface{} {
+		m := map[string]interface{}{}
+		if raw, err := json.Marshal(v); err == nil {
+			_ = json.Unmarshal(raw, &m)
+		}
+		return m
+	}
+	p, c := toMap(previous), toMap(current)
+
+	var fields []string
+	for _, name := range sortedUnion(p, c) {
+		pv, cv := p[name], c[name]
+		if reflect.DeepEqual(pv, cv) {
+			continue
+		}
+		if isScalar(pv) && isScalar(cv) {
+			fields = append(fields, fmt.Sprintf("%s %v -> %v", name, pv, cv))
+		} else {
+			fields = append(fields, fmt.Sprintf("%s changed", name))
+		}
+	}
+	return fields
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, bool, float64:
+		return true
+	}
+	return false
+}
+
+/*****************************************************************************
+ * Function: predictedImpact
+ * Description: Describes the disruption caused by applying the configuration
+ ****************************************************************************/
+func predictedImpact(drainPolicy string, pfs []string) string {
+	drain := "no drain"
+	switch drainPolicy {
+	case drainPolicyFull:
+		drain = "node drain"
+	case drainPolicyAcceleratorConsumersOnly:
+		drain = "eviction of accelerator consumers"
+	}
+	if len(pfs) == 0 {
+		return drain
+	}
+	return fmt.Sprintf("%s, VF reset of %s", drain, strings.Join(pfs, ", "))
+}
+
+/*****************************************************************************
+ * Function: pendingApprovalCondition
+ * Description: Builds PendingApproval condition publishing the changes
+ * 		awaiting approval and their predicted impact
+ ****************************************************************************/
+func pendingApprovalCondition(generation int64, changes []string, impact string) metav1.Condition {
+	changesDescription := "none"
+	if len(changes) > 0 {
+		changesDescription = strings.Join(changes, "; ")
+	}
+	return metav1.Condition{
+		Type:   ConditionPendingApproval,
+		Status: metav1.ConditionTrue,
+		Reason: string(ApprovalRequired),
+		Message: fmt.Sprintf("generation %d awaits approval (annotate with %s=%d); changes: %s; impact: %s",
+			generation, utils.ApprovedGenerationAnnotation, generation, changesDescription, impact),
+		ObservedGeneration: generation,
+	}
+}
+
+// pfsToReset returns sorted PCI addresses of PFs which are changed by the configuration or whose VFs are not in requested state
+func pfsToReset[T any](applied, requested map[string]T, outdated []string) []string {
+	pfs := map[string]struct{}{}
+	for _, pci := range sortedUnion(applied, requested) {
+		if !reflect.DeepEqual(applied[pci], requested[pci]) {
+			pfs[pci] = struct{}{}
+		}
+	}
+	for _, pci := range outdated {
+		pfs[pci] = struct{}{}
+	}
+	return sortedUnion(pfs, nil)
+}
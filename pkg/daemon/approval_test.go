@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("approval", func() {
+	pf := func(pci string, vfs int) fec.PhysicalFunctionConfigExt {
+		return fec.PhysicalFunctionConfigExt{PCIAddress: pci, PFDriver: utils.PciPfStubDash, VFDriver: utils.VfioPci, VFAmount: vfs}
+	}
+
+	Context("describePfChanges", func() {
+		It("should describe added, removed and changed PFs", func() {
+			applied := map[string]fec.PhysicalFunctionConfigExt{"0000:aa:00.0": pf("0000:aa:00.0", 2), "0000:bb:00.0": pf("0000:bb:00.0", 2)}
+			requested := map[string]fec.PhysicalFunctionConfigExt{"0000:aa:00.0": pf("0000:aa:00.0", 4), "0000:cc:00.0": pf("0000:cc:00.0", 1)}
+
+			Expect(describePfChanges(applied, requested)).To(Equal([]string{
+				"0000:aa:00.0: vfAmount 2 -> 4",
+				"0000:bb:00.0: removed",
+				"0000:cc:00.0: added",
+			}))
+			Expect(pfsToReset(applied, requested, nil)).To(Equal([]string{"0000:aa:00.0", "0000:bb:00.0", "0000:cc:00.0"}))
+		})
+
+		It("should report nothing for unchanged PFs", func() {
+			applied := map[string]fec.PhysicalFunctionConfigExt{"0000:aa:00.0": pf("0000:aa:00.0", 2)}
+			Expect(describePfChanges(applied, applied)).To(BeEmpty())
+			Expect(pfsToReset(applied, applied, []string{"0000:aa:00.0"})).To(Equal([]string{"0000:aa:00.0"}))
+		})
+	})
+
+	It("predictedImpact should describe drain and VF reset", func() {
+		Expect(predictedImpact(drainPolicyFull, []string{"0000:aa:00.0"})).To(Equal("node drain, VF reset of 0000:aa:00.0"))
+		Expect(predictedImpact("Never", nil)).To(Equal("no drain"))
+	})
+
+	Context("FecNodeConfigReconciler::isReconfigurationApproved", func() {
+		var (
+			nodeConfig *fec.SriovFecNodeConfig
+			fakeClient client.Client
+			reconciler FecNodeConfigReconciler
+		)
+
+		BeforeEach(func() {
+			nodeConfig = &fec.SriovFecNodeConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default", Generation: 3},
+				Spec: fec.SriovFecNodeConfigSpec{
+					PhysicalFunctions: []fec.PhysicalFunctionConfigExt{pf("0000:aa:00.0", 2)},
+					ApprovalPolicy:    fec.ApprovalPolicyManual,
+				},
+			}
+			scheme := runtime.NewScheme()
+			Expect(fec.AddToScheme(scheme)).To(Succeed())
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodeConfig).Build()
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(nodeConfig), nodeConfig)).To(Succeed())
+			reconciler = FecNodeConfigReconciler{Client: fakeClient, log: utils.NewLogger()}
+		})
+
+		It("should publish PendingApproval condition when generation is not approved", func() {
+			approved, err := reconciler.isReconfigurationApproved(nodeConfig, &fec.NodeInventory{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approved).To(BeFalse())
+
+			res := new(fec.SriovFecNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(nodeConfig), res)).To(Succeed())
+			condition := res.FindCondition(ConditionPendingApproval)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("0000:aa:00.0: added"))
+			Expect(condition.Message).To(ContainSubstring("VF reset of 0000:aa:00.0"))
+		})
+
+		It("should describe changes against physical functions applied before the restart of the daemon", func() {
+			nodeConfig.Status.AppliedPhysicalFunctions = []fec.PhysicalFunctionConfigExt{pf("0000:aa:00.0", 1)}
+			inventory := &fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{
+				{PCIAddress: "0000:aa:00.0", VFs: []fec.VF{{PCIAddress: "0000:ab:00.0"}}},
+			}}
+
+			approved, err := reconciler.isReconfigurationApproved(nodeConfig, inventory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approved).To(BeFalse())
+			condition := nodeConfig.FindCondition(ConditionPendingApproval)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).To(ContainSubstring("0000:aa:00.0: vfAmount 1 -> 2"))
+			Expect(condition.Message).ToNot(ContainSubstring("added"))
+		})
+
+		It("should approve when annotation carries current generation", func() {
+			nodeConfig.Annotations = map[string]string{utils.ApprovedGenerationAnnotation: "3"}
+			nodeConfig.Status.Conditions = []metav1.Condition{pendingApprovalCondition(3, nil, "no drain")}
+
+			approved, err := reconciler.isReconfigurationApproved(nodeConfig, &fec.NodeInventory{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approved).To(BeTrue())
+			Expect(nodeConfig.FindCondition(ConditionPendingApproval)).To(BeNil())
+		})
+
+		It("should not approve when annotation carries outdated generation", func() {
+			nodeConfig.Annotations = map[string]string{utils.ApprovedGenerationAnnotation: "2"}
+
+			approved, err := reconciler.isReconfigurationApproved(nodeConfig, &fec.NodeInventory{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approved).To(BeFalse())
+		})
+
+		It("should approve automatically when approval is not required", func() {
+			nodeConfig.Spec.ApprovalPolicy = fec.ApprovalPolicyAutomatic
+
+			approved, err := reconciler.isReconfigurationApproved(nodeConfig, &fec.NodeInventory{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approved).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CDISpecDir is the directory of generated Container Device Interface specs read by container runtimes
+	CDISpecDir = "/var/run/cdi"
+	cdiVersion = "0.5.0"
+	// cdiVendor is the vendor of CDI device kinds, VFs are referred to as e.g. intel.com/fec=0000:b2:00.0
+	cdiVendor = "intel.com"
+)
+
+// cdiSpec is a subset of Container Device Interface spec used to describe VFs
+type cdiSpec struct {
+	Version        string            `json:"cdiVersion"`
+	Kind           string            `json:"kind"`
+	Devices        []cdiDevice       `json:"devices"`
+	ContainerEdits cdiContainerEdits `json:"containerEdits,omitempty"`
+}
+
+type cdiDevice struct {
+	Name           string            `json:"name"`
+	ContainerEdits cdiContainerEdits `json:"containerEdits"`
+}
+
+type cdiContainerEdits struct {
+	Env         []string        `json:"env,omitempty"`
+	DeviceNodes []cdiDeviceNode `json:"deviceNodes,omitempty"`
+}
+
+type cdiDeviceNode struct {
+	Path        string `json:"path"`
+	Permissions string `json:"permissions,omitempty"`
+}
+
+// cdiVF describes a VF bound to vfio-pci
+type cdiVF struct {
+	pciAddress string
+	iommuGroup string
+}
+
+// CDISpecWriter maintains Container Device Interface specs of VFs bound to vfio-pci, one spec per accelerator family
+type CDISpecWriter struct {
+	log       *logrus.Logger
+	dir       string
+	vfioToken string
+
+	mu      sync.Mutex
+	written map[string][]byte
+}
+
+func NewCDISpecWriter(log *logrus.Logger, dir, vfioToken string) *CDISpecWriter {
+	return &CDISpecWriter{log: log, dir: dir, vfioToken: vfioToken, written: map[string][]byte{}}
+}
+
+/*****************************************************************************
+ * Method: CDISpecWriter::Write
+ * Description: Writes the CDI spec of VFs of the accelerator family when it
+ * 		changed; the spec is removed when the family has no VFs. Nil
+ * 		writer does nothing
+ ****************************************************************************/
+func (w *CDISpecWriter) Write(family string, vfs []cdiVF) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	path := filepath.Join(w.dir, cdiVendor+"-"+family+".json")
+	if len(vfs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove CDI spec: %v", err)
+		}
+		if _, ok := w.written[family]; ok {
+			w.log.WithField("path", path).Info("CDI spec removed")
+		}
+		delete(w.written, family)
+		return nil
+	}
+
+	content, err := json.MarshalIndent(w.spec(family, vfs), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to build CDI spec: %v", err)
+	}
+	if previous, ok := w.written[family]; ok && bytes.Equal(previous, content) {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+
+	// write to a temporary file first so that runtimes never read partial content
+	tmp := filepath.Join(w.dir, "."+cdiVendor+"-"+family+".json.tmp")
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write CDI spec: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write CDI spec: %v", err)
+	}
+	w.log.WithField("path", path).WithField("vfs", len(vfs)).Info("CDI spec written")
+	w.written[family] = content
+	return nil
+}
+
+// spec describes every VF as a device named after its PCI address; devices share /dev/vfio/vfio and the VFIO token
+func (w *CDISpecWriter) spec(family string, vfs []cdiVF) cdiSpec {
+	kind := cdiVendor + "/" + family
+	spec := cdiSpec{
+		Version: cdiVersion,
+		Kind:    kind,
+		Devices: []cdiDevice{},
+		ContainerEdits: cdiContainerEdits{
+			DeviceNodes: []cdiDeviceNode{{Path: vfioContainerDevice, Permissions: "rw"}},
+		},
+	}
+	if w.vfioToken != "" {
+		spec.ContainerEdits.Env = []string{"VFIO_TOKEN=" + w.vfioToken}
+	}
+
+	envPrefix := "PCIDEVICE_" + invalidEnvChars.ReplaceAllString(strings.ToUpper(kind), "_") + "_"
+	for _, vf := range vfs {
+		spec.Devices = append(spec.Devices, cdiDevice{
+			Name: vf.pciAddress,
+			ContainerEdits: cdiContainerEdits{
+				Env:         []string{envPrefix + invalidEnvChars.ReplaceAllString(strings.ToUpper(vf.pciAddress), "_") + "=" + vf.pciAddress},
+				DeviceNodes: []cdiDeviceNode{{Path: "/dev/vfio/" + vf.iommuGroup, Permissions: "rw"}},
+			},
+		})
+	}
+	return spec
+}
+
+// fecCDIVFs returns VFs of the inventory which can be injected through VFIO
+func fecCDIVFs(inventory *fec.NodeInventory) []cdiVF {
+	var vfs []cdiVF
+	for _, acc := range inventory.SriovAccelerators {
+		for _, vf := range acc.VFs {
+			if strings.EqualFold(vf.Driver, utils.VfioPci) && vf.IOMMUGroup != "" {
+				vfs = append(vfs, cdiVF{pciAddress: vf.PCIAddress, iommuGroup: vf.IOMMUGroup})
+			}
+		}
+	}
+	return vfs
+}
+
+// vrbCDIVFs returns VFs of the inventory which can be injected through VFIO
+func vrbCDIVFs(inventory *vrbv1.NodeInventory) []cdiVF {
+	var vfs []cdiVF
+	for _, acc := range inventory.SriovAccelerators {
+		for _, vf := range acc.VFs {
+			if strings.EqualFold(vf.Driver, utils.VfioPci) && vf.IOMMUGroup != "" {
+				vfs = append(vfs, cdiVF{pciAddress: vf.PCIAddress, iommuGroup: vf.IOMMUGroup})
+			}
+		}
+	}
+	return vfs
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CDI specs", func() {
+	var (
+		dir    string
+		writer *CDISpecWriter
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "cdi")
+		Expect(err).ToNot(HaveOccurred())
+		writer = NewCDISpecWriter(utils.NewLogger(), dir, "02bddbbf-bbb0-4d79-886b-91bad3fbb510")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should describe VFs bound to vfio-pci", func() {
+		Expect(fecCDIVFs(&fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{{VFs: []fec.VF{
+			{PCIAddress: "0000:b2:00.0", Driver: utils.VfioPci, IOMMUGroup: "42"},
+			{PCIAddress: "0000:b2:00.1", Driver: utils.IgbUio},
+			{PCIAddress: "0000:b2:00.2", Driver: utils.VfioPci},
+		}}}})).To(Equal([]cdiVF{{pciAddress: "0000:b2:00.0", iommuGroup: "42"}}))
+	})
+
+	It("should write the spec of the family with VFIO devices, PCI addresses and VFIO token", func() {
+		Expect(writer.Write("fec", []cdiVF{{pciAddress: "0000:b2:00.0", iommuGroup: "42"}, {pciAddress: "0000:b2:00.1", iommuGroup: "43"}})).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(dir, "intel.com-fec.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(MatchJSON(`{
+			"cdiVersion": "0.5.0",
+			"kind": "intel.com/fec",
+			"devices": [
+				{"name": "0000:b2:00.0", "containerEdits": {
+					"env": ["PCIDEVICE_INTEL_COM_FEC_0000_B2_00_0=0000:b2:00.0"],
+					"deviceNodes": [{"path": "/dev/vfio/42", "permissions": "rw"}]}},
+				{"name": "0000:b2:00.1", "containerEdits": {
+					"env": ["PCIDEVICE_INTEL_COM_FEC_0000_B2_00_1=0000:b2:00.1"],
+					"deviceNodes": [{"path": "/dev/vfio/43", "permissions": "rw"}]}}
+			],
+			"containerEdits": {
+				"env": ["VFIO_TOKEN=02bddbbf-bbb0-4d79-886b-91bad3fbb510"],
+				"deviceNodes": [{"path": "/dev/vfio/vfio", "permissions": "rw"}]
+			}
+		}`))
+		Expect(filepath.Glob(filepath.Join(dir, ".*"))).To(BeEmpty())
+	})
+
+	It("should follow changes of VFs and remove the spec when there are none", func() {
+		path := filepath.Join(dir, "intel.com-vrb.json")
+		Expect(writer.Write("vrb", []cdiVF{{pciAddress: "0000:f8:00.0", iommuGroup: "7"}})).To(Succeed())
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(writer.Write("vrb", []cdiVF{{pciAddress: "0000:f8:00.0", iommuGroup: "7"}})).To(Succeed())
+		Expect(os.Stat(path)).To(Equal(info))
+
+		Expect(os.Remove(path)).To(Succeed())
+		Expect(writer.Write("vrb", []cdiVF{{pciAddress: "0000:f8:00.0", iommuGroup: "7"}})).To(Succeed())
+		Expect(path).To(BeAnExistingFile())
+
+		Expect(writer.Write("vrb", []cdiVF{{pciAddress: "0000:f8:00.1", iommuGroup: "8"}})).To(Succeed())
+		Expect(os.ReadFile(path)).To(ContainSubstring("/dev/vfio/8"))
+
+		Expect(writer.Write("vrb", nil)).To(Succeed())
+		Expect(path).ToNot(BeAnExistingFile())
+	})
+
+	It("should do nothing when disabled", func() {
+		var disabled *CDISpecWriter
+		Expect(disabled.Write("fec", []cdiVF{{pciAddress: "0000:b2:00.0", iommuGroup: "42"}})).To(Succeed())
+	})
+})
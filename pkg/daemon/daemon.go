@@ -13,10 +13,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -40,7 +42,7 @@ var (
 	kernelParams        = []string{"intel_iommu=on", "iommu=pt"}
 )
 
-type DrainAndExecute func(configurer func(ctx context.Context) bool, drain bool) error
+type DrainAndExecute func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error
 
 type RestartDevicePluginFunction func() error
 
@@ -84,6 +86,8 @@ func CreateManager(config *rest.Config, scheme *runtime.Scheme, namespace string
 		LeaderElection:         false,
 		Namespace:              namespace,
 		HealthProbeBindAddress: ":" + strconv.Itoa(healthProbePort),
+		// Node is read only to maintain the reconfiguration taint; caching all Nodes is not worth it
+		ClientDisableCacheFor: []client.Object{&corev1.Node{}},
 	})
 	if err != nil {
 		return nil, err
@@ -41,6 +41,9 @@ type FecNodeConfigReconciler struct {
 	drainerAndExecute   DrainAndExecute
 	sriovfecconfigurer  Configurer
 	restartDevicePlugin RestartDevicePluginFunction
+	nodeFeatures        *NodeFeatureWriter
+	devicePlugin        *DevicePlugin
+	cdiSpecs            *CDISpecWriter
 }
 
 type Configurer interface {
@@ -52,10 +55,15 @@ type Configurer interface {
  * Description:
  *
  ****************************************************************************/
-func (r *FecNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *FecNodeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.log.Debugf("Reconcile(...) triggered by %s", req.NamespacedName.String())
 
-	r.setLogLevel()
+	if tornDown, err := isTornDown(ctx, r, r.nodeNameRef); err != nil {
+		return requeueNowWithError(err)
+	} else if tornDown {
+		r.log.Info("accelerators of the node are torn down - skipping configuration")
+		return requeueLater()
+	}
 
 	sfnc, err := r.readNodeConfig(req.NamespacedName)
 	if err != nil {
@@ -85,8 +93,23 @@ func (r *FecNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request)
 		fecDeviceUpdateRequired[accelerator.PCIAddress] = true
 	}
 
+	r.writeNodeFeatures(sfnc, detectedInventory)
+	r.devicePlugin.UpdateFecDevices(detectedInventory)
+	r.writeCDISpec(detectedInventory)
+
 	if !r.isCardUpdateRequired(sfnc, detectedInventory) {
+		if sfnc.GetGeneration() != findOrCreateConfigurationStatusCondition(sfnc).ObservedGeneration {
+			r.log.WithField("generation", sfnc.GetGeneration()).Info("physical functions are applied already, acknowledging changed settings of reconfiguration")
+			meta.RemoveStatusCondition(&sfnc.Status.Conditions, ConditionPendingApproval)
+			return requeueLaterOrNowIfError(r.updateStatus(sfnc, metav1.ConditionTrue, ConfigurationSucceeded, "Configured successfully"))
+		}
 		r.log.Debug("SriovFec: Nothing to do")
+		return requeueLaterOrNowIfError(r.refreshInventory(sfnc, detectedInventory))
+	}
+
+	if approved, err := r.isReconfigurationApproved(sfnc, detectedInventory); err != nil {
+		return requeueNowWithError(err)
+	} else if !approved {
 		return requeueLater()
 	}
 
@@ -129,6 +152,51 @@ func (r *FecNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request)
 	return requeueLaterOrNowIfError(r.updateStatus(sfnc, metav1.ConditionTrue, ConfigurationSucceeded, "Configured successfully"))
 }
 
+// Logger returns the logger of the reconciler
+func (r *FecNodeConfigReconciler) Logger() *logrus.Logger {
+	return r.log
+}
+
+// SetNodeFeatureWriter makes the reconciler publish its accelerators as Node Feature Discovery features
+func (r *FecNodeConfigReconciler) SetNodeFeatureWriter(w *NodeFeatureWriter) {
+	r.nodeFeatures = w
+}
+
+// SetDevicePlugin makes the reconciler serve VFs of its inventory with the built-in device plugin
+func (r *FecNodeConfigReconciler) SetDevicePlugin(p *DevicePlugin) {
+	r.devicePlugin = p
+}
+
+// SetCDISpecWriter makes the reconciler maintain Container Device Interface specs of VFs of its inventory
+func (r *FecNodeConfigReconciler) SetCDISpecWriter(w *CDISpecWriter) {
+	r.cdiSpecs = w
+}
+
+// refreshInventory keeps the inventory in the status up to date, e.g. with VF consumers, between reconfigurations
+func (r *FecNodeConfigReconciler) refreshInventory(nc *fec.SriovFecNodeConfig, inventory *fec.NodeInventory) error {
+	if equality.Semantic.DeepEqual(nc.Status.Inventory, *inventory) {
+		return nil
+	}
+	nc.Status.Inventory = *inventory
+	if err := r.Status().Update(context.Background(), nc); err != nil {
+		return err
+	}
+	r.log.Debug("inventory in the status refreshed")
+	return nil
+}
+
+func (r *FecNodeConfigReconciler) writeCDISpec(inventory *fec.NodeInventory) {
+	if err := r.cdiSpecs.Write("fec", fecCDIVFs(inventory)); err != nil {
+		r.log.WithError(err).Error("failed to write CDI spec")
+	}
+}
+
+func (r *FecNodeConfigReconciler) writeNodeFeatures(nc *fec.SriovFecNodeConfig, inventory *fec.NodeInventory) {
+	if err := r.nodeFeatures.Write("fec", fecNodeFeatures(nc, inventory)); err != nil {
+		r.log.WithError(err).Error("failed to publish node features")
+	}
+}
+
 /*****************************************************************************
  * Method: FecNodeConfigReconciler::
  * Description:
@@ -206,7 +274,8 @@ func (r *FecNodeConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					requiredName: r.nodeNameRef.Name,
 					log:          r.log,
 				},
-				predicate.GenerationChangedPredicate{},
+				// approval annotation does not change the generation
+				predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
 			),
 		).Complete(r)
 }
@@ -238,6 +307,7 @@ func (r *FecNodeConfigReconciler) updateStatus(nc *fec.SriovFecNodeConfig, statu
 		for _, pf := range nc.Spec.PhysicalFunctions {
 			fecPreviousConfig[pf.PCIAddress] = pf
 		}
+		nc.Status.AppliedPhysicalFunctions = nc.DeepCopy().Spec.PhysicalFunctions
 	}
 
 	// SriovFecNodeConfig.generation is under K8S management
@@ -272,6 +342,9 @@ func (r *FecNodeConfigReconciler) updateStatus(nc *fec.SriovFecNodeConfig, statu
 	if err := r.Status().Update(context.Background(), nc); err != nil {
 		return err
 	}
+	r.writeNodeFeatures(nc, &nc.Status.Inventory)
+	r.devicePlugin.UpdateFecDevices(&nc.Status.Inventory)
+	r.writeCDISpec(&nc.Status.Inventory)
 
 	r.log.WithField("previous", previousCondition).
 		WithField("current", condition).
@@ -334,19 +407,75 @@ func (r *FecNodeConfigReconciler) readNodeConfig(nn types.NamespacedName) (nc *f
  ****************************************************************************/
 func (r *FecNodeConfigReconciler) configureNode(nodeConfig *fec.SriovFecNodeConfig) error {
 	var configurationError error
+	var hookResults []fec.HookResult
+	hooks := hookRunner{client: r.Client, log: r.log, nodeNameRef: r.nodeNameRef}
+	pfs := pfsRequiringUpdate(fecDeviceUpdateRequired)
+
+	runHook := func(ctx context.Context, phase hookPhase) error {
+		hook := fecReconfigurationHook(nodeConfig.Spec.Hooks, phase)
+		if hook == nil {
+			return nil
+		}
+		outcome, err := hooks.run(ctx, phase, hook, pfs)
+		hookResults = append(hookResults, outcome.fecHookResult())
+		return err
+	}
+
+	marker := nodeReconfigurationMarker{client: r.Client, log: r.log, nodeName: r.nodeNameRef.Name}
+
+	markerSet := false
+	removeMarker := func() {
+		if !markerSet {
+			return
+		}
+		markerSet = false
+		if err := marker.end(context.TODO()); err != nil {
+			r.log.WithError(err).Error("failed to remove reconfiguration taint from the node")
+		}
+	}
 
 	drainFunc := func(ctx context.Context) bool {
+		marker.progress(ctx, reconfigurationApplyingConfiguration)
 		if err := r.sriovfecconfigurer.ApplySpec(nodeConfig.Spec, fecDeviceUpdateRequired); err != nil {
 			r.log.WithError(err).Error("failed applying new PF/VF configuration")
 			configurationError = err
 			return true
 		}
 
-		configurationError = r.restartDevicePlugin()
+		marker.progress(ctx, reconfigurationRestartingDevicePlugin)
+		if configurationError = r.restartDevicePlugin(); configurationError != nil {
+			return true
+		}
+		removeMarker()
+
+		configurationError = runHook(ctx, postReconfigurationHook)
 		return true
 	}
 
-	if err := r.drainerAndExecute(drainFunc, !nodeConfig.Spec.DrainSkip); err != nil {
+	drainOptions, err := drainOptionsFor(r.Client, r.log, r.nodeNameRef, string(nodeConfig.Spec.EffectiveDrainPolicy()),
+		fecDrainSettings(nodeConfig.Spec.DrainSettings), fecDeviceUpdateRequired)
+	if err != nil {
+		r.log.WithError(err).Error("failed to resolve drain options")
+		return err
+	}
+
+	// taint keeps new pods away from the node from the drain on, the node is not cordoned under AcceleratorConsumersOnly
+	// policy; it is best effort protection, stale taints are removed at daemon startup. The node is tainted by the
+	// leader only, so that nodes waiting for the reconfiguration stay schedulable.
+	// Consumers of accelerators quiesce their queues right before they are evicted by the drain of their node
+	drainOptions.BeforeDrain = func(ctx context.Context) error {
+		markerSet = true
+		if err := marker.begin(ctx); err != nil {
+			r.log.WithError(err).Error("failed to taint the node for the time of reconfiguration")
+		}
+		return runHook(ctx, preReconfigurationHook)
+	}
+	defer removeMarker()
+	// results are exposed in the status by the subsequent updateStatus call
+	defer func() { nodeConfig.Status.HookResults = hookResults }()
+
+	err = r.drainerAndExecute(drainFunc, drainOptions)
+	if err != nil {
 		return err
 	}
 
@@ -385,7 +514,7 @@ func (r *FecNodeConfigReconciler) isCardUpdateRequired(nc *fec.SriovFecNodeConfi
 
 	isGenerationChanged := func() bool {
 		observedGeneration := findOrCreateConfigurationStatusCondition(nc).ObservedGeneration
-		if nc.GetGeneration() != observedGeneration {
+		if nc.GetGeneration() != observedGeneration && !arePhysicalFunctionsApplied(nc) {
 			r.log.WithField("observed", observedGeneration).
 				WithField("requested", nc.GetGeneration()).
 				Info("Observed generation doesn't reflect requested one")
@@ -418,6 +547,51 @@ func (r *FecNodeConfigReconciler) isCardUpdateRequired(nc *fec.SriovFecNodeConfi
 	return isGenerationChanged() || exposedInventoryOutdated() || r.bbDevConfigDaemonIsDead(nc)
 }
 
+/*****************************************************************************
+ * Method: FecNodeConfigReconciler::isReconfigurationApproved
+ * Description: Returns true if the node config does not require approval or
+ * 		its generation has been approved. Otherwise publishes PendingApproval
+ * 		condition describing the pending changes and their predicted impact.
+ ****************************************************************************/
+func (r *FecNodeConfigReconciler) isReconfigurationApproved(nc *fec.SriovFecNodeConfig, detectedInventory *fec.NodeInventory) (bool, error) {
+	if !nc.Spec.RequiresApproval() || isGenerationApproved(nc) {
+		// persisted by the subsequent updateStatus call
+		meta.RemoveStatusCondition(&nc.Status.Conditions, ConditionPendingApproval)
+		return true, nil
+	}
+
+	requested := map[string]fec.PhysicalFunctionConfigExt{}
+	for _, pf := range nc.Spec.PhysicalFunctions {
+		requested[pf.PCIAddress] = pf
+	}
+	// the status survives restarts of the daemon unlike the configuration kept in memory
+	applied := map[string]fec.PhysicalFunctionConfigExt{}
+	for _, pf := range nc.Status.AppliedPhysicalFunctions {
+		applied[pf.PCIAddress] = pf
+	}
+	var outdated []string
+	for _, acc := range detectedInventory.SriovAccelerators {
+		if pf, ok := requested[acc.PCIAddress]; ok && len(acc.VFs) != pf.VFAmount {
+			outdated = append(outdated, acc.PCIAddress)
+		}
+	}
+
+	condition := pendingApprovalCondition(nc.GetGeneration(), describePfChanges(applied, requested),
+		predictedImpact(string(nc.Spec.EffectiveDrainPolicy()), pfsToReset(applied, requested, outdated)))
+	if previous := nc.FindCondition(ConditionPendingApproval); previous != nil && previous.Message == condition.Message {
+		r.log.WithField("generation", nc.GetGeneration()).Debug("reconfiguration still awaits approval")
+		return false, nil
+	}
+
+	meta.SetStatusCondition(&nc.Status.Conditions, condition)
+	if err := r.Status().Update(context.Background(), nc); err != nil {
+		return false, err
+	}
+	r.log.WithField("generation", nc.GetGeneration()).WithField("details", condition.Message).
+		Info("reconfiguration awaits approval")
+	return false, nil
+}
+
 /*****************************************************************************
  * Function: FecNewNodeConfigReconciler
  * Description:
@@ -441,6 +615,19 @@ func FecNewNodeConfigReconciler(k8sClient client.Client, drainer DrainAndExecute
 	}, nil
 }
 
+/*****************************************************************************
+ * Function: arePhysicalFunctionsApplied
+ * Description: Returns true if the requested physical functions were applied
+ * 		successfully already, i.e. the generation changed only by
+ * 		settings of the reconfiguration like drainSettings, hooks or
+ * 		approvalPolicy which do not require reconfiguration on their own
+ ****************************************************************************/
+func arePhysicalFunctionsApplied(nc *fec.SriovFecNodeConfig) bool {
+	condition := nc.FindCondition(ConditionConfigured)
+	return condition != nil && condition.Reason == string(ConfigurationSucceeded) &&
+		equality.Semantic.DeepEqual(nc.Status.AppliedPhysicalFunctions, nc.Spec.PhysicalFunctions)
+}
+
 /******************************************************************************
  * Function: findOrCreateConfigurationStatusCondition
  * Description:
@@ -549,7 +736,13 @@ func (r *FecNodeConfigReconciler) getPfBbConfVersion(shouldLog bool) string {
 func (r *FecNodeConfigReconciler) checkIfDeviceUpdateNeeded(previousConf, currentConf map[string]fec.PhysicalFunctionConfigExt) {
 	// Check for updates in previous configuration
 	for k, prevConfig := range previousConf {
-		if currConfig, exists := currentConf[k]; !exists || !equality.Semantic.DeepEqual(prevConfig, currConfig) {
+		currConfig, exists := currentConf[k]
+		// resource name is served by sriovdp-config generated by the operator, it does not require reconfiguration of the PF
+		prevConfig.ResourceName, currConfig.ResourceName = "", ""
+		// VF groups are served the same way, only drivers of their VFs matter
+		prevDrivers, currDrivers := prevConfig.VFDrivers(), currConfig.VFDrivers()
+		prevConfig.VFGroups, currConfig.VFGroups = nil, nil
+		if !exists || !equality.Semantic.DeepEqual(prevConfig, currConfig) || !equality.Semantic.DeepEqual(prevDrivers, currDrivers) {
 			fecDeviceUpdateRequired[k] = true
 		} else {
 			fecDeviceUpdateRequired[k] = false
@@ -582,55 +775,3 @@ func (r *FecNodeConfigReconciler) updatePfBbConfVersionIfChanged(nodeConfig *fec
 	}
 	return nil
 }
-
-/*****************************************************************************
- * Method: FecNodeConfigReconciler::setLogLevel
- * Description: Sets the log level based on the contents of the log level file.
- * If the file is empty or does not exist, it does nothing.
- * If the log level is changed, it logs a message indicating the change.
- ****************************************************************************/
-func (r *FecNodeConfigReconciler) setLogLevel() {
-	logLevelFilePath := "/var/log/level.dat"
-	logLevelContent, err := os.ReadFile(logLevelFilePath)
-	if err != nil {
-		return
-	}
-
-	// If the file is empty, do nothing
-	if len(logLevelContent) == 0 {
-		r.log.Debugf("Log level file %s is empty", logLevelFilePath)
-		return
-	}
-
-	logLevelContent = bytes.TrimSpace(logLevelContent)
-	logLevelContent = bytes.ToLower(logLevelContent)
-	logLevelString := string(logLevelContent)
-
-	// Get the current log level
-	currentLogLevel := r.log.GetLevel()
-
-	switch logLevelString {
-	case "warn":
-		if currentLogLevel != logrus.WarnLevel {
-			r.log.SetLevel(logrus.WarnLevel)
-			r.log.Warn("Log level changed to Warn")
-		}
-	case "error":
-		if currentLogLevel != logrus.ErrorLevel {
-			r.log.SetLevel(logrus.ErrorLevel)
-			r.log.Error("Log level changed to Error")
-		}
-	case "info":
-		if currentLogLevel != logrus.InfoLevel {
-			r.log.SetLevel(logrus.InfoLevel)
-			r.log.Info("Log level changed to Info")
-		}
-	case "debug":
-		if currentLogLevel != logrus.DebugLevel {
-			r.log.SetLevel(logrus.DebugLevel)
-			r.log.Debug("Log level changed to Debug")
-		}
-	default:
-		r.log.Debugf("Unknown log level: %s", logLevelString)
-	}
-}
@@ -6,15 +6,17 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
 	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
 	"github.com/intel/sriov-fec-operator/pkg/common/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
-	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -27,6 +29,7 @@ var _ = Describe("FecNodeConfigReconciler.Reconcile", func() {
 
 	BeforeEach(func() {
 		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
 		Expect(sriovv2.AddToScheme(scheme)).ToNot(HaveOccurred())
 		Expect(vrbv1.AddToScheme(scheme)).ToNot(HaveOccurred())
 	})
@@ -96,7 +99,12 @@ var _ = Describe("FecNodeConfigReconciler.Reconcile", func() {
 				log:                utils.NewLogger(),
 				nodeNameRef:        nodeNameRef,
 				sriovfecconfigurer: configurer,
-				drainerAndExecute: func(configurer func(ctx context.Context) bool, drain bool) error {
+				drainerAndExecute: func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
+					if opts.BeforeDrain != nil {
+						if err := opts.BeforeDrain(context.TODO()); err != nil {
+							return err
+						}
+					}
 					_ = configurer(context.TODO())
 					return nil
 				}, restartDevicePlugin: func() error {
@@ -269,6 +277,49 @@ var _ = Describe("FecNodeConfigReconciler.Reconcile", func() {
 			Expect(fakeClient.Get(context.TODO(), nodeNameRef, sfnc)).ToNot(HaveOccurred())
 			Expect(sfnc.Status.Inventory).ToNot(Equal(nodeInventory))
 		})
+
+		It("acknowledges changed settings of reconfiguration without reconfiguring physical functions", func() {
+			sysLockdownFilePath = filepath.Join(testTmpFolder, "lockdown")
+			Expect(os.WriteFile(sysLockdownFilePath, []byte("[none] integrity confidentiality"), 0644)).To(Succeed())
+			defer os.Remove(sysLockdownFilePath)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+			sfnc := new(sriovv2.SriovFecNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, sfnc)).ToNot(HaveOccurred())
+			sfnc.Generation++
+			sfnc.Spec.PhysicalFunctions = []sriovv2.PhysicalFunctionConfigExt{
+				{PCIAddress: pciAddress, PFDriver: utils.IgbUio, VFDriver: utils.IgbUio, VFAmount: 1},
+			}
+			Expect(fakeClient.Patch(context.TODO(), sfnc, client.Merge)).To(Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+
+			drains := 0
+			drainerAndExecute := reconciler.drainerAndExecute
+			reconciler.drainerAndExecute = func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
+				drains++
+				return drainerAndExecute(configurer, opts)
+			}
+			sfnc = new(sriovv2.SriovFecNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, sfnc)).ToNot(HaveOccurred())
+			Expect(sfnc.Status.AppliedPhysicalFunctions).To(Equal(sfnc.Spec.PhysicalFunctions))
+			sfnc.Generation++
+			timeout := int64(60)
+			sfnc.Spec.DrainSettings = &sriovv2.DrainSettings{TimeoutSeconds: &timeout}
+			sfnc.Spec.ApprovalPolicy = sriovv2.ApprovalPolicyManual
+			Expect(fakeClient.Patch(context.TODO(), sfnc, client.Merge)).To(Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drains).To(BeZero())
+			sfnc = new(sriovv2.SriovFecNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, sfnc)).ToNot(HaveOccurred())
+			condition := sfnc.FindCondition(ConditionConfigured)
+			Expect(condition.Reason).To(Equal(string(ConfigurationSucceeded)))
+			Expect(condition.ObservedGeneration).To(Equal(sfnc.Generation))
+			Expect(sfnc.FindCondition(ConditionPendingApproval)).To(BeNil())
+		})
 	})
 })
 
@@ -344,6 +395,8 @@ var _ = Describe("VrbNodeConfigReconciler.Reconcile", func() {
 
 	BeforeEach(func() {
 		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+		Expect(sriovv2.AddToScheme(scheme)).ToNot(HaveOccurred())
 		Expect(vrbv1.AddToScheme(scheme)).ToNot(HaveOccurred())
 	})
 
@@ -412,7 +465,12 @@ var _ = Describe("VrbNodeConfigReconciler.Reconcile", func() {
 				log:           utils.NewLogger(),
 				nodeNameRef:   nodeNameRef,
 				vrbconfigurer: configurer,
-				drainerAndExecute: func(configurer func(ctx context.Context) bool, drain bool) error {
+				drainerAndExecute: func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
+					if opts.BeforeDrain != nil {
+						if err := opts.BeforeDrain(context.TODO()); err != nil {
+							return err
+						}
+					}
 					_ = configurer(context.TODO())
 					return nil
 				}, restartDevicePlugin: func() error {
@@ -486,6 +544,49 @@ var _ = Describe("VrbNodeConfigReconciler.Reconcile", func() {
 			Expect(svnc.Status.Inventory).ToNot(Equal(nodeInventory))
 		})
 
+		It("acknowledges changed settings of reconfiguration without reconfiguring physical functions", func() {
+			sysLockdownFilePath = filepath.Join(testTmpFolder, "lockdown")
+			Expect(os.WriteFile(sysLockdownFilePath, []byte("[none] integrity confidentiality"), 0644)).To(Succeed())
+			defer os.Remove(sysLockdownFilePath)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+			svnc := new(vrbv1.SriovVrbNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, svnc)).ToNot(HaveOccurred())
+			svnc.Generation++
+			svnc.Spec.PhysicalFunctions = []vrbv1.PhysicalFunctionConfigExt{
+				{PCIAddress: pciAddress, PFDriver: utils.IgbUio, VFDriver: utils.IgbUio, VFAmount: 1},
+			}
+			Expect(fakeClient.Patch(context.TODO(), svnc, client.Merge)).To(Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+
+			drains := 0
+			drainerAndExecute := reconciler.drainerAndExecute
+			reconciler.drainerAndExecute = func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
+				drains++
+				return drainerAndExecute(configurer, opts)
+			}
+			svnc = new(vrbv1.SriovVrbNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, svnc)).ToNot(HaveOccurred())
+			Expect(svnc.Status.AppliedPhysicalFunctions).To(Equal(svnc.Spec.PhysicalFunctions))
+			svnc.Generation++
+			timeout := int64(60)
+			svnc.Spec.DrainSettings = &vrbv1.DrainSettings{TimeoutSeconds: &timeout}
+			svnc.Spec.ApprovalPolicy = vrbv1.ApprovalPolicyManual
+			Expect(fakeClient.Patch(context.TODO(), svnc, client.Merge)).To(Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcileRequestes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drains).To(BeZero())
+			svnc = new(vrbv1.SriovVrbNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), nodeNameRef, svnc)).ToNot(HaveOccurred())
+			condition := svnc.FindCondition(ConditionConfigured)
+			Expect(condition.Reason).To(Equal(string(ConfigurationSucceeded)))
+			Expect(condition.ObservedGeneration).To(Equal(svnc.Generation))
+			Expect(svnc.FindCondition(ConditionPendingApproval)).To(BeNil())
+		})
+
 		It("restores/recreates VFs when sriov_numvfs is reset externally", func() {
 			// Verifies that when VFs disappear from sysfs without any Kubernetes
 			// spec change, the reconciler still calls VrbApplySpec to restore
@@ -589,197 +690,38 @@ var _ = Describe("VrbNodeConfigReconciler.Reconcile", func() {
 })
 
 var _ = Describe("VrbResourceName", func() {
-	var (
-		reconciler    *VrbNodeConfigReconciler
-		vrbnc         *vrbv1.SriovVrbNodeConfig
-		acc           vrbv1.PhysicalFunctionConfigExt
-		currentConfig map[string]interface{}
-		resourceList  []interface{}
-		scheme        *runtime.Scheme
-	)
-
-	BeforeEach(func() {
-		scheme = runtime.NewScheme()
-		Expect(vrbv1.AddToScheme(scheme)).ToNot(HaveOccurred())
-		Expect(v1.AddToScheme(scheme)).ToNot(HaveOccurred()) // Register the core v1 types
-
-		reconciler = &VrbNodeConfigReconciler{
-			log:    utils.NewLogger(),
-			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
-			drainerAndExecute: func(configurer func(ctx context.Context) bool, drain bool) error {
-				_ = configurer(context.TODO())
-				return nil
-			},
-			restartDevicePlugin: func() error {
-				return nil
-			},
-		}
-
-		vrbnc = &vrbv1.SriovVrbNodeConfig{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-vrbnc",
-				Namespace: "default",
-			},
-		}
-		acc = vrbv1.PhysicalFunctionConfigExt{
-			PCIAddress:      "0000:00:00.0",
-			VrbResourceName: "test-resource",
-		}
-		currentConfig = make(map[string]interface{})
-		resourceList = []interface{}{}
-	})
-
-	Describe("loadAndModifyDevicePluginConfig", func() {
-		It("should modify the ConfigMap if needed", func() {
-			// Create the SriovVrbNodeConfig object in the fake client
-			err := reconciler.Client.Create(context.TODO(), vrbnc)
-			Expect(err).NotTo(HaveOccurred())
-
-			err = reconciler.loadAndModifyDevicePluginConfig(vrbnc, acc)
-			Expect(err).NotTo(HaveOccurred())
-		})
-	})
-
-	Describe("matchPFPCIAddress", func() {
-		It("should return true if the PF_PCI_ADDR stored in additional information matches", func() {
-			resourceMap := map[string]interface{}{
-				"additionalInfo": map[string]interface{}{
-					"*": map[string]interface{}{
-						"PF_PCI_ADDR": "0000:00:00.0",
-					},
-				},
-			}
-			matches := reconciler.matchPFPCIAddress(resourceMap, "0000:00:00.0")
-			Expect(matches).To(BeTrue())
-		})
-	})
-
-	Describe("matchVFDeviceID", func() {
-		It("should return true if selectors[\"devices\"] match the VF device ID", func() {
-			resourceMap := map[string]interface{}{
-				"additionalInfo": map[string]interface{}{
-					"*": map[string]interface{}{
-						"PF_PCI_ADDR": "0000:00:00.0",
-					},
-				},
-				"selectors": map[string]interface{}{
-					"devices": []interface{}{"57c3"},
-				},
-			}
-			matches := reconciler.matchVFDeviceID(resourceMap, "57c3")
-			Expect(matches).To(BeTrue())
-		})
-	})
-
-	Describe("handleResourceNotFound", func() {
-		It("should handle the case where a resource was not found", func() {
-			// Create a fake ConfigMap
-			configMap := &v1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "sriovdp-config",
-					Namespace: "default",
-				},
-				Data: map[string]string{
-					"config.json": "{}",
-				},
-			}
-			err := reconciler.Client.Create(context.TODO(), configMap)
-			Expect(err).NotTo(HaveOccurred())
-
-			// Set the nodeNameRef.Namespace to "default" to match the ConfigMap namespace
-			reconciler.nodeNameRef.Namespace = "default"
-
-			vfAddresses := []string{"0000:00:00.1"}
-			err = reconciler.handleResourceNotFound(currentConfig, resourceList, "57c3", acc, vfAddresses)
-			Expect(err).NotTo(HaveOccurred())
-		})
+	It("should not require reconfiguration of the PF when only its resource name changes", func() {
+		reconciler := &VrbNodeConfigReconciler{log: utils.NewLogger()}
+		defer delete(vrbDeviceUpdateRequired, "0000:00:00.0")
+		previous := vrbv1.PhysicalFunctionConfigExt{PCIAddress: "0000:00:00.0", VFAmount: 2}
+		current := previous
+		current.VrbResourceName = "test-resource"
+
+		reconciler.checkIfDeviceUpdateNeeded(map[string]vrbv1.PhysicalFunctionConfigExt{previous.PCIAddress: previous},
+			map[string]vrbv1.PhysicalFunctionConfigExt{current.PCIAddress: current})
+		Expect(vrbDeviceUpdateRequired).To(HaveKeyWithValue("0000:00:00.0", false))
+
+		current.VFAmount = 4
+		reconciler.checkIfDeviceUpdateNeeded(map[string]vrbv1.PhysicalFunctionConfigExt{previous.PCIAddress: previous},
+			map[string]vrbv1.PhysicalFunctionConfigExt{current.PCIAddress: current})
+		Expect(vrbDeviceUpdateRequired).To(HaveKeyWithValue("0000:00:00.0", true))
 	})
 
-	Describe("updateConfigMap", func() {
-		It("should update the ConfigMap", func() {
-			// Create a fake ConfigMap
-			configMap := &v1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "sriovdp-config",
-					Namespace: "default",
-				},
-				Data: map[string]string{
-					"config.json": "{}",
-				},
-			}
-			err := reconciler.Client.Create(context.TODO(), configMap)
-			Expect(err).NotTo(HaveOccurred())
-
-			// Prepare the new config
-			newConfig := make(map[string]interface{})
-			newConfig["resourceList"] = []interface{}{
-				map[string]interface{}{
-					"resourceName": "new-resource",
-					"selectors": map[string]interface{}{
-						"devices": []interface{}{"57c3"},
-					},
-				},
-			}
-
-			// Set the nodeNameRef.Namespace to "default" to match the ConfigMap namespace
-			reconciler.nodeNameRef.Namespace = "default"
-
-			// Run the updateConfigMap function
-			err = reconciler.updateConfigMap(newConfig, "new-resource")
-			Expect(err).NotTo(HaveOccurred())
-
-			// Verify the ConfigMap was updated
-			updatedConfigMap := &v1.ConfigMap{}
-			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "sriovdp-config", Namespace: "default"}, updatedConfigMap)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(updatedConfigMap.Data["config.json"]).To(ContainSubstring("new-resource"))
-		})
-	})
-
-	Describe("modifyResource", func() {
-		It("should modify the resource", func() {
-			resourceMap := map[string]interface{}{
-				"additionalInfo": map[string]interface{}{
-					"*": map[string]interface{}{
-						"PF_PCI_ADDR": "0000:00:00.0",
-					},
-				},
-				"selectors": map[string]interface{}{
-					"devices": []interface{}{"57c3"},
-				},
-			}
-			vfAddresses := []string{"0000:00:00.1"}
-			modified := reconciler.modifyResource(resourceMap, "new-resource", "0000:00:00.0", vfAddresses)
-			Expect(modified).To(BeTrue())
-		})
-	})
-
-	Describe("loadCurrentDevicePluginConfig", func() {
-		It("should load the current device plugin config", func() {
-			// Create a fake ConfigMap
-			configMap := &v1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "sriovdp-config",
-					Namespace: "default",
-				},
-				Data: map[string]string{
-					"config.json": `{"resourceList": [{"resourceName": "test-resource"}]}`,
-				},
-			}
-			err := reconciler.Client.Create(context.TODO(), configMap)
-			Expect(err).NotTo(HaveOccurred())
-
-			// Set the nodeNameRef.Namespace to "default" to match the ConfigMap namespace
-			reconciler.nodeNameRef.Namespace = "default"
-
-			// Run the loadCurrentDevicePluginConfig function
-			config, err := reconciler.loadCurrentDevicePluginConfig()
-			Expect(err).NotTo(HaveOccurred())
-			Expect(config).NotTo(BeNil())
-			Expect(config["resourceList"]).To(HaveLen(1))
-			resourceList := config["resourceList"].([]interface{})
-			resourceMap := resourceList[0].(map[string]interface{})
-			Expect(resourceMap["resourceName"]).To(Equal("test-resource"))
-		})
+	It("should require reconfiguration of the PF only when drivers of its VFs change", func() {
+		reconciler := &VrbNodeConfigReconciler{log: utils.NewLogger()}
+		defer delete(vrbDeviceUpdateRequired, "0000:00:00.0")
+		previous := vrbv1.PhysicalFunctionConfigExt{PCIAddress: "0000:00:00.0", VFDriver: "vfio-pci", VFAmount: 4,
+			VFGroups: []vrbv1.VFGroup{{First: 0, Last: 1, ResourceName: "du_a"}}}
+		current := previous
+		current.VFGroups = []vrbv1.VFGroup{{First: 0, Last: 2, ResourceName: "du_b"}}
+
+		reconciler.checkIfDeviceUpdateNeeded(map[string]vrbv1.PhysicalFunctionConfigExt{previous.PCIAddress: previous},
+			map[string]vrbv1.PhysicalFunctionConfigExt{current.PCIAddress: current})
+		Expect(vrbDeviceUpdateRequired).To(HaveKeyWithValue("0000:00:00.0", false))
+
+		current.VFGroups = []vrbv1.VFGroup{{First: 0, Last: 2, ResourceName: "du_b", VFDriver: "igb_uio"}}
+		reconciler.checkIfDeviceUpdateNeeded(map[string]vrbv1.PhysicalFunctionConfigExt{previous.PCIAddress: previous},
+			map[string]vrbv1.PhysicalFunctionConfigExt{current.PCIAddress: current})
+		Expect(vrbDeviceUpdateRequired).To(HaveKeyWithValue("0000:00:00.0", true))
 	})
 })
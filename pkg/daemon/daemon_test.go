@@ -15,6 +15,7 @@ import (
 
 	fuzz "github.com/google/gofuzz"
 	"github.com/google/uuid"
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
 	"github.com/intel/sriov-fec-operator/pkg/common/utils"
 
 	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
@@ -104,7 +105,7 @@ var _ = Describe("FecNodeConfigReconciler", func() {
 
 				nodeNameRef := types.NamespacedName{Namespace: _SUPPORTED_NAMESPACE, Name: _THIS_NODE_NAME}
 
-				drainer := func(operation func(ctx context.Context) bool, drain bool) error { return nil }
+				drainer := func(operation func(ctx context.Context) bool, opts drainhelper.DrainOptions) error { return nil }
 
 				var err error
 				reconciler, err = FecNewNodeConfigReconciler(&onGetErrorReturningClient, drainer, nodeNameRef, nil, nil)
@@ -145,7 +146,7 @@ var _ = Describe("FecNodeConfigReconciler", func() {
 
 					reconciler, err := FecNewNodeConfigReconciler(
 						k8sClient,
-						func(configure func(ctx context.Context) bool, drain bool) error {
+						func(configure func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
 							configure(context.TODO())
 							return nil
 						},
@@ -271,7 +272,7 @@ var _ = Describe("FecNodeConfigReconciler", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(k8sClient).ToNot(BeNil())
 
-					drainer := func(configure func(ctx context.Context) bool, drain bool) error {
+					drainer := func(configure func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
 						configure(context.TODO())
 						return nil
 					}
@@ -595,7 +596,7 @@ func FuzzIsCardUpdateRequired(f *testing.F) {
 		Client:      nil,
 		log:         &logrus.Logger{},
 		nodeNameRef: types.NamespacedName{},
-		drainerAndExecute: func(configurer func(ctx context.Context) bool, drain bool) error {
+		drainerAndExecute: func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
 			return nil
 		},
 		sriovfecconfigurer: nil,
@@ -632,7 +633,7 @@ func FuzzVrbIsCardUpdateRequired(f *testing.F) {
 		Client:      nil,
 		log:         &logrus.Logger{},
 		nodeNameRef: types.NamespacedName{},
-		drainerAndExecute: func(configurer func(ctx context.Context) bool, drain bool) error {
+		drainerAndExecute: func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
 			return nil
 		},
 		vrbconfigurer: nil,
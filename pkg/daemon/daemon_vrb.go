@@ -6,17 +6,13 @@ package daemon
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/intel/sriov-fec-operator/pkg/common/utils"
 	"github.com/sirupsen/logrus"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
@@ -33,7 +29,6 @@ var (
 	VrbConfigPath            = "/sriov_config/config/accelerators_vrb.json"
 	VrbgetSriovInventory     = VrbGetSriovInventory
 	VrbsupportedAccelerators utils.AcceleratorDiscoveryConfig
-	configMapResource        sync.Map
 	vrbPreviousConfig        = make(map[string]vrbv1.PhysicalFunctionConfigExt)
 	vrbCurrentConfig         = make(map[string]vrbv1.PhysicalFunctionConfigExt)
 	vrbDeviceUpdateRequired  = make(map[string]bool)
@@ -46,8 +41,9 @@ type VrbNodeConfigReconciler struct {
 	drainerAndExecute   DrainAndExecute
 	vrbconfigurer       VrbConfigurer
 	restartDevicePlugin RestartDevicePluginFunction
-	cmRetrieveTime      time.Time
-	cmRetrieveMutex     sync.Mutex
+	nodeFeatures        *NodeFeatureWriter
+	devicePlugin        *DevicePlugin
+	cdiSpecs            *CDISpecWriter
 }
 
 type VrbConfigurer interface {
@@ -72,10 +68,15 @@ type VrbConfigurer interface {
  *              8. Updates the status to indicate whether the configuration
  *                 succeeded or failed.
  ****************************************************************************/
-func (r *VrbNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *VrbNodeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.log.Debugf("VrbReconcile(...) triggered by %s", req.NamespacedName.String())
 
-	r.setLogLevel()
+	if tornDown, err := isTornDown(ctx, r, r.nodeNameRef); err != nil {
+		return requeueNowWithError(err)
+	} else if tornDown {
+		r.log.Info("accelerators of the node are torn down - skipping configuration")
+		return requeueLater()
+	}
 
 	vrbnc, err := r.readNodeConfig(req.NamespacedName)
 
@@ -106,8 +107,23 @@ func (r *VrbNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request)
 		vrbDeviceUpdateRequired[accelerator.PCIAddress] = true
 	}
 
+	r.writeNodeFeatures(vrbnc, vrbdetectedInventory)
+	r.devicePlugin.UpdateVrbDevices(vrbdetectedInventory)
+	r.writeCDISpec(vrbdetectedInventory)
+
 	if !r.isCardUpdateRequired(vrbnc, vrbdetectedInventory) {
+		if vrbnc.GetGeneration() != VrbfindOrCreateConfigurationStatusCondition(vrbnc).ObservedGeneration {
+			r.log.WithField("generation", vrbnc.GetGeneration()).Info("physical functions are applied already, acknowledging changed settings of reconfiguration")
+			meta.RemoveStatusCondition(&vrbnc.Status.Conditions, ConditionPendingApproval)
+			return requeueLaterOrNowIfError(r.updateStatus(vrbnc, metav1.ConditionTrue, ConfigurationSucceeded, "Configured successfully"))
+		}
 		r.log.Debug("SriovVrb: Nothing to do")
+		return requeueLaterOrNowIfError(r.refreshInventory(vrbnc, vrbdetectedInventory))
+	}
+
+	if approved, err := r.isReconfigurationApproved(vrbnc, vrbdetectedInventory); err != nil {
+		return requeueNowWithError(err)
+	} else if !approved {
 		return requeueLater()
 	}
 
@@ -150,26 +166,6 @@ func (r *VrbNodeConfigReconciler) Reconcile(_ context.Context, req ctrl.Request)
 	return requeueLaterOrNowIfError(r.updateStatus(vrbnc, metav1.ConditionTrue, ConfigurationSucceeded, "Configured successfully"))
 }
 
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::handleSriovDevicePluginConfigMap
- * Description: Handles the SR-IOV device plugin ConfigMap configuration,
- *		including loading, modifying and updating it if needed.
- * Returns an error if the device plugin configuration could not be handled.
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) handleSriovDevicePluginConfigMap(vrbnc *vrbv1.SriovVrbNodeConfig) error {
-	// Process each physical function
-	for _, acc := range vrbnc.Spec.PhysicalFunctions {
-		if acc.VrbResourceName == "" {
-			continue
-		}
-		if err := r.loadAndModifyDevicePluginConfig(vrbnc, acc); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 /*****************************************************************************
  * Method: VrbNodeConfigReconciler::CreateEmptyNodeConfigIfNeeded
  * Description:
@@ -229,6 +225,51 @@ func (r *VrbNodeConfigReconciler) CreateEmptyNodeConfigIfNeeded(c client.Client)
 	return nil
 }
 
+// Logger returns the logger of the reconciler
+func (r *VrbNodeConfigReconciler) Logger() *logrus.Logger {
+	return r.log
+}
+
+// SetNodeFeatureWriter makes the reconciler publish its accelerators as Node Feature Discovery features
+func (r *VrbNodeConfigReconciler) SetNodeFeatureWriter(w *NodeFeatureWriter) {
+	r.nodeFeatures = w
+}
+
+// SetDevicePlugin makes the reconciler serve VFs of its inventory with the built-in device plugin
+func (r *VrbNodeConfigReconciler) SetDevicePlugin(p *DevicePlugin) {
+	r.devicePlugin = p
+}
+
+// SetCDISpecWriter makes the reconciler maintain Container Device Interface specs of VFs of its inventory
+func (r *VrbNodeConfigReconciler) SetCDISpecWriter(w *CDISpecWriter) {
+	r.cdiSpecs = w
+}
+
+// refreshInventory keeps the inventory in the status up to date, e.g. with VF consumers, between reconfigurations
+func (r *VrbNodeConfigReconciler) refreshInventory(nc *vrbv1.SriovVrbNodeConfig, inventory *vrbv1.NodeInventory) error {
+	if equality.Semantic.DeepEqual(nc.Status.Inventory, *inventory) {
+		return nil
+	}
+	nc.Status.Inventory = *inventory
+	if err := r.Status().Update(context.Background(), nc); err != nil {
+		return err
+	}
+	r.log.Debug("inventory in the status refreshed")
+	return nil
+}
+
+func (r *VrbNodeConfigReconciler) writeCDISpec(inventory *vrbv1.NodeInventory) {
+	if err := r.cdiSpecs.Write("vrb", vrbCDIVFs(inventory)); err != nil {
+		r.log.WithError(err).Error("failed to write CDI spec")
+	}
+}
+
+func (r *VrbNodeConfigReconciler) writeNodeFeatures(nc *vrbv1.SriovVrbNodeConfig, inventory *vrbv1.NodeInventory) {
+	if err := r.nodeFeatures.Write("vrb", vrbNodeFeatures(nc, inventory)); err != nil {
+		r.log.WithError(err).Error("failed to publish node features")
+	}
+}
+
 /*****************************************************************************
  * Method: VrbNodeConfigReconciler::SetupWithManager
  * Description:
@@ -244,7 +285,8 @@ func (r *VrbNodeConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					requiredName: r.nodeNameRef.Name,
 					log:          r.log,
 				},
-				predicate.GenerationChangedPredicate{},
+				// approval annotation does not change the generation
+				predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
 			),
 		).Complete(r)
 }
@@ -279,6 +321,7 @@ func (r *VrbNodeConfigReconciler) updateStatus(nc *vrbv1.SriovVrbNodeConfig,
 		for _, pf := range nc.Spec.PhysicalFunctions {
 			vrbPreviousConfig[pf.PCIAddress] = pf
 		}
+		nc.Status.AppliedPhysicalFunctions = nc.DeepCopy().Spec.PhysicalFunctions
 	}
 
 	// SriovFecNodeConfig.generation is under K8S management
@@ -313,6 +356,9 @@ func (r *VrbNodeConfigReconciler) updateStatus(nc *vrbv1.SriovVrbNodeConfig,
 	if err := r.Status().Update(context.Background(), nc); err != nil {
 		return err
 	}
+	r.writeNodeFeatures(nc, &nc.Status.Inventory)
+	r.devicePlugin.UpdateVrbDevices(&nc.Status.Inventory)
+	r.writeCDISpec(&nc.Status.Inventory)
 
 	r.log.WithField("previous", previousCondition).
 		WithField("current", condition).
@@ -376,23 +422,74 @@ func (r *VrbNodeConfigReconciler) readNodeConfig(nn types.NamespacedName) (nc *v
  ****************************************************************************/
 func (r *VrbNodeConfigReconciler) configureNode(nodeConfig *vrbv1.SriovVrbNodeConfig) error {
 	var configurationError error
+	var hookResults []vrbv1.HookResult
+	hooks := hookRunner{client: r.Client, log: r.log, nodeNameRef: r.nodeNameRef}
+	pfs := pfsRequiringUpdate(vrbDeviceUpdateRequired)
+
+	runHook := func(ctx context.Context, phase hookPhase) error {
+		hook := vrbReconfigurationHook(nodeConfig.Spec.Hooks, phase)
+		if hook == nil {
+			return nil
+		}
+		outcome, err := hooks.run(ctx, phase, hook, pfs)
+		hookResults = append(hookResults, outcome.vrbHookResult())
+		return err
+	}
+
+	marker := nodeReconfigurationMarker{client: r.Client, log: r.log, nodeName: r.nodeNameRef.Name}
+
+	markerSet := false
+	removeMarker := func() {
+		if !markerSet {
+			return
+		}
+		markerSet = false
+		if err := marker.end(context.TODO()); err != nil {
+			r.log.WithError(err).Error("failed to remove reconfiguration taint from the node")
+		}
+	}
 
 	drainFunc := func(ctx context.Context) bool {
+		marker.progress(ctx, reconfigurationApplyingConfiguration)
 		if err := r.vrbconfigurer.VrbApplySpec(nodeConfig.Spec, vrbDeviceUpdateRequired); err != nil {
 			r.log.WithError(err).Error("failed applying new PF/VF configuration")
 			configurationError = err
 			return true
 		}
-		if err := r.handleSriovDevicePluginConfigMap(nodeConfig); err != nil {
-			r.log.WithError(err).Error("failed updating the sriov device plugin ConfigMap")
-			configurationError = err
+		marker.progress(ctx, reconfigurationRestartingDevicePlugin)
+		if configurationError = r.restartDevicePlugin(); configurationError != nil {
 			return true
 		}
-		configurationError = r.restartDevicePlugin()
+		removeMarker()
+
+		configurationError = runHook(ctx, postReconfigurationHook)
 		return true
 	}
 
-	if err := r.drainerAndExecute(drainFunc, !nodeConfig.Spec.DrainSkip); err != nil {
+	drainOptions, err := drainOptionsFor(r.Client, r.log, r.nodeNameRef, string(nodeConfig.Spec.EffectiveDrainPolicy()),
+		vrbDrainSettings(nodeConfig.Spec.DrainSettings), vrbDeviceUpdateRequired)
+	if err != nil {
+		r.log.WithError(err).Error("failed to resolve drain options")
+		return err
+	}
+
+	// taint keeps new pods away from the node from the drain on, the node is not cordoned under AcceleratorConsumersOnly
+	// policy; it is best effort protection, stale taints are removed at daemon startup. The node is tainted by the
+	// leader only, so that nodes waiting for the reconfiguration stay schedulable.
+	// Consumers of accelerators quiesce their queues right before they are evicted by the drain of their node
+	drainOptions.BeforeDrain = func(ctx context.Context) error {
+		markerSet = true
+		if err := marker.begin(ctx); err != nil {
+			r.log.WithError(err).Error("failed to taint the node for the time of reconfiguration")
+		}
+		return runHook(ctx, preReconfigurationHook)
+	}
+	defer removeMarker()
+	// results are exposed in the status by the subsequent updateStatus call
+	defer func() { nodeConfig.Status.HookResults = hookResults }()
+
+	err = r.drainerAndExecute(drainFunc, drainOptions)
+	if err != nil {
 		return err
 	}
 
@@ -430,7 +527,7 @@ func (r *VrbNodeConfigReconciler) isCardUpdateRequired(nc *vrbv1.SriovVrbNodeCon
 	}
 	isGenerationChanged := func() bool {
 		observedGeneration := VrbfindOrCreateConfigurationStatusCondition(nc).ObservedGeneration
-		if nc.GetGeneration() != observedGeneration {
+		if nc.GetGeneration() != observedGeneration && !VrbarePhysicalFunctionsApplied(nc) {
 			r.log.WithField("observed", observedGeneration).
 				WithField("requested", nc.GetGeneration()).
 				Info("Observed generation doesn't reflect requested one")
@@ -463,6 +560,51 @@ func (r *VrbNodeConfigReconciler) isCardUpdateRequired(nc *vrbv1.SriovVrbNodeCon
 	return isGenerationChanged() || exposedInventoryOutdated() || r.bbDevConfigDaemonIsDead(nc)
 }
 
+/*****************************************************************************
+ * Method: VrbNodeConfigReconciler::isReconfigurationApproved
+ * Description: Returns true if the node config does not require approval or
+ * 		its generation has been approved. Otherwise publishes PendingApproval
+ * 		condition describing the pending changes and their predicted impact.
+ ****************************************************************************/
+func (r *VrbNodeConfigReconciler) isReconfigurationApproved(nc *vrbv1.SriovVrbNodeConfig, detectedInventory *vrbv1.NodeInventory) (bool, error) {
+	if !nc.Spec.RequiresApproval() || isGenerationApproved(nc) {
+		// persisted by the subsequent updateStatus call
+		meta.RemoveStatusCondition(&nc.Status.Conditions, ConditionPendingApproval)
+		return true, nil
+	}
+
+	requested := map[string]vrbv1.PhysicalFunctionConfigExt{}
+	for _, pf := range nc.Spec.PhysicalFunctions {
+		requested[pf.PCIAddress] = pf
+	}
+	// the status survives restarts of the daemon unlike the configuration kept in memory
+	applied := map[string]vrbv1.PhysicalFunctionConfigExt{}
+	for _, pf := range nc.Status.AppliedPhysicalFunctions {
+		applied[pf.PCIAddress] = pf
+	}
+	var outdated []string
+	for _, acc := range detectedInventory.SriovAccelerators {
+		if pf, ok := requested[acc.PCIAddress]; ok && len(acc.VFs) != pf.VFAmount {
+			outdated = append(outdated, acc.PCIAddress)
+		}
+	}
+
+	condition := pendingApprovalCondition(nc.GetGeneration(), describePfChanges(applied, requested),
+		predictedImpact(string(nc.Spec.EffectiveDrainPolicy()), pfsToReset(applied, requested, outdated)))
+	if previous := nc.FindCondition(ConditionPendingApproval); previous != nil && previous.Message == condition.Message {
+		r.log.WithField("generation", nc.GetGeneration()).Debug("reconfiguration still awaits approval")
+		return false, nil
+	}
+
+	meta.SetStatusCondition(&nc.Status.Conditions, condition)
+	if err := r.Status().Update(context.Background(), nc); err != nil {
+		return false, err
+	}
+	r.log.WithField("generation", nc.GetGeneration()).WithField("details", condition.Message).
+		Info("reconfiguration awaits approval")
+	return false, nil
+}
+
 /*****************************************************************************
  * Function: VrbNewNodeConfigReconciler
  * Description:
@@ -484,6 +626,19 @@ func VrbNewNodeConfigReconciler(k8sClient client.Client, drainer DrainAndExecute
 	}, nil
 }
 
+/*****************************************************************************
+ * Function: VrbarePhysicalFunctionsApplied
+ * Description: Returns true if the requested physical functions were applied
+ * 		successfully already, i.e. the generation changed only by
+ * 		settings of the reconfiguration like drainSettings, hooks or
+ * 		approvalPolicy which do not require reconfiguration on their own
+ ****************************************************************************/
+func VrbarePhysicalFunctionsApplied(nc *vrbv1.SriovVrbNodeConfig) bool {
+	condition := nc.FindCondition(ConditionConfigured)
+	return condition != nil && condition.Reason == string(ConfigurationSucceeded) &&
+		equality.Semantic.DeepEqual(nc.Status.AppliedPhysicalFunctions, nc.Spec.PhysicalFunctions)
+}
+
 /*****************************************************************************
  * Function: VrbfindOrCreateConfigurationStatusCondition
  * Description:
@@ -579,387 +734,6 @@ func (r *VrbNodeConfigReconciler) getVrbPfBbConfVersion(shouldLog bool) string {
 	return out.String()
 }
 
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::loadAndModifyDevicePluginConfig
- * Description: Loads the sriovdp-config ConfigMap and modifies the resourceName
- * 	    	and PF_PCI_ADDR for the requested pfPciAddress
- * Returns an error if the ConfigMap could not be loaded or modified
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) loadAndModifyDevicePluginConfig(vrbnc *vrbv1.SriovVrbNodeConfig, acc vrbv1.PhysicalFunctionConfigExt) error {
-	// Load the current sriovdp-config ConfigMap
-	currentConfig, err := r.loadCurrentDevicePluginConfig()
-	if err != nil {
-		return r.updateStatus(vrbnc, metav1.ConditionFalse, ConfigurationFailed, err.Error())
-	}
-
-	// Check if currentConfig["resourceList"] is a non-empty slice
-	resourceList, ok := currentConfig["resourceList"].([]interface{})
-	if !ok || len(resourceList) == 0 {
-		r.log.Info("currentConfig does not contain a valid resourceList")
-		return r.updateStatus(vrbnc, metav1.ConditionFalse, ConfigurationFailed, "currentConfig does not contain a valid resourceList")
-	}
-
-	// Get the VF device ID
-	vfDeviceID, err := utils.GetVFDeviceID(acc.PCIAddress)
-	if err != nil {
-		r.log.WithError(err).Error("failed to get VF device ID")
-	}
-	r.log.Infof("VF device ID: %s for PCIAddress: %s", vfDeviceID, acc.PCIAddress)
-
-	// Find all VFs configured for the PF
-	vfAddresses, err := utils.FindVFs(acc.PCIAddress)
-	r.log.WithField("pfPciAddress", acc.PCIAddress).Infof("Found VFs: %v", vfAddresses)
-	if err != nil {
-		r.log.WithError(err).Error("failed to find VFs")
-	}
-
-	// Check if the ConfigMap resource exists and needs to be updated
-	if modified, err := r.resourceFoundAndUpdated(currentConfig, resourceList, vfDeviceID, acc, vfAddresses); err != nil {
-		r.log.WithError(err).WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Error("failed to update ConfigMap")
-		return r.updateStatus(vrbnc, metav1.ConditionFalse, ConfigurationFailed, err.Error())
-	} else if modified {
-		r.log.WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Info("ConfigMap resource updated successfully")
-		return r.updateStatus(vrbnc, metav1.ConditionTrue, ConfigurationSucceeded, "ConfigMap resource updated successfully")
-	}
-
-	// Handle the case where a resource matching vfDeviceID and pfPciAddress was not found in the ConfigMap
-	return r.handleResourceNotFound(currentConfig, resourceList, vfDeviceID, acc, vfAddresses)
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::resourceFoundAndUpdated
- * Description: Checks if the ConfigMap resource needs to be updated
- * Returns true if the resource was modified or
- *		if the number of pciAddresses in the resourceMap match the VFs found and the resourceName is the same as the newResourceName
- * 	   false otherwise
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) resourceFoundAndUpdated(currentConfig map[string]interface{}, resourceList []interface{}, vfDeviceID string, acc vrbv1.PhysicalFunctionConfigExt, vfAddresses []string) (bool, error) {
-	for i := 0; i < len(resourceList); i++ {
-		resourceMap, ok := resourceList[i].(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if !r.matchVFDeviceID(resourceMap, vfDeviceID) {
-			continue
-		}
-		if !r.matchPFPCIAddress(resourceMap, acc.PCIAddress) {
-			continue
-		}
-		if r.matchVFsAndResourceName(resourceMap, acc.VrbResourceName, vfAddresses) {
-			// VFs and resourceName match, no need to update the resource
-			r.log.WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Info("ConfigMap resource already exists and is up-to-date")
-			return true, nil
-		}
-		if r.modifyResource(resourceMap, acc.VrbResourceName, acc.PCIAddress, vfAddresses) {
-			if err := r.updateConfigMap(currentConfig, acc.VrbResourceName); err != nil {
-				r.log.WithError(err).WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Error("failed to update ConfigMap")
-				return false, err
-			} else {
-				r.log.WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Info("ConfigMap resource modified successfully")
-				return true, nil
-			}
-		}
-	}
-	return false, nil
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::matchVFsAndResourceName
- * Description: Checks if the amount of pciAddresses in the resourceMap match the VFs found and
- * 		if the resourceName is the same as the newResourceName
- * Returns true if number of pciAddresses in the resourceMap match the VFs found and
- * 		the resourceName is the same as the newResourceName, false otherwise
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) matchVFsAndResourceName(resourceMap map[string]interface{}, newResourceName string, vfAddresses []string) bool {
-	resourceName, ok := resourceMap["resourceName"].(string)
-	if !ok {
-		return false
-	}
-	selectors, ok := resourceMap["selectors"].(map[string]interface{})
-	if !ok {
-		return false
-	}
-	pciAddresses, ok := selectors["pciAddresses"].([]interface{})
-	if !ok {
-		r.log.WithField("selectors", selectors).Info("pciAddresses not found in resourceMap")
-		return false
-	}
-	r.log.WithField("pciAddresses", pciAddresses).WithField("vfAddresses", vfAddresses).Infof("current vfAddresses amount: %d requested vfAddresses: %d", len(pciAddresses), len(vfAddresses))
-	r.log.WithField("resourceName", resourceName).WithField("newResourceName", newResourceName).Info("resourceName")
-	return resourceName == newResourceName && len(pciAddresses) == len(vfAddresses)
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::matchPFPCIAddress
- * Description: Checks the additional information in the resource map to see if
- *              PF_PCI_ADDR matches the requested PF PCI address.
- * Returns: true if the PF_PCI_ADDR stored in additional information matches or if not set
- * 	    false otherwise.
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) matchPFPCIAddress(resourceMap map[string]interface{}, pfPciAddress string) bool {
-	additionalInfoMap, ok := resourceMap["additionalInfo"].(map[string]interface{})
-	if !ok {
-		r.log.Infof("additionalInfo not found in resourceMap")
-		return false
-	}
-
-	starInfo, ok := additionalInfoMap["*"].(map[string]interface{})
-	if !ok {
-		r.log.Infof("additionalInfo[*] not found in resourceMap")
-		return false
-	}
-
-	pfPciAddr, ok := starInfo["PF_PCI_ADDR"].(string)
-	if ok {
-		// If the PF_PCI_ADDR is already set and it is different from the new PF_PCI_ADDR, return false
-		if strings.TrimSpace(pfPciAddr) != pfPciAddress {
-			r.log.Infof("PF_PCI_ADDR=%s in resourceMap does not match requested pfPciAddress=%s", pfPciAddr, pfPciAddress)
-			return false
-		}
-	}
-	return true
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::matchVFDeviceID
- * Description: Checks the selectors["devices"] in the resource map to see if
- * 		they match the VF device ID. If they do, it saves the original
- *		ConfigMap resource if it hasn't been saved already.
- * Returns true if selectors["devices"] equals vfDeviceID, false otherwise.
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) matchVFDeviceID(resourceMap map[string]interface{}, vfDeviceID string) bool {
-	selectors, ok := resourceMap["selectors"].(map[string]interface{})
-	if !ok {
-		return false
-	}
-	devices, ok := selectors["devices"].([]interface{})
-	if !ok || len(devices) == 0 {
-		return false
-	}
-	if deviceID, ok := devices[0].(string); ok && deviceID == vfDeviceID {
-		// Save original ConfigMap resource
-		if _, loaded := configMapResource.LoadOrStore(vfDeviceID, copyResource(resourceMap)); !loaded {
-			r.log.WithFields(logrus.Fields{
-				"vfDeviceID":       vfDeviceID,
-				"originalResource": resourceMap,
-			}).Info("original resource saved")
-		}
-		return true
-	}
-	return false
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::handleResourceNotFound
- * Description: Handles the case where a resource matching vfDeviceID and
- * 		pfPciAddress was not found in the ConfigMap
- * Returns an error if the resource could not be modified
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) handleResourceNotFound(config map[string]interface{}, resourceList []interface{}, vfDeviceID string, acc vrbv1.PhysicalFunctionConfigExt, vfAddresses []string) error {
-	// Check if the configMapResource map contains the original resource for the vfDeviceID
-	if originalResource, exists := configMapResource.Load(vfDeviceID); exists {
-		r.log.WithFields(logrus.Fields{
-			"vfDeviceID":       vfDeviceID,
-			"pfPciAddress":     acc.PCIAddress,
-			"newResourceName":  acc.VrbResourceName,
-			"originalResource": originalResource,
-		}).Info("modifying original resource")
-
-		// Create a new resource based on the original resource
-		newResource := copyResource(originalResource)
-		newResourceMap, ok := newResource.(map[string]interface{})
-		if !ok {
-			r.log.Infof("resourceMap not found in new resource")
-			return nil
-		}
-
-		// Modify the new resource
-		if r.modifyResource(newResourceMap, acc.VrbResourceName, acc.PCIAddress, vfAddresses) {
-			// Append the new resource to the resourceList
-			r.log.WithField("originalConfig", config).Info("original sriov-device-plugin config")
-			resourceList = append(resourceList, newResource)
-			config["resourceList"] = resourceList
-			r.log.WithField("newConfig", config).Info("updated sriov-device-plugin config")
-			return r.updateConfigMap(config, acc.VrbResourceName)
-		} else {
-			r.log.WithField("pfPciAddress", acc.PCIAddress).WithField("resourceName", acc.VrbResourceName).Info("ConfigMap resource not modified")
-			return nil
-		}
-	} else {
-		r.log.WithField("vfDeviceID", vfDeviceID).WithField("pfPciAddress", acc.PCIAddress).Info("resource not found in ConfigMap")
-		return nil
-	}
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::updateConfigMap
- * Description: Updates the sriovdp-config ConfigMap with the modified data
- * Returns an error if the ConfigMap could not be updated
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) updateConfigMap(newConfig map[string]interface{}, newResourceName string) error {
-	modifiedData, err := json.MarshalIndent(newConfig, "", "  ")
-	if err != nil {
-		r.log.WithError(err).Error("failed to marshal modified config.json")
-		return err
-	}
-	ctx := context.TODO()
-	configMapName := "sriovdp-config"
-
-	// Extract the node name to construct a key for node-specific configuration in the ConfigMap.
-	// This key is used to check if a node-specific entry (config_$nodeName.json) exists and to update it if necessary
-	nodeName := r.nodeNameRef.Name
-	r.log.WithField("configMapName", configMapName).Debug("loading current sriovdp-config")
-
-	// Load the ConfigMap
-	configMap := &v1.ConfigMap{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: r.nodeNameRef.Namespace}, configMap); err != nil {
-		r.log.WithError(err).WithField("ConfigMap name", configMapName).Error("failed to load ConfigMap")
-		return err
-	}
-
-	// Check for config_$nodeName.json entry
-	nodeConfigKey := fmt.Sprintf("config_%s.json", nodeName)
-	_, ok := configMap.Data[nodeConfigKey]
-	if ok {
-		configMap.Data[nodeConfigKey] = string(modifiedData)
-	} else {
-		nodeConfigKey = "config.json"
-		configMap.Data["config.json"] = string(modifiedData)
-	}
-	// Attempt to update the ConfigMap in the cluster
-	if err := r.Client.Update(ctx, configMap); err != nil {
-		r.log.WithError(err).WithField("ConfigMap name", configMapName).Error("failed to update ConfigMap")
-		return err
-	}
-
-	r.log.WithFields(logrus.Fields{
-		"ConfigMap name": configMapName,
-		"nodeConfigKey":  nodeConfigKey,
-		"resourceName":   newResourceName,
-	}).Info("sriovdp-config modified successfully")
-
-	return nil
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::modifyResource
- * Description: Modifies the resource with the new resourceName and pfPciAddress
- * Returns true if the resource was modified, false otherwise
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) modifyResource(resourceMap map[string]interface{}, newResourceName string, pfPciAddress string, vfAddresses []string) bool {
-	selectors, ok := resourceMap["selectors"].(map[string]interface{})
-	if !ok {
-		return false
-	}
-
-	additionalInfoMap, ok := resourceMap["additionalInfo"].(map[string]interface{})
-	if !ok {
-		r.log.Infof("additionalInfo not found in resourceMap")
-		return false
-	}
-
-	starInfo, ok := additionalInfoMap["*"].(map[string]interface{})
-	if !ok {
-		r.log.Infof("additionalInfo[*] not found in resourceMap")
-		return false
-	}
-
-	r.log.WithFields(logrus.Fields{
-		"selectors":    selectors,
-		"resourceName": resourceMap["resourceName"],
-	}).Info("original resource map selectors and resourceName")
-
-	// Modify selectors["pciAddresses"], resourceName and additionalInfo["*"]["PF_PCI_ADDR"]
-	selectors["pciAddresses"] = vfAddresses
-	resourceMap["resourceName"] = newResourceName
-	starInfo["PF_PCI_ADDR"] = pfPciAddress
-
-	r.log.WithFields(logrus.Fields{
-		"selectors":    selectors,
-		"resourceName": resourceMap["resourceName"],
-	}).Info("new resource map selectors and resourceName")
-
-	return true
-}
-
-// copyResource creates a deep copy of the given resource
-func copyResource(resource interface{}) interface{} {
-	resourceBytes, err := json.Marshal(resource)
-	if err != nil {
-		log.Fatalf("failed to marshal resource: %v", err)
-	}
-	var newResource interface{}
-	if err := json.Unmarshal(resourceBytes, &newResource); err != nil {
-		log.Fatalf("failed to unmarshal resource: %v", err)
-	}
-	return newResource
-}
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::loadCurrentDevicePluginConfig
- * Description: Loads the current sriovdp-config ConfigMap
- * Returns the config.json data as a map[string]interface{} or an error
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) loadCurrentDevicePluginConfig() (map[string]interface{}, error) {
-	r.cmRetrieveMutex.Lock()
-	defer r.cmRetrieveMutex.Unlock()
-
-	// Enforce a delay of at least 1 second between executions
-	timeSinceLastExecution := time.Since(r.cmRetrieveTime)
-	if timeSinceLastExecution < time.Second {
-		time.Sleep(time.Second - timeSinceLastExecution)
-	}
-
-	// Update the last execution time
-	r.cmRetrieveTime = time.Now()
-
-	configMapName := "sriovdp-config"
-	nodeName := r.nodeNameRef.Name
-	r.log.WithField("configMapName", configMapName).Info("loading current sriovdp-config")
-
-	// Load the ConfigMap
-	configMap := &v1.ConfigMap{}
-	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: r.nodeNameRef.Namespace}, configMap); err != nil {
-		r.log.WithError(err).WithField("ConfigMap name", configMapName).Error("failed to load ConfigMap")
-		return nil, err
-	}
-
-	// Check for config_$nodeName.json entry
-	nodeConfigKey := fmt.Sprintf("config_%s.json", nodeName)
-	data, ok := configMap.Data[nodeConfigKey]
-	if !ok {
-		// If config_$nodeName.json entry does not exist, create it from config.json
-		// This is a fallback mechanism to ensure that the node-specific configuration is created when vrbCustomResourceName is set
-		r.log.WithField("nodeConfigKey", nodeConfigKey).Infof("%s not found, creating %s entry from config.json", nodeConfigKey, nodeConfigKey)
-
-		// Attempt to copy config.json if it exists
-		defaultConfigData, defaultConfigExists := configMap.Data["config.json"]
-		if !defaultConfigExists {
-			err := fmt.Errorf("config.json not found in ConfigMap %s", configMapName)
-			r.log.WithError(err).Error("failed to find config.json in ConfigMap")
-			return nil, err
-		}
-
-		// Copy config.json to config_$nodeName.json
-		configMap.Data[nodeConfigKey] = defaultConfigData
-		if err := r.Client.Update(context.TODO(), configMap); err != nil {
-			r.log.WithError(err).Errorf("failed to update ConfigMap with %s", nodeConfigKey)
-			return nil, err
-		}
-
-		data = defaultConfigData
-	}
-
-	// Parse the config_$nodeName.json data
-	var config map[string]interface{}
-	if err := json.Unmarshal([]byte(data), &config); err != nil {
-		r.log.WithError(err).Errorf("failed to unmarshal %s", nodeConfigKey)
-		return nil, err
-	}
-
-	return config, nil
-}
-
 /*****************************************************************************
  * Method: VrbNodeConfigReconciler::checkIfDeviceUpdateNeeded
  * Description: Determines if a device update is required based on the current
@@ -968,7 +742,13 @@ func (r *VrbNodeConfigReconciler) loadCurrentDevicePluginConfig() (map[string]in
 func (r *VrbNodeConfigReconciler) checkIfDeviceUpdateNeeded(previousConf, currentConf map[string]vrbv1.PhysicalFunctionConfigExt) {
 	// Check for updates in previous configuration
 	for k, prevConfig := range previousConf {
-		if currConfig, exists := currentConf[k]; !exists || !equality.Semantic.DeepEqual(prevConfig, currConfig) {
+		currConfig, exists := currentConf[k]
+		// resource name is served by sriovdp-config generated by the operator, it does not require reconfiguration of the PF
+		prevConfig.VrbResourceName, currConfig.VrbResourceName = "", ""
+		// VF groups are served the same way, only drivers of their VFs matter
+		prevDrivers, currDrivers := prevConfig.VFDrivers(), currConfig.VFDrivers()
+		prevConfig.VFGroups, currConfig.VFGroups = nil, nil
+		if !exists || !equality.Semantic.DeepEqual(prevConfig, currConfig) || !equality.Semantic.DeepEqual(prevDrivers, currDrivers) {
 			vrbDeviceUpdateRequired[k] = true
 		} else {
 			vrbDeviceUpdateRequired[k] = false
@@ -1001,55 +781,3 @@ func (r *VrbNodeConfigReconciler) updatePfBbConfVersionIfChanged(vrbnc *vrbv1.Sr
 	}
 	return nil
 }
-
-/*****************************************************************************
- * Method: VrbNodeConfigReconciler::setLogLevel
- * Description: Sets the log level based on the contents of the log level file.
- * If the file is empty or does not exist, it does nothing.
- * If the log level is changed, it logs a message indicating the change.
- ****************************************************************************/
-func (r *VrbNodeConfigReconciler) setLogLevel() {
-	logLevelFilePath := "/var/log/level.dat"
-	logLevelContent, err := os.ReadFile(logLevelFilePath)
-	if err != nil {
-		return
-	}
-
-	// If the file is empty, do nothing
-	if len(logLevelContent) == 0 {
-		r.log.Debugf("Log level file %s is empty", logLevelFilePath)
-		return
-	}
-
-	logLevelContent = bytes.TrimSpace(logLevelContent)
-	logLevelContent = bytes.ToLower(logLevelContent)
-	logLevelString := string(logLevelContent)
-
-	// Get the current log level
-	currentLogLevel := r.log.GetLevel()
-
-	switch logLevelString {
-	case "warn":
-		if currentLogLevel != logrus.WarnLevel {
-			r.log.SetLevel(logrus.WarnLevel)
-			r.log.Warn("Log level changed to Warn")
-		}
-	case "error":
-		if currentLogLevel != logrus.ErrorLevel {
-			r.log.SetLevel(logrus.ErrorLevel)
-			r.log.Error("Log level changed to Error")
-		}
-	case "info":
-		if currentLogLevel != logrus.InfoLevel {
-			r.log.SetLevel(logrus.InfoLevel)
-			r.log.Info("Log level changed to Info")
-		}
-	case "debug":
-		if currentLogLevel != logrus.DebugLevel {
-			r.log.SetLevel(logrus.DebugLevel)
-			r.log.Debug("Log level changed to Debug")
-		}
-	default:
-		r.log.Debugf("Unknown log level: %s", logLevelString)
-	}
-}
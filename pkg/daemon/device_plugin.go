@@ -0,0 +1,542 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/types"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// devicePluginCheckInterval is the interval of checking whether the kubelet restarted and plugins have to register again
+	devicePluginCheckInterval = 5 * time.Second
+	devicePluginDialTimeout   = 5 * time.Second
+	vfioContainerDevice       = "/dev/vfio/vfio"
+)
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// pluginDevice is an API version agnostic representation of a VF served by the device plugin
+type pluginDevice struct {
+	pciAddress string
+	pfAddress  string
+	vendorID   string
+	deviceID   string
+	driver     string
+	iommuGroup string
+	numaNode   *int
+}
+
+// pluginResource groups VFs served as one extended resource
+type pluginResource struct {
+	name      string
+	vfioToken string
+	devices   []pluginDevice
+}
+
+// DevicePlugin serves VFs of the node config inventories to the kubelet, one extended resource per
+// resource of sriovdp-config, without the need to restart anything when the inventory changes
+type DevicePlugin struct {
+	client.Client
+	log           *logrus.Logger
+	nodeNameRef   types.NamespacedName
+	dir           string
+	kubeletSocket string
+
+	mu sync.Mutex
+	// running is set while the plugin is started; resources are registered only then
+	running bool
+	// devices of every accelerator family, key: fec or vrb
+	devices map[string][]pluginDevice
+	// readiness of VFs reported by vf_status telemetry, key: PCI address of VF
+	readiness map[string]bool
+	servers   map[string]*resourceServer
+}
+
+func NewDevicePlugin(c client.Client, log *logrus.Logger, nnr types.NamespacedName) *DevicePlugin {
+	return &DevicePlugin{
+		Client:        c,
+		log:           log,
+		nodeNameRef:   nnr,
+		dir:           pluginapi.DevicePluginPath,
+		kubeletSocket: pluginapi.KubeletSocket,
+		devices:       map[string][]pluginDevice{},
+		readiness:     map[string]bool{},
+		servers:       map[string]*resourceServer{},
+	}
+}
+
+/*****************************************************************************
+ * Method: DevicePlugin::Start
+ * Description: Serves resources until the context is done; follows changes
+ * 		of sriovdp-config and registers resources again when the kubelet
+ * 		restarts and removes sockets of plugins
+ ****************************************************************************/
+func (p *DevicePlugin) Start(ctx context.Context) error {
+	p.mu.Lock()
+	p.running = true
+	p.sync()
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(devicePluginCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.running = false
+			for _, server := range p.servers {
+				server.stop()
+			}
+			return nil
+		case <-ticker.C:
+			p.mu.Lock()
+			// node specific config is generated by the operator once VFs are known, follow its changes
+			p.sync()
+			for _, server := range p.servers {
+				if !server.isRegistered() {
+					p.log.WithField("resource", server.resourceName).Info("registering device plugin again")
+					server.stop()
+					if err := server.start(); err != nil {
+						p.log.WithError(err).WithField("resource", server.resourceName).Error("failed to start device plugin")
+					}
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// NeedLeaderElection makes the plugin run on every node, the daemon does not elect leaders
+func (p *DevicePlugin) NeedLeaderElection() bool {
+	return false
+}
+
+// Refresh reloads sriovdp-config; it replaces the restart of sriov-network-device-plugin
+func (p *DevicePlugin) Refresh() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sync()
+	return nil
+}
+
+// UpdateFecDevices serves VFs of the FEC inventory; nil plugin does nothing
+func (p *DevicePlugin) UpdateFecDevices(inventory *fec.NodeInventory) {
+	if p == nil {
+		return
+	}
+	var devices []pluginDevice
+	for _, acc := range inventory.SriovAccelerators {
+		for _, vf := range acc.VFs {
+			devices = append(devices, pluginDevice{
+				pciAddress: vf.PCIAddress,
+				pfAddress:  acc.PCIAddress,
+				vendorID:   acc.VendorID,
+				deviceID:   vf.DeviceID,
+				driver:     vf.Driver,
+				iommuGroup: vf.IOMMUGroup,
+				numaNode:   acc.NUMANode,
+			})
+		}
+	}
+	p.updateDevices("fec", devices)
+}
+
+// UpdateVrbDevices serves VFs of the VRB inventory; nil plugin does nothing
+func (p *DevicePlugin) UpdateVrbDevices(inventory *vrbv1.NodeInventory) {
+	if p == nil {
+		return
+	}
+	var devices []pluginDevice
+	for _, acc := range inventory.SriovAccelerators {
+		for _, vf := range acc.VFs {
+			devices = append(devices, pluginDevice{
+				pciAddress: vf.PCIAddress,
+				pfAddress:  acc.PCIAddress,
+				vendorID:   acc.VendorID,
+				deviceID:   vf.DeviceID,
+				driver:     vf.Driver,
+				iommuGroup: vf.IOMMUGroup,
+				numaNode:   acc.NUMANode,
+			})
+		}
+	}
+	p.updateDevices("vrb", devices)
+}
+
+func (p *DevicePlugin) updateDevices(family string, devices []pluginDevice) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if reflect.DeepEqual(p.devices[family], devices) {
+		return
+	}
+	p.devices[family] = devices
+	p.sync()
+}
+
+// SetVfReadiness marks VFs which are not configured or active according to vf_status telemetry as unhealthy
+func (p *DevicePlugin) SetVfReadiness(readiness map[string]bool) {
+	if p == nil || len(readiness) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := false
+	for pciAddress, ready := range readiness {
+		if previous, ok := p.readiness[pciAddress]; !ok || previous != ready {
+			p.readiness[pciAddress] = ready
+			changed = true
+		}
+	}
+	if changed {
+		for _, server := range p.servers {
+			server.setReadiness(p.readiness)
+		}
+	}
+}
+
+// sync serves devices according to sriovdp-config; resources which do not match any device anymore are served
+// with no devices so that the kubelet drops their capacity. Has to be called with the lock held
+func (p *DevicePlugin) sync() {
+	if !p.running {
+		return
+	}
+	config, err := readDevicePluginConfig(p.Client, p.nodeNameRef)
+	if err != nil {
+		p.log.WithError(err).Error("failed to read device plugin config, devices are not updated")
+		return
+	}
+
+	var devices []pluginDevice
+	for _, family := range []string{"fec", "vrb"} {
+		devices = append(devices, p.devices[family]...)
+	}
+	resources := pluginResources(config, devices)
+	for name := range p.servers {
+		if _, ok := resources[name]; !ok {
+			resources[name] = &pluginResource{name: name}
+		}
+	}
+
+	for name, resource := range resources {
+		server, ok := p.servers[name]
+		if !ok {
+			server = newResourceServer(p.log, name, p.dir, p.kubeletSocket)
+			p.servers[name] = server
+			if err := server.start(); err != nil {
+				p.log.WithError(err).WithField("resource", name).Error("failed to start device plugin, will retry")
+			}
+		}
+		server.update(resource.devices, resource.vfioToken, p.readiness)
+	}
+}
+
+// pluginResources assigns every device to the first resource of sriovdp-config selecting it
+func pluginResources(config deviceplugin.Config, devices []pluginDevice) map[string]*pluginResource {
+	resources := map[string]*pluginResource{}
+	for _, device := range devices {
+		for _, resource := range config.ResourceList {
+			if !resourceServesVF(resource, device) {
+				continue
+			}
+			name := resource.FullName()
+			if _, ok := resources[name]; !ok {
+				resources[name] = &pluginResource{name: name, vfioToken: resource.VfioToken()}
+			}
+			resources[name].devices = append(resources[name].devices, device)
+			break
+		}
+	}
+	return resources
+}
+
+func resourceServesVF(resource deviceplugin.Resource, device pluginDevice) bool {
+	if !containsFold(resource.Selectors.Devices, device.deviceID) {
+		return false
+	}
+	if len(resource.Selectors.Vendors) > 0 && !containsFold(resource.Selectors.Vendors, device.vendorID) {
+		return false
+	}
+	if len(resource.Selectors.Drivers) > 0 && !containsFold(resource.Selectors.Drivers, device.driver) {
+		return false
+	}
+	if len(resource.Selectors.PciAddresses) > 0 && !containsFold(resource.Selectors.PciAddresses, device.pciAddress) {
+		return false
+	}
+	if pfAddr := resource.PFAddress(); pfAddr != "" && pfAddr != device.pfAddress {
+		return false
+	}
+	return true
+}
+
+// resourceServer implements the device plugin API of the kubelet for one extended resource
+type resourceServer struct {
+	log           *logrus.Logger
+	resourceName  string
+	socket        string
+	kubeletSocket string
+
+	mu        sync.Mutex
+	devices   map[string]pluginDevice
+	vfioToken string
+	readiness map[string]bool
+	// changed is closed and replaced whenever the devices change to wake up ListAndWatch streams
+	changed    chan struct{}
+	server     *grpc.Server
+	registered bool
+}
+
+func newResourceServer(log *logrus.Logger, resourceName, dir, kubeletSocket string) *resourceServer {
+	return &resourceServer{
+		log:           log,
+		resourceName:  resourceName,
+		socket:        filepath.Join(dir, "sriov-fec-"+strings.ReplaceAll(resourceName, "/", "_")+".sock"),
+		kubeletSocket: kubeletSocket,
+		devices:       map[string]pluginDevice{},
+		readiness:     map[string]bool{},
+		changed:       make(chan struct{}),
+	}
+}
+
+func (s *resourceServer) start() error {
+	if err := os.Remove(s.socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket %s: %v", s.socket, err)
+	}
+	listener, err := net.Listen("unix", s.socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.socket, err)
+	}
+	server := grpc.NewServer()
+	pluginapi.RegisterDevicePluginServer(server, s)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			s.log.WithError(err).WithField("resource", s.resourceName).Info("device plugin server stopped")
+		}
+	}()
+
+	s.mu.Lock()
+	s.server = server
+	s.mu.Unlock()
+
+	if err := s.register(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.registered = true
+	s.mu.Unlock()
+	s.log.WithField("resource", s.resourceName).Info("device plugin registered")
+	return nil
+}
+
+func (s *resourceServer) register() error {
+	ctx, cancel := context.WithTimeout(context.Background(), devicePluginDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "unix://"+s.kubeletSocket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return fmt.Errorf("failed to connect to kubelet: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = pluginapi.NewRegistrationClient(conn).Register(ctx, &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     filepath.Base(s.socket),
+		ResourceName: s.resourceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %s: %v", s.resourceName, err)
+	}
+	return nil
+}
+
+// isRegistered is false when the registration failed or the kubelet removed the socket after its restart
+func (s *resourceServer) isRegistered() bool {
+	s.mu.Lock()
+	registered := s.registered
+	s.mu.Unlock()
+	if _, err := os.Stat(s.socket); err != nil {
+		return false
+	}
+	return registered
+}
+
+func (s *resourceServer) stop() {
+	s.mu.Lock()
+	server := s.server
+	s.server, s.registered = nil, false
+	s.mu.Unlock()
+	if server != nil {
+		server.Stop()
+	}
+	_ = os.Remove(s.socket)
+}
+
+func (s *resourceServer) update(devices []pluginDevice, vfioToken string, readiness map[string]bool) {
+	byAddress := make(map[string]pluginDevice, len(devices))
+	for _, device := range devices {
+		byAddress[device.pciAddress] = device
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if reflect.DeepEqual(s.devices, byAddress) && s.vfioToken == vfioToken && reflect.DeepEqual(s.readiness, readiness) {
+		return
+	}
+	s.devices, s.vfioToken = byAddress, vfioToken
+	s.readiness = copyReadiness(readiness)
+	s.notify()
+}
+
+func (s *resourceServer) setReadiness(readiness map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readiness = copyReadiness(readiness)
+	s.notify()
+}
+
+// notify wakes up ListAndWatch streams; has to be called with the lock held
+func (s *resourceServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func copyReadiness(readiness map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(readiness))
+	for pciAddress, ready := range readiness {
+		c[pciAddress] = ready
+	}
+	return c
+}
+
+// listDevices describes devices with their health and NUMA locality; has to be called with the lock held
+func (s *resourceServer) listDevices() *pluginapi.ListAndWatchResponse {
+	addresses := make([]string, 0, len(s.devices))
+	for pciAddress := range s.devices {
+		addresses = append(addresses, pciAddress)
+	}
+	sort.Strings(addresses)
+
+	response := &pluginapi.ListAndWatchResponse{Devices: []*pluginapi.Device{}}
+	for _, pciAddress := range addresses {
+		device := &pluginapi.Device{ID: pciAddress, Health: pluginapi.Healthy}
+		if ready, ok := s.readiness[pciAddress]; ok && !ready {
+			device.Health = pluginapi.Unhealthy
+		}
+		if numaNode := s.devices[pciAddress].numaNode; numaNode != nil {
+			device.Topology = &pluginapi.TopologyInfo{Nodes: []*pluginapi.NUMANode{{ID: int64(*numaNode)}}}
+		}
+		response.Devices = append(response.Devices, device)
+	}
+	return response
+}
+
+func (s *resourceServer) GetDevicePluginOptions(context.Context, *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	return &pluginapi.DevicePluginOptions{}, nil
+}
+
+func (s *resourceServer) ListAndWatch(_ *pluginapi.Empty, stream pluginapi.DevicePlugin_ListAndWatchServer) error {
+	for {
+		s.mu.Lock()
+		response, changed := s.listDevices(), s.changed
+		s.mu.Unlock()
+
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *resourceServer) GetPreferredAllocation(context.Context, *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
+	return &pluginapi.PreferredAllocationResponse{}, nil
+}
+
+// vfInfo is the per device entry of PCIDEVICE_<RESOURCE>_INFO, the same as sriov-network-device-plugin injects
+type vfInfo struct {
+	Generic map[string]string `json:"generic"`
+	Vfio    map[string]string `json:"vfio,omitempty"`
+	Extra   map[string]string `json:"extra,omitempty"`
+}
+
+/*****************************************************************************
+ * Method: resourceServer::Allocate
+ * Description: Injects PCI addresses, VFIO token and VFIO device nodes of
+ * 		allocated VFs into containers, the same way
+ * 		sriov-network-device-plugin does
+ ****************************************************************************/
+func (s *resourceServer) Allocate(_ context.Context, request *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	envName := "PCIDEVICE_" + invalidEnvChars.ReplaceAllString(strings.ToUpper(s.resourceName), "_")
+	response := &pluginapi.AllocateResponse{}
+	for _, containerRequest := range request.GetContainerRequests() {
+		infos := map[string]vfInfo{}
+		var deviceSpecs []*pluginapi.DeviceSpec
+		groups := map[string]bool{}
+		for _, id := range containerRequest.GetDevicesIDs() {
+			device, ok := s.devices[id]
+			if !ok {
+				return nil, fmt.Errorf("device %s is not served as %s", id, s.resourceName)
+			}
+			info := vfInfo{Generic: map[string]string{"deviceID": id}}
+			if s.vfioToken != "" {
+				info.Extra = map[string]string{"VFIO_TOKEN": s.vfioToken}
+			}
+			if strings.EqualFold(device.driver, utils.VfioPci) && device.iommuGroup != "" {
+				groupDevice := "/dev/vfio/" + device.iommuGroup
+				info.Vfio = map[string]string{"mount": vfioContainerDevice, "dev-mount": groupDevice}
+				if len(groups) == 0 {
+					deviceSpecs = append(deviceSpecs, &pluginapi.DeviceSpec{ContainerPath: vfioContainerDevice, HostPath: vfioContainerDevice, Permissions: "rw"})
+				}
+				if !groups[groupDevice] {
+					groups[groupDevice] = true
+					deviceSpecs = append(deviceSpecs, &pluginapi.DeviceSpec{ContainerPath: groupDevice, HostPath: groupDevice, Permissions: "rw"})
+				}
+			}
+			infos[id] = info
+		}
+
+		rawInfos, err := json.Marshal(infos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe devices: %v", err)
+		}
+		response.ContainerResponses = append(response.ContainerResponses, &pluginapi.ContainerAllocateResponse{
+			Envs: map[string]string{
+				envName:           strings.Join(containerRequest.GetDevicesIDs(), ","),
+				envName + "_INFO": string(rawInfos),
+			},
+			Devices: deviceSpecs,
+		})
+	}
+	return response, nil
+}
+
+func (s *resourceServer) PreStartContainer(context.Context, *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	return &pluginapi.PreStartContainerResponse{}, nil
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeKubeletRegistration struct {
+	pluginapi.UnimplementedRegistrationServer
+	requests chan *pluginapi.RegisterRequest
+}
+
+func (f *fakeKubeletRegistration) Register(_ context.Context, request *pluginapi.RegisterRequest) (*pluginapi.Empty, error) {
+	f.requests <- request
+	return &pluginapi.Empty{}, nil
+}
+
+var _ = Describe("DevicePlugin", func() {
+	const dpConfig = `{"resourceList": [
+		{"resourceName": "intel_fec_acc100", "selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"]},
+			"additionalInfo": {"*": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510"}}},
+		{"resourceName": "intel_fec_pinned", "resourcePrefix": "example.com", "selectors": {"devices": ["0d5d"]},
+			"additionalInfo": {"*": {"PF_PCI_ADDR": "0000:cc:00.0"}}}
+	]}`
+
+	numaNode := 1
+	vf := func(pf, pci, driver, group string) pluginDevice {
+		return pluginDevice{pciAddress: pci, pfAddress: pf, vendorID: "8086", deviceID: "0d5d", driver: driver, iommuGroup: group, numaNode: &numaNode}
+	}
+
+	It("should assign VFs to the first resource selecting them", func() {
+		config, err := deviceplugin.Parse(dpConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		resources := pluginResources(config, []pluginDevice{
+			vf("0000:aa:00.0", "0000:ab:00.0", utils.VfioPci, "10"),
+			vf("0000:cc:00.0", "0000:cd:00.0", utils.IgbUio, ""),
+			vf("0000:dd:00.0", "0000:de:00.0", utils.IgbUio, ""),
+		})
+		Expect(resources).To(HaveLen(2))
+		Expect(resources["intel.com/intel_fec_acc100"].vfioToken).To(Equal("02bddbbf-bbb0-4d79-886b-91bad3fbb510"))
+		Expect(resources["intel.com/intel_fec_acc100"].devices).To(ConsistOf(vf("0000:aa:00.0", "0000:ab:00.0", utils.VfioPci, "10")))
+		Expect(resources["example.com/intel_fec_pinned"].devices).To(ConsistOf(vf("0000:cc:00.0", "0000:cd:00.0", utils.IgbUio, "")))
+	})
+
+	Context("resourceServer", func() {
+		var server *resourceServer
+
+		BeforeEach(func() {
+			server = newResourceServer(utils.NewLogger(), "intel.com/intel_fec_acc100", os.TempDir(), "")
+			server.update([]pluginDevice{
+				vf("0000:aa:00.0", "0000:ab:00.0", utils.VfioPci, "10"),
+				vf("0000:aa:00.0", "0000:ab:00.1", utils.VfioPci, "11"),
+			}, "02bddbbf-bbb0-4d79-886b-91bad3fbb510", map[string]bool{"0000:ab:00.1": false})
+		})
+
+		It("should list devices with their health and NUMA node", func() {
+			topology := &pluginapi.TopologyInfo{Nodes: []*pluginapi.NUMANode{{ID: 1}}}
+			Expect(server.listDevices().Devices).To(Equal([]*pluginapi.Device{
+				{ID: "0000:ab:00.0", Health: pluginapi.Healthy, Topology: topology},
+				{ID: "0000:ab:00.1", Health: pluginapi.Unhealthy, Topology: topology},
+			}))
+		})
+
+		It("should inject addresses, VFIO token and VFIO devices of allocated VFs", func() {
+			response, err := server.Allocate(context.TODO(), &pluginapi.AllocateRequest{ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"0000:ab:00.0", "0000:ab:00.1"}},
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.ContainerResponses).To(HaveLen(1))
+
+			container := response.ContainerResponses[0]
+			Expect(container.Envs).To(HaveKeyWithValue("PCIDEVICE_INTEL_COM_INTEL_FEC_ACC100", "0000:ab:00.0,0000:ab:00.1"))
+			Expect(container.Envs).To(HaveKey("PCIDEVICE_INTEL_COM_INTEL_FEC_ACC100_INFO"))
+			Expect(container.Envs["PCIDEVICE_INTEL_COM_INTEL_FEC_ACC100_INFO"]).To(MatchJSON(`{
+				"0000:ab:00.0": {"generic": {"deviceID": "0000:ab:00.0"}, "vfio": {"mount": "/dev/vfio/vfio", "dev-mount": "/dev/vfio/10"},
+					"extra": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510"}},
+				"0000:ab:00.1": {"generic": {"deviceID": "0000:ab:00.1"}, "vfio": {"mount": "/dev/vfio/vfio", "dev-mount": "/dev/vfio/11"},
+					"extra": {"VFIO_TOKEN": "02bddbbf-bbb0-4d79-886b-91bad3fbb510"}}
+			}`))
+			Expect(container.Devices).To(Equal([]*pluginapi.DeviceSpec{
+				{ContainerPath: "/dev/vfio/vfio", HostPath: "/dev/vfio/vfio", Permissions: "rw"},
+				{ContainerPath: "/dev/vfio/10", HostPath: "/dev/vfio/10", Permissions: "rw"},
+				{ContainerPath: "/dev/vfio/11", HostPath: "/dev/vfio/11", Permissions: "rw"},
+			}))
+		})
+
+		It("should reject devices which are not served", func() {
+			_, err := server.Allocate(context.TODO(), &pluginapi.AllocateRequest{ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"0000:ff:00.0"}},
+			}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("serving the kubelet", func() {
+		var (
+			dir     string
+			kubelet *grpc.Server
+			fakeReg *fakeKubeletRegistration
+			plugin  *DevicePlugin
+			cancel  context.CancelFunc
+			stopped chan struct{}
+		)
+
+		serveKubelet := func() {
+			listener, err := net.Listen("unix", filepath.Join(dir, "kubelet.sock"))
+			Expect(err).ToNot(HaveOccurred())
+			kubelet = grpc.NewServer()
+			pluginapi.RegisterRegistrationServer(kubelet, fakeReg)
+			go func() { _ = kubelet.Serve(listener) }()
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "device-plugins")
+			Expect(err).ToNot(HaveOccurred())
+			fakeReg = &fakeKubeletRegistration{requests: make(chan *pluginapi.RegisterRequest, 10)}
+			serveKubelet()
+
+			nodeNameRef := types.NamespacedName{Name: "worker", Namespace: "default"}
+			c := fake.NewClientBuilder().WithObjects(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "sriovdp-config", Namespace: nodeNameRef.Namespace},
+				Data:       map[string]string{"config.json": dpConfig},
+			}).Build()
+			plugin = NewDevicePlugin(c, utils.NewLogger(), nodeNameRef)
+			plugin.dir, plugin.kubeletSocket = dir, filepath.Join(dir, "kubelet.sock")
+
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			stopped = make(chan struct{})
+			go func() {
+				defer close(stopped)
+				_ = plugin.Start(ctx)
+			}()
+		})
+
+		AfterEach(func() {
+			cancel()
+			Eventually(stopped, 5*time.Second).Should(BeClosed())
+			kubelet.Stop()
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should register resources and stream changes of devices", func() {
+			plugin.UpdateFecDevices(&fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{{
+				VendorID: "8086", PCIAddress: "0000:aa:00.0", NUMANode: &numaNode,
+				VFs: []fec.VF{{PCIAddress: "0000:ab:00.0", Driver: utils.VfioPci, DeviceID: "0d5d", IOMMUGroup: "10"}},
+			}}})
+
+			var request *pluginapi.RegisterRequest
+			Eventually(fakeReg.requests, 5*time.Second).Should(Receive(&request))
+			Expect(request.ResourceName).To(Equal("intel.com/intel_fec_acc100"))
+			Expect(request.Version).To(Equal(pluginapi.Version))
+
+			conn, err := grpc.Dial("unix://"+filepath.Join(dir, request.Endpoint), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+			stream, err := pluginapi.NewDevicePluginClient(conn).ListAndWatch(context.TODO(), &pluginapi.Empty{})
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Devices).To(HaveLen(1))
+			Expect(response.Devices[0].Health).To(Equal(pluginapi.Healthy))
+
+			plugin.SetVfReadiness(map[string]bool{"0000:ab:00.0": false})
+			response, err = stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Devices[0].Health).To(Equal(pluginapi.Unhealthy))
+
+			plugin.UpdateFecDevices(&fec.NodeInventory{})
+			response, err = stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Devices).To(BeEmpty())
+		})
+
+		It("should register again when the kubelet removes the socket", func() {
+			plugin.UpdateFecDevices(&fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{{
+				VendorID: "8086", PCIAddress: "0000:aa:00.0",
+				VFs: []fec.VF{{PCIAddress: "0000:ab:00.0", Driver: utils.VfioPci, DeviceID: "0d5d"}},
+			}}})
+			var request *pluginapi.RegisterRequest
+			Eventually(fakeReg.requests, 5*time.Second).Should(Receive(&request))
+
+			Expect(os.Remove(filepath.Join(dir, request.Endpoint))).To(Succeed())
+			Eventually(fakeReg.requests, 3*devicePluginCheckInterval).Should(Receive(&request))
+			Expect(filepath.Join(dir, request.Endpoint)).To(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/deviceplugin"
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	drainPolicyFull                     = "Full"
+	drainPolicyAcceleratorConsumersOnly = "AcceleratorConsumersOnly"
+)
+
+var (
+	getVFDeviceID = utils.GetVFDeviceID
+	findVFs       = utils.FindVFs
+)
+
+/*****************************************************************************
+ * Method: drainOptionsFor
+ * Description: Translates the effective drain policy and drain settings of
+ * 		the node config into drain options. For AcceleratorConsumersOnly policy
+ * 		the resource names served from the PFs being reconfigured are resolved
+ * 		from sriovdp-config; an error is returned when they cannot be
+ * 		resolved, so that the reconfiguration is retried rather than
+ * 		unrelated workloads are evicted.
+ ****************************************************************************/
+func drainOptionsFor(c client.Client, log *logrus.Logger, nodeNameRef types.NamespacedName, policy string, settings drainSettings, deviceUpdateRequired map[string]bool) (drainhelper.DrainOptions, error) {
+	opts, err := drainOptionsForPolicy(c, log, nodeNameRef, policy, deviceUpdateRequired)
+	if err != nil {
+		return opts, err
+	}
+	if err := settings.apply(&opts); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func drainOptionsForPolicy(c client.Client, log *logrus.Logger, nodeNameRef types.NamespacedName, policy string, deviceUpdateRequired map[string]bool) (drainhelper.DrainOptions, error) {
+	switch policy {
+	case drainPolicyFull:
+		return drainhelper.DrainOptions{Drain: true}, nil
+	case drainPolicyAcceleratorConsumersOnly:
+		pfs := pfsRequiringUpdate(deviceUpdateRequired)
+
+		resourceNames, err := acceleratorResourceNames(c, nodeNameRef, pfs)
+		if err != nil {
+			// consumers cannot be told apart, e.g. sriovdp-config was deleted; unrelated workloads must not be evicted
+			log.WithError(err).WithField("pfs", pfs).Error("failed to resolve accelerator resources")
+			return drainhelper.DrainOptions{}, fmt.Errorf("failed to resolve accelerator resources for %s drain policy: %w", policy, err)
+		}
+		log.WithFields(logrus.Fields{"pfs": pfs, "resourceNames": resourceNames}).Info("resolved accelerator resources to be drained")
+		return drainhelper.DrainOptions{Drain: true, ConsumersOnly: true, ResourceNames: resourceNames}, nil
+	default:
+		return drainhelper.DrainOptions{}, nil
+	}
+}
+
+// pfsRequiringUpdate returns sorted PCI addresses of PFs which are going to be reconfigured
+func pfsRequiringUpdate(deviceUpdateRequired map[string]bool) []string {
+	var pfs []string
+	for pf, required := range deviceUpdateRequired {
+		if required {
+			pfs = append(pfs, pf)
+		}
+	}
+	sort.Strings(pfs)
+	return pfs
+}
+
+/*****************************************************************************
+ * Method: acceleratorResourceNames
+ * Description: Returns fully qualified names of sriovdp-config resources which
+ * 		serve VFs of any of the given PFs
+ ****************************************************************************/
+func acceleratorResourceNames(c client.Client, nodeNameRef types.NamespacedName, pfs []string) ([]string, error) {
+	if len(pfs) == 0 {
+		return nil, nil
+	}
+
+	config, err := readDevicePluginConfig(c, nodeNameRef)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, pf := range pfs {
+		vfDeviceID, err := getVFDeviceID(pf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VF device ID of %s: %w", pf, err)
+		}
+		vfs, err := findVFs(pf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find VFs of %s: %w", pf, err)
+		}
+
+		for _, resource := range config.ResourceList {
+			if resourceServesPF(resource, pf, vfDeviceID, vfs) {
+				names[resource.FullName()] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// readDevicePluginConfig reads the node specific or the common config of sriovdp-config
+func readDevicePluginConfig(c client.Client, nodeNameRef types.NamespacedName) (deviceplugin.Config, error) {
+	cm := &v1.ConfigMap{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: deviceplugin.ConfigMapName, Namespace: nodeNameRef.Namespace}, cm); err != nil {
+		return deviceplugin.Config{}, fmt.Errorf("failed to get %s: %w", deviceplugin.ConfigMapName, err)
+	}
+	return deviceplugin.ForNode(cm, nodeNameRef.Name)
+}
+
+func resourceServesPF(resource deviceplugin.Resource, pf, vfDeviceID string, vfs []string) bool {
+	if !containsFold(resource.Selectors.Devices, vfDeviceID) {
+		return false
+	}
+
+	if pfAddr := resource.PFAddress(); pfAddr != "" && pfAddr != pf {
+		return false
+	}
+
+	if len(resource.Selectors.PciAddresses) == 0 {
+		return true
+	}
+	for _, vf := range vfs {
+		if containsFold(resource.Selectors.PciAddresses, vf) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// drainSettings is an API version agnostic representation of DrainSettings
+type drainSettings struct {
+	timeoutSeconds              *int64
+	gracePeriodSeconds          *int
+	respectPodDisruptionBudgets *bool
+	excludedPodSelector         *metav1.LabelSelector
+	backoffSteps                int
+	backoffIntervalSeconds      int64
+	backoffFactor               int
+	leaseDurationSeconds        int64
+	leaseRenewDeadlineSeconds   int64
+	leaseRetryPeriodSeconds     int64
+}
+
+func fecDrainSettings(s *fec.DrainSettings) drainSettings {
+	if s == nil {
+		return drainSettings{}
+	}
+	ds := drainSettings{
+		timeoutSeconds:              s.TimeoutSeconds,
+		gracePeriodSeconds:          s.GracePeriodSeconds,
+		respectPodDisruptionBudgets: s.RespectPodDisruptionBudgets,
+		excludedPodSelector:         s.ExcludedPodSelector,
+	}
+	if b := s.RetryBackoff; b != nil {
+		ds.backoffSteps, ds.backoffIntervalSeconds, ds.backoffFactor = b.Steps, b.InitialIntervalSeconds, b.Factor
+	}
+	if l := s.Lease; l != nil {
+		ds.leaseDurationSeconds, ds.leaseRenewDeadlineSeconds, ds.leaseRetryPeriodSeconds = l.DurationSeconds, l.RenewDeadlineSeconds, l.RetryPeriodSeconds
+	}
+	return ds
+}
+
+func vrbDrainSettings(s *vrbv1.DrainSettings) drainSettings {
+	if s == nil {
+		return drainSettings{}
+	}
+	ds := drainSettings{
+		timeoutSeconds:              s.TimeoutSeconds,
+		gracePeriodSeconds:          s.GracePeriodSeconds,
+		respectPodDisruptionBudgets: s.RespectPodDisruptionBudgets,
+		excludedPodSelector:         s.ExcludedPodSelector,
+	}
+	if b := s.RetryBackoff; b != nil {
+		ds.backoffSteps, ds.backoffIntervalSeconds, ds.backoffFactor = b.Steps, b.InitialIntervalSeconds, b.Factor
+	}
+	if l := s.Lease; l != nil {
+		ds.leaseDurationSeconds, ds.leaseRenewDeadlineSeconds, ds.leaseRetryPeriodSeconds = l.DurationSeconds, l.RenewDeadlineSeconds, l.RetryPeriodSeconds
+	}
+	return ds
+}
+
+func (s drainSettings) apply(opts *drainhelper.DrainOptions) error {
+	if s.timeoutSeconds != nil {
+		timeout := time.Duration(*s.timeoutSeconds) * time.Second
+		opts.Timeout = &timeout
+	}
+	opts.GracePeriodSeconds = s.gracePeriodSeconds
+	opts.DeleteOnEvictionFailure = s.respectPodDisruptionBudgets != nil && !*s.respectPodDisruptionBudgets
+	if s.excludedPodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(s.excludedPodSelector)
+		if err != nil {
+			return fmt.Errorf("invalid excludedPodSelector: %w", err)
+		}
+		opts.ExcludedPods = selector
+	}
+	if s.backoffSteps > 0 {
+		opts.Backoff = &wait.Backoff{
+			Steps:    s.backoffSteps,
+			Duration: time.Duration(s.backoffIntervalSeconds) * time.Second,
+			Factor:   float64(s.backoffFactor),
+		}
+	}
+	if s.leaseDurationSeconds > 0 {
+		opts.Lease = &drainhelper.LeaseTimings{
+			Duration:      time.Duration(s.leaseDurationSeconds) * time.Second,
+			RenewDeadline: time.Duration(s.leaseRenewDeadlineSeconds) * time.Second,
+			RetryPeriod:   time.Duration(s.leaseRetryPeriodSeconds) * time.Second,
+		}
+	}
+	return nil
+}
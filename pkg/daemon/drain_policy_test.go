@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("drainOptionsFor", func() {
+	const dpConfig = `{"resourceList": [
+		{"resourceName": "intel_fec_acc100", "selectors": {"vendors": ["8086"], "devices": ["0d5d"], "drivers": ["vfio-pci"]}},
+		{"resourceName": "intel_vrb_vrb1", "resourcePrefix": "example.com", "selectors": {"devices": ["57c1"]}},
+		{"resourceName": "intel_fec_pinned", "selectors": {"devices": ["0d5d"], "pciAddresses": ["0000:bb:00.1"]}},
+		{"resourceName": "intel_fec_other_pf", "selectors": {"devices": ["0d5d"]}, "additionalInfo": {"*": {"PF_PCI_ADDR": "0000:cc:00.0"}}}
+	]}`
+
+	nodeNameRef := types.NamespacedName{Name: "worker", Namespace: "testNamespace"}
+
+	BeforeEach(func() {
+		getVFDeviceID = func(string) (string, error) { return "0d5d", nil }
+		findVFs = func(string) ([]string, error) { return []string{"0000:aa:00.1", "0000:aa:00.2"}, nil }
+	})
+
+	AfterEach(func() {
+		getVFDeviceID = utils.GetVFDeviceID
+		findVFs = utils.FindVFs
+	})
+
+	newConfigMap := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "sriovdp-config", Namespace: nodeNameRef.Namespace},
+			Data:       data,
+		}
+	}
+
+	It("should drain whole node for Full policy", func() {
+		opts, err := drainOptionsFor(fake.NewClientBuilder().Build(), utils.NewLogger(), nodeNameRef, "Full", drainSettings{}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts).To(Equal(drainhelper.DrainOptions{Drain: true}))
+	})
+
+	It("should not drain for Never policy", func() {
+		opts, err := drainOptionsFor(fake.NewClientBuilder().Build(), utils.NewLogger(), nodeNameRef, "Never", drainSettings{}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.Drain).To(BeFalse())
+	})
+
+	It("should resolve resources served from reconfigured PFs", func() {
+		c := fake.NewClientBuilder().WithObjects(newConfigMap(map[string]string{"config.json": dpConfig})).Build()
+		opts, err := drainOptionsFor(c, utils.NewLogger(), nodeNameRef, "AcceleratorConsumersOnly", drainSettings{},
+			map[string]bool{"0000:aa:00.0": true, "0000:dd:00.0": false})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.Drain).To(BeTrue())
+		Expect(opts.ConsumersOnly).To(BeTrue())
+		Expect(opts.ResourceNames).To(Equal([]string{"intel.com/intel_fec_acc100"}))
+	})
+
+	It("should prefer node specific device plugin config", func() {
+		c := fake.NewClientBuilder().WithObjects(newConfigMap(map[string]string{
+			"config.json":        dpConfig,
+			"config_worker.json": `{"resourceList": [{"resourceName": "node_specific", "selectors": {"devices": ["0d5d"]}}]}`,
+		})).Build()
+		opts, err := drainOptionsFor(c, utils.NewLogger(), nodeNameRef, "AcceleratorConsumersOnly", drainSettings{}, map[string]bool{"0000:aa:00.0": true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.ResourceNames).To(Equal([]string{"intel.com/node_specific"}))
+	})
+
+	It("should fail when device plugin config is missing", func() {
+		_, err := drainOptionsFor(fake.NewClientBuilder().Build(), utils.NewLogger(), nodeNameRef, "AcceleratorConsumersOnly", drainSettings{}, map[string]bool{"0000:aa:00.0": true})
+		Expect(err).To(MatchError(ContainSubstring("failed to get sriovdp-config")))
+	})
+
+	It("should fail when device plugin config is malformed", func() {
+		c := fake.NewClientBuilder().WithObjects(newConfigMap(map[string]string{"config.json": "{"})).Build()
+		_, err := drainOptionsFor(c, utils.NewLogger(), nodeNameRef, "AcceleratorConsumersOnly", drainSettings{}, map[string]bool{"0000:aa:00.0": true})
+		Expect(err).To(MatchError(ContainSubstring("failed to resolve accelerator resources for AcceleratorConsumersOnly drain policy")))
+	})
+
+	It("should apply drain settings", func() {
+		timeout, gracePeriod, respectPDB := int64(30), 5, false
+		settings := fecDrainSettings(&fec.DrainSettings{
+			TimeoutSeconds:              &timeout,
+			GracePeriodSeconds:          &gracePeriod,
+			RespectPodDisruptionBudgets: &respectPDB,
+			ExcludedPodSelector:         &metav1.LabelSelector{MatchLabels: map[string]string{"app": "critical"}},
+			RetryBackoff:                &fec.DrainRetryBackoff{Steps: 3, InitialIntervalSeconds: 10, Factor: 2},
+			Lease:                       &fec.LeaseSettings{DurationSeconds: 60, RenewDeadlineSeconds: 40, RetryPeriodSeconds: 10},
+		})
+
+		opts, err := drainOptionsFor(fake.NewClientBuilder().Build(), utils.NewLogger(), nodeNameRef, "Full", settings, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.Drain).To(BeTrue())
+		Expect(*opts.Timeout).To(Equal(30 * time.Second))
+		Expect(*opts.GracePeriodSeconds).To(Equal(5))
+		Expect(opts.DeleteOnEvictionFailure).To(BeTrue())
+		Expect(opts.ExcludedPods.String()).To(Equal("app=critical"))
+		Expect(*opts.Backoff).To(Equal(wait.Backoff{Steps: 3, Duration: 10 * time.Second, Factor: 2}))
+		Expect(*opts.Lease).To(Equal(drainhelper.LeaseTimings{Duration: time.Minute, RenewDeadline: 40 * time.Second, RetryPeriod: 10 * time.Second}))
+	})
+
+	It("should keep defaults when drain settings are not set", func() {
+		opts, err := drainOptionsFor(fake.NewClientBuilder().Build(), utils.NewLogger(), nodeNameRef, "Full", vrbDrainSettings(nil), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts).To(Equal(drainhelper.DrainOptions{Drain: true}))
+	})
+})
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type hookPhase string
+
+const (
+	preReconfigurationHook  hookPhase = "PreReconfiguration"
+	postReconfigurationHook hookPhase = "PostReconfiguration"
+
+	hookResultSucceeded = "Succeeded"
+	hookResultFailed    = "Failed"
+
+	hookPhaseLabel           = "sriovfec.intel.com/hook-phase"
+	hookNodeLabel            = "sriovfec.intel.com/hook-node"
+	defaultHookTimeout       = 60 * time.Second
+	hookJobTTLSecondsDefault = int32(3600)
+)
+
+var (
+	hookJobPollInterval = 2 * time.Second
+	hookHTTPClient      = &http.Client{}
+)
+
+// reconfigurationHook is an API version agnostic representation of ReconfigurationHook
+type reconfigurationHook struct {
+	url           string
+	jobTemplate   *batchv1.JobTemplateSpec
+	timeout       time.Duration
+	ignoreFailure bool
+}
+
+// hookOutcome is an API version agnostic representation of HookResult
+type hookOutcome struct {
+	phase          hookPhase
+	result         string
+	message        string
+	startTime      metav1.Time
+	completionTime metav1.Time
+}
+
+// hookPayload is sent to HTTP hooks and exposed to Job hooks through environment variables
+type hookPayload struct {
+	Node         string   `json:"node"`
+	Phase        string   `json:"phase"`
+	PciAddresses []string `json:"pciAddresses"`
+}
+
+type hookRunner struct {
+	client      client.Client
+	log         *logrus.Logger
+	nodeNameRef types.NamespacedName
+}
+
+func newReconfigurationHook(url string, jobTemplate *batchv1.JobTemplateSpec, timeoutSeconds int64, failurePolicy string) *reconfigurationHook {
+	hook := &reconfigurationHook{
+		url:           url,
+		jobTemplate:   jobTemplate,
+		timeout:       defaultHookTimeout,
+		ignoreFailure: failurePolicy == string(fec.HookFailurePolicyIgnore),
+	}
+	if timeoutSeconds > 0 {
+		hook.timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	return hook
+}
+
+func fecReconfigurationHook(hooks *fec.ReconfigurationHooks, phase hookPhase) *reconfigurationHook {
+	if hooks == nil {
+		return nil
+	}
+	hook := hooks.PreReconfiguration
+	if phase == postReconfigurationHook {
+		hook = hooks.PostReconfiguration
+	}
+	if hook == nil {
+		return nil
+	}
+	return newReconfigurationHook(hook.URL, hook.JobTemplate, hook.TimeoutSeconds, string(hook.FailurePolicy))
+}
+
+func vrbReconfigurationHook(hooks *vrbv1.ReconfigurationHooks, phase hookPhase) *reconfigurationHook {
+	if hooks == nil {
+		return nil
+	}
+	hook := hooks.PreReconfiguration
+	if phase == postReconfigurationHook {
+		hook = hooks.PostReconfiguration
+	}
+	if hook == nil {
+		return nil
+	}
+	return newReconfigurationHook(hook.URL, hook.JobTemplate, hook.TimeoutSeconds, string(hook.FailurePolicy))
+}
+
+func (o hookOutcome) fecHookResult() fec.HookResult {
+	return fec.HookResult{Phase: string(o.phase), Result: o.result, Message: o.message, StartTime: o.startTime, CompletionTime: o.completionTime}
+}
+
+func (o hookOutcome) vrbHookResult() vrbv1.HookResult {
+	return vrbv1.HookResult{Phase: string(o.phase), Result: o.result, Message: o.message, StartTime: o.startTime, CompletionTime: o.completionTime}
+}
+
+/*****************************************************************************
+ * Method: hookRunner::run
+ * Description: Invokes the hook and waits for its completion.
+ * Returns the outcome of the invocation and an error if the hook failed and
+ * its failure policy requires the reconfiguration to be aborted.
+ ****************************************************************************/
+func (h *hookRunner) run(ctx context.Context, phase hookPhase, hook *reconfigurationHook, pciAddresses []string) (hookOutcome, error) {
+	outcome := hookOutcome{phase: phase, startTime: metav1.Now()}
+	payload := hookPayload{Node: h.nodeNameRef.Name, Phase: string(phase), PciAddresses: pciAddresses}
+
+	log := h.log.WithFields(logrus.Fields{"phase": phase, "pciAddresses": pciAddresses})
+	log.Info("invoking reconfiguration hook")
+
+	ctx, cancel := context.WithTimeout(ctx, hook.timeout)
+	defer cancel()
+
+	var err error
+	if hook.jobTemplate != nil {
+		outcome.message, err = h.runJob(ctx, hook.jobTemplate, payload)
+	} else {
+		outcome.message, err = h.callURL(ctx, hook.url, payload)
+	}
+	outcome.completionTime = metav1.Now()
+
+	if err == nil {
+		outcome.result = hookResultSucceeded
+		log.WithField("message", outcome.message).Info("reconfiguration hook succeeded")
+		return outcome, nil
+	}
+
+	outcome.result = hookResultFailed
+	outcome.message = err.Error()
+	if hook.ignoreFailure {
+		log.WithError(err).Warn("reconfiguration hook failed - ignoring")
+		return outcome, nil
+	}
+	log.WithError(err).Error("reconfiguration hook failed")
+	return outcome, fmt.Errorf("%s hook failed: %w", phase, err)
+}
+
+func (h *hookRunner) callURL(ctx context.Context, url string, payload hookPayload) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hookHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s responded with %s", url, resp.Status)
+	}
+	return fmt.Sprintf("%s responded with %s", url, resp.Status), nil
+}
+
+func (h *hookRunner) runJob(ctx context.Context, template *batchv1.JobTemplateSpec, payload hookPayload) (string, error) {
+	job := &batchv1.Job{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	job.Name = ""
+	job.GenerateName = fmt.Sprintf("%s-%s-", h.nodeNameRef.Name, strings.ToLower(payload.Phase))
+	job.Namespace = h.nodeNameRef.Namespace
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[hookPhaseLabel] = payload.Phase
+	job.Labels[hookNodeLabel] = payload.Node
+	if job.Spec.TTLSecondsAfterFinished == nil {
+		ttl := hookJobTTLSecondsDefault
+		job.Spec.TTLSecondsAfterFinished = &ttl
+	}
+
+	podSpec := &job.Spec.Template.Spec
+	// node is cordoned during the reconfiguration, so the pod is bound to the node directly
+	podSpec.NodeName = payload.Node
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	env := []corev1.EnvVar{
+		{Name: "NODE_NAME", Value: payload.Node},
+		{Name: "HOOK_PHASE", Value: payload.Phase},
+		{Name: "PCI_ADDRESSES", Value: strings.Join(payload.PciAddresses, ",")},
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, env...)
+	}
+
+	if err := h.client.Create(ctx, job); err != nil {
+		return "", fmt.Errorf("failed to create hook job: %w", err)
+	}
+	h.log.WithField("job", job.Name).Info("hook job created")
+
+	var finished *batchv1.JobCondition
+	err := wait.PollImmediateUntil(hookJobPollInterval, func() (bool, error) {
+		current := &batchv1.Job{}
+		if err := h.client.Get(ctx, client.ObjectKeyFromObject(job), current); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		for i, c := range current.Status.Conditions {
+			if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+				finished = &current.Status.Conditions[i]
+				return true, nil
+			}
+		}
+		return false, nil
+	}, ctx.Done())
+	if err != nil {
+		h.deleteJob(job)
+		if err == wait.ErrWaitTimeout {
+			return "", fmt.Errorf("job %s did not finish in time", job.Name)
+		}
+		return "", err
+	}
+
+	if finished.Type == batchv1.JobFailed {
+		h.deleteJob(job)
+		return "", fmt.Errorf("job %s failed: %s", job.Name, finished.Message)
+	}
+	return fmt.Sprintf("job %s completed", job.Name), nil
+}
+
+// deleteJob removes the unsuccessful hook job together with its pods, so that it does not keep running on the node
+func (h *hookRunner) deleteJob(job *batchv1.Job) {
+	// context of the hook is already done when the job timed out
+	err := h.client.Delete(context.Background(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8serrors.IsNotFound(err) {
+		h.log.WithError(err).WithField("job", job.Name).Warn("failed to delete hook job")
+		return
+	}
+	h.log.WithField("job", job.Name).Info("hook job deleted")
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/drainhelper"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("hookRunner", func() {
+	var (
+		runner      hookRunner
+		fakeClient  client.Client
+		nodeNameRef = types.NamespacedName{Name: "worker", Namespace: "testNamespace"}
+		pfs         = []string{"0000:aa:00.0"}
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).ToNot(HaveOccurred())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		runner = hookRunner{client: fakeClient, log: utils.NewLogger(), nodeNameRef: nodeNameRef}
+		hookJobPollInterval = 10 * time.Millisecond
+	})
+
+	Context("HTTP hook", func() {
+		It("should succeed when endpoint responds with 2xx", func() {
+			var received hookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			hook := fecReconfigurationHook(&fec.ReconfigurationHooks{
+				PreReconfiguration: &fec.ReconfigurationHook{URL: server.URL},
+			}, preReconfigurationHook)
+
+			outcome, err := runner.run(context.TODO(), preReconfigurationHook, hook, pfs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outcome.result).To(Equal(hookResultSucceeded))
+			Expect(received).To(Equal(hookPayload{Node: "worker", Phase: "PreReconfiguration", PciAddresses: pfs}))
+		})
+
+		It("should fail when endpoint responds with error and failure policy is Fail", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			hook := fecReconfigurationHook(&fec.ReconfigurationHooks{
+				PostReconfiguration: &fec.ReconfigurationHook{URL: server.URL, FailurePolicy: fec.HookFailurePolicyFail},
+			}, postReconfigurationHook)
+
+			outcome, err := runner.run(context.TODO(), postReconfigurationHook, hook, pfs)
+			Expect(err).To(HaveOccurred())
+			Expect(outcome.result).To(Equal(hookResultFailed))
+			Expect(outcome.fecHookResult().Phase).To(Equal("PostReconfiguration"))
+		})
+
+		It("should not return error when failure policy is Ignore", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			hook := fecReconfigurationHook(&fec.ReconfigurationHooks{
+				PreReconfiguration: &fec.ReconfigurationHook{URL: server.URL, FailurePolicy: fec.HookFailurePolicyIgnore},
+			}, preReconfigurationHook)
+
+			outcome, err := runner.run(context.TODO(), preReconfigurationHook, hook, pfs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outcome.result).To(Equal(hookResultFailed))
+		})
+	})
+
+	Context("Job hook", func() {
+		template := &batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "quiesce", Image: "du-tools"}},
+			}}},
+		}
+
+		completeJobs := func(conditionType batchv1.JobConditionType) {
+			defer GinkgoRecover()
+			Eventually(func() int {
+				jobs := &batchv1.JobList{}
+				Expect(fakeClient.List(context.TODO(), jobs)).To(Succeed())
+				for i := range jobs.Items {
+					jobs.Items[i].Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+					Expect(fakeClient.Status().Update(context.TODO(), &jobs.Items[i])).To(Succeed())
+				}
+				return len(jobs.Items)
+			}, time.Second, 10*time.Millisecond).Should(Equal(1))
+		}
+
+		It("should create job bound to the node and wait for its completion", func() {
+			go completeJobs(batchv1.JobComplete)
+
+			hook := newReconfigurationHook("", template, 5, "")
+			outcome, err := runner.run(context.TODO(), preReconfigurationHook, hook, pfs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outcome.result).To(Equal(hookResultSucceeded))
+
+			jobs := &batchv1.JobList{}
+			Expect(fakeClient.List(context.TODO(), jobs)).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			podSpec := jobs.Items[0].Spec.Template.Spec
+			Expect(podSpec.NodeName).To(Equal("worker"))
+			Expect(podSpec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PCI_ADDRESSES", Value: "0000:aa:00.0"}))
+		})
+
+		It("should report failed job", func() {
+			go completeJobs(batchv1.JobFailed)
+
+			hook := newReconfigurationHook("", template, 5, "")
+			outcome, err := runner.run(context.TODO(), postReconfigurationHook, hook, pfs)
+			Expect(err).To(HaveOccurred())
+			Expect(outcome.result).To(Equal(hookResultFailed))
+
+			jobs := &batchv1.JobList{}
+			Expect(fakeClient.List(context.TODO(), jobs)).To(Succeed())
+			Expect(jobs.Items).To(BeEmpty(), "failed job is deleted")
+		})
+
+		It("should time out when job does not finish", func() {
+			hook := newReconfigurationHook("", template, 1, "")
+			_, err := runner.run(context.TODO(), preReconfigurationHook, hook, pfs)
+			Expect(err).To(MatchError(ContainSubstring("did not finish in time")))
+
+			jobs := &batchv1.JobList{}
+			Expect(fakeClient.List(context.TODO(), jobs)).To(Succeed())
+			Expect(jobs.Items).To(BeEmpty(), "unfinished job is deleted")
+		})
+	})
+})
+
+type recordingConfigurer struct {
+	steps *[]string
+}
+
+func (c recordingConfigurer) ApplySpec(fec.SriovFecNodeConfigSpec, map[string]bool) error {
+	*c.steps = append(*c.steps, "apply")
+	return nil
+}
+
+var _ = Describe("FecNodeConfigReconciler.configureNode hooks", func() {
+	var (
+		steps      []string
+		server     *httptest.Server
+		status     int
+		reconciler *FecNodeConfigReconciler
+		nodeConfig *fec.SriovFecNodeConfig
+		tainted    bool
+
+		taintedDuringRestart bool
+	)
+
+	nodeTainted := func() bool {
+		node := &corev1.Node{}
+		Expect(reconciler.Get(context.TODO(), types.NamespacedName{Name: "worker"}, node)).To(Succeed())
+		return hasReconfiguringTaint(node)
+	}
+
+	BeforeEach(func() {
+		steps, status, tainted = nil, http.StatusOK, false
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload hookPayload
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			steps = append(steps, payload.Phase)
+			w.WriteHeader(status)
+		}))
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		nodeNameRef := types.NamespacedName{Name: "worker", Namespace: "testNamespace"}
+		reconciler = &FecNodeConfigReconciler{
+			Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}}).Build(),
+			log:         utils.NewLogger(),
+			nodeNameRef: nodeNameRef,
+			drainerAndExecute: func(configurer func(ctx context.Context) bool, opts drainhelper.DrainOptions) error {
+				steps = append(steps, "lease")
+				tainted = nodeTainted()
+				if opts.BeforeDrain != nil {
+					if err := opts.BeforeDrain(context.TODO()); err != nil {
+						return err
+					}
+				}
+				steps = append(steps, "drain")
+				_ = configurer(context.TODO())
+				return nil
+			},
+			sriovfecconfigurer: recordingConfigurer{steps: &steps},
+			restartDevicePlugin: func() error {
+				steps = append(steps, "restart")
+				taintedDuringRestart = nodeTainted()
+				return nil
+			},
+		}
+		nodeConfig = &fec.SriovFecNodeConfig{Spec: fec.SriovFecNodeConfigSpec{
+			DrainPolicy: fec.DrainPolicyFull,
+			Hooks: &fec.ReconfigurationHooks{
+				PreReconfiguration:  &fec.ReconfigurationHook{URL: server.URL},
+				PostReconfiguration: &fec.ReconfigurationHook{URL: server.URL},
+			},
+		}}
+		fecDeviceUpdateRequired["0000:aa:00.0"] = true
+	})
+
+	AfterEach(func() {
+		server.Close()
+		delete(fecDeviceUpdateRequired, "0000:aa:00.0")
+	})
+
+	It("should invoke the pre hook after the lease is acquired right before the drain and the post hook after the configuration is applied", func() {
+		Expect(reconciler.configureNode(nodeConfig)).To(Succeed())
+		Expect(steps).To(Equal([]string{"lease", "PreReconfiguration", "drain", "apply", "restart", "PostReconfiguration"}))
+		Expect(nodeConfig.Status.HookResults).To(HaveLen(2))
+	})
+
+	It("should taint the node only after the lease is acquired and untaint it after the reconfiguration", func() {
+		nodeConfig.Spec.Hooks = nil
+
+		Expect(reconciler.configureNode(nodeConfig)).To(Succeed())
+		Expect(steps).To(Equal([]string{"lease", "drain", "apply", "restart"}))
+		Expect(tainted).To(BeFalse(), "node waiting for the lease must not be tainted")
+		Expect(taintedDuringRestart).To(BeTrue())
+		Expect(nodeTainted()).To(BeFalse())
+	})
+
+	It("should neither drain nor apply the configuration when the pre hook fails", func() {
+		status = http.StatusInternalServerError
+
+		Expect(reconciler.configureNode(nodeConfig)).To(MatchError(ContainSubstring("PreReconfiguration hook failed")))
+		Expect(steps).To(Equal([]string{"lease", "PreReconfiguration"}))
+		Expect(nodeConfig.Status.HookResults).To(HaveLen(1))
+		Expect(nodeConfig.Status.HookResults[0].Result).To(Equal(hookResultFailed))
+	})
+
+	It("should drain and apply the configuration when the ignored pre hook fails", func() {
+		status = http.StatusInternalServerError
+		nodeConfig.Spec.Hooks.PreReconfiguration.FailurePolicy = fec.HookFailurePolicyIgnore
+		nodeConfig.Spec.Hooks.PostReconfiguration = nil
+
+		Expect(reconciler.configureNode(nodeConfig)).To(Succeed())
+		Expect(steps).To(Equal([]string{"lease", "PreReconfiguration", "drain", "apply", "restart"}))
+	})
+})
@@ -56,6 +56,7 @@ func GetSriovInventory(log *logrus.Logger) (*sriovv2.NodeInventory, error) {
 	accelerators := &sriovv2.NodeInventory{
 		SriovAccelerators: []sriovv2.SriovAccelerator{},
 	}
+	consumers := getVfConsumers(log)
 
 	for _, device := range commonUtils.Filter(devices, isKnownDevice) {
 		if !utils.IsSriovPF(device.Address) {
@@ -73,13 +74,22 @@ func GetSriovInventory(log *logrus.Logger) (*sriovv2.NodeInventory, error) {
 			}
 		}
 
+		details := readPciDetails(device)
 		acc := sriovv2.SriovAccelerator{
-			VendorID:   device.Vendor.ID,
-			DeviceID:   device.Product.ID,
-			PCIAddress: device.Address,
-			PFDriver:   driver,
-			MaxVFs:     utils.GetSriovVFcapacity(device.Address),
-			VFs:        []sriovv2.VF{},
+			VendorID:          device.Vendor.ID,
+			DeviceID:          device.Product.ID,
+			PCIAddress:        device.Address,
+			PFDriver:          driver,
+			MaxVFs:            utils.GetSriovVFcapacity(device.Address),
+			VFs:               []sriovv2.VF{},
+			NUMANode:          details.numaNode,
+			IOMMUGroup:        details.iommuGroup,
+			Revision:          details.revision,
+			SubsystemVendorID: details.subsystemVendorID,
+			SubsystemDeviceID: details.subsystemDeviceID,
+			SerialNumber:      details.serialNumber,
+			LinkSpeed:         details.linkSpeed,
+			LinkWidth:         details.linkWidth,
 		}
 
 		vfs, err := utils.GetVFList(device.Address)
@@ -87,12 +97,25 @@ func GetSriovInventory(log *logrus.Logger) (*sriovv2.NodeInventory, error) {
 			log.WithError(err).WithField("pci", device.Address).Error("failed to get list of VFs for device")
 		}
 
+		indexes := vfIndexes(device.Address)
 		for _, vf := range vfs {
 			vfInfo := sriovv2.VF{
 				PCIAddress: vf,
+				IOMMUGroup: readIommuGroup(vf),
+			}
+			if index, ok := indexes[vf]; ok {
+				vfInfo.Index = &index
 			}
 
 			vfInfo.Driver, vfInfo.DeviceID = getVFDeviceInfo(log, pciInfo, device.Address, vf)
+			if consumer, ok := consumers[vf]; ok {
+				vfInfo.Consumer = &sriovv2.VFConsumer{
+					Namespace:    consumer.namespace,
+					Pod:          consumer.pod,
+					Container:    consumer.container,
+					ResourceName: consumer.resourceName,
+				}
+			}
 
 			acc.VFs = append(acc.VFs, vfInfo)
 		}
@@ -120,6 +143,7 @@ func VrbGetSriovInventory(log *logrus.Logger) (*vrbv1.NodeInventory, error) {
 	accelerators := &vrbv1.NodeInventory{
 		SriovAccelerators: []vrbv1.SriovAccelerator{},
 	}
+	consumers := getVfConsumers(log)
 
 	for _, device := range commonUtils.Filter(devices, VrbisKnownDevice) {
 		if !utils.IsSriovPF(device.Address) {
@@ -137,13 +161,22 @@ func VrbGetSriovInventory(log *logrus.Logger) (*vrbv1.NodeInventory, error) {
 			}
 		}
 
+		details := readPciDetails(device)
 		acc := vrbv1.SriovAccelerator{
-			VendorID:   device.Vendor.ID,
-			DeviceID:   device.Product.ID,
-			PCIAddress: device.Address,
-			PFDriver:   driver,
-			MaxVFs:     utils.GetSriovVFcapacity(device.Address),
-			VFs:        []vrbv1.VF{},
+			VendorID:          device.Vendor.ID,
+			DeviceID:          device.Product.ID,
+			PCIAddress:        device.Address,
+			PFDriver:          driver,
+			MaxVFs:            utils.GetSriovVFcapacity(device.Address),
+			VFs:               []vrbv1.VF{},
+			NUMANode:          details.numaNode,
+			IOMMUGroup:        details.iommuGroup,
+			Revision:          details.revision,
+			SubsystemVendorID: details.subsystemVendorID,
+			SubsystemDeviceID: details.subsystemDeviceID,
+			SerialNumber:      details.serialNumber,
+			LinkSpeed:         details.linkSpeed,
+			LinkWidth:         details.linkWidth,
 		}
 
 		vfs, err := utils.GetVFList(device.Address)
@@ -151,12 +184,25 @@ func VrbGetSriovInventory(log *logrus.Logger) (*vrbv1.NodeInventory, error) {
 			log.WithError(err).WithField("pci", device.Address).Error("failed to get list of VFs for device")
 		}
 
+		indexes := vfIndexes(device.Address)
 		for _, vf := range vfs {
 			vfInfo := vrbv1.VF{
 				PCIAddress: vf,
+				IOMMUGroup: readIommuGroup(vf),
+			}
+			if index, ok := indexes[vf]; ok {
+				vfInfo.Index = &index
 			}
 
 			vfInfo.Driver, vfInfo.DeviceID = getVFDeviceInfo(log, pciInfo, device.Address, vf)
+			if consumer, ok := consumers[vf]; ok {
+				vfInfo.Consumer = &vrbv1.VFConsumer{
+					Namespace:    consumer.namespace,
+					Pod:          consumer.pod,
+					Container:    consumer.container,
+					ResourceName: consumer.resourceName,
+				}
+			}
 
 			acc.VFs = append(acc.VFs, vfInfo)
 		}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"sync"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Names of loggers of the daemon whose level can be set individually
+const (
+	ReconcilerLogger        = "reconciler"
+	DrainHelperLogger       = "drainHelper"
+	TelemetryLogger         = "telemetry"
+	PfBbConfigMonitorLogger = "pfBbConfigMonitor"
+	// DefaultLogger names loggers of the daemon without a level of their own
+	DefaultLogger = "daemon"
+)
+
+// LogConfigController applies log levels and format of SriovFecOperatorConfig to loggers of the daemon
+type LogConfigController struct {
+	client.Client
+	log *logrus.Logger
+
+	mu      sync.Mutex
+	loggers map[string][]*logrus.Logger
+}
+
+func NewLogConfigController(c client.Client, logger *logrus.Logger) *LogConfigController {
+	return &LogConfigController{
+		Client: c,
+		log:    logger,
+		// package logger is shared by helpers of the daemon
+		loggers: map[string][]*logrus.Logger{DefaultLogger: {logger, log}},
+	}
+}
+
+/*****************************************************************************
+ * Method: LogConfigController::Register
+ * Description: Makes level of given loggers follow the level configured for
+ * 		the named logger
+ ****************************************************************************/
+func (c *LogConfigController) Register(name string, loggers ...*logrus.Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, log := range loggers {
+		if log != nil {
+			c.loggers[name] = append(c.loggers[name], log)
+		}
+	}
+}
+
+/*****************************************************************************
+ * Method: LogConfigController::Reconcile
+ * Description: Applies logging configuration of the SriovFecOperatorConfig;
+ * 		defaults are restored when it does not exist
+ ****************************************************************************/
+func (c *LogConfigController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	config := new(sriovv2.SriovFecOperatorConfig)
+	if err := c.Get(ctx, req.NamespacedName, config); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		config = &sriovv2.SriovFecOperatorConfig{}
+	}
+	c.apply(config.Spec)
+	return ctrl.Result{}, nil
+}
+
+func (c *LogConfigController) apply(spec sriovv2.SriovFecOperatorConfigSpec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levels := daemonLogLevels(spec.LogLevels)
+	for name, loggers := range c.loggers {
+		level, err := logrus.ParseLevel(string(levels[name]))
+		if err != nil {
+			c.log.WithError(err).WithField("logger", name).Error("invalid log level, using info")
+			level = logrus.InfoLevel
+		}
+		for _, log := range loggers {
+			if utils.ConfigureLogger(log, level, string(spec.LogFormat)) {
+				log.WithField("logger", name).WithField("logLevel", level).WithField("logFormat", spec.LogFormat).Info("logging configuration changed")
+			}
+		}
+	}
+}
+
+// daemonLogLevels returns levels of daemon loggers; loggers without a level inherit the level of the daemon
+func daemonLogLevels(levels *sriovv2.ComponentLogLevels) map[string]sriovv2.LogLevel {
+	res := map[string]sriovv2.LogLevel{}
+	daemonLevel := sriovv2.LogLevel(logrus.InfoLevel.String())
+	if levels != nil && levels.Daemon != "" {
+		daemonLevel = levels.Daemon
+	}
+	for _, name := range []string{DefaultLogger, ReconcilerLogger, DrainHelperLogger, TelemetryLogger, PfBbConfigMonitorLogger} {
+		res[name] = daemonLevel
+	}
+	if levels == nil || levels.DaemonLoggers == nil {
+		return res
+	}
+
+	for name, level := range map[string]sriovv2.LogLevel{
+		ReconcilerLogger:        levels.DaemonLoggers.Reconciler,
+		DrainHelperLogger:       levels.DaemonLoggers.DrainHelper,
+		TelemetryLogger:         levels.DaemonLoggers.Telemetry,
+		PfBbConfigMonitorLogger: levels.DaemonLoggers.PfBbConfigMonitor,
+	} {
+		if level != "" {
+			res[name] = level
+		}
+	}
+	return res
+}
+
+func (c *LogConfigController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sriovv2.SriovFecOperatorConfig{}).
+		WithEventFilter(resourceNamePredicate{
+			requiredName: sriovv2.OperatorConfigName,
+			log:          c.log,
+		}).Complete(c)
+}
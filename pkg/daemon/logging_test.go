@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+
+	v2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("LogConfigController", func() {
+	var (
+		fakeClient                client.Client
+		controller                *LogConfigController
+		daemonLog, drainLog, tLog *logrus.Logger
+	)
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: v2.OperatorConfigName, Namespace: "default"}}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v2.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		daemonLog, drainLog, tLog = utils.NewLogger(), utils.NewLogger(), utils.NewLogger()
+		controller = NewLogConfigController(fakeClient, daemonLog)
+		controller.Register(DrainHelperLogger, drainLog)
+		controller.Register(TelemetryLogger, tLog)
+	})
+
+	AfterEach(func() {
+		// package logger is shared with other tests
+		utils.ConfigureLogger(log, logrus.InfoLevel, utils.LogFormatJSON)
+	})
+
+	It("should apply per-logger levels and format", func() {
+		Expect(fakeClient.Create(context.TODO(), &v2.SriovFecOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: request.Name, Namespace: request.Namespace},
+			Spec: v2.SriovFecOperatorConfigSpec{
+				LogLevels: &v2.ComponentLogLevels{
+					Daemon:        "warn",
+					DaemonLoggers: &v2.DaemonLoggerLevels{DrainHelper: "trace"},
+				},
+				LogFormat: utils.LogFormatText,
+			},
+		})).To(Succeed())
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(daemonLog.GetLevel()).To(Equal(logrus.WarnLevel))
+		Expect(tLog.GetLevel()).To(Equal(logrus.WarnLevel))
+		Expect(drainLog.GetLevel()).To(Equal(logrus.TraceLevel))
+		Expect(drainLog.Formatter).To(BeAssignableToTypeOf(&logrus.TextFormatter{}))
+	})
+
+	It("should restore defaults when config does not exist", func() {
+		utils.ConfigureLogger(drainLog, logrus.DebugLevel, utils.LogFormatText)
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drainLog.GetLevel()).To(Equal(logrus.InfoLevel))
+		Expect(drainLog.Formatter).To(BeAssignableToTypeOf(&logrus.JSONFormatter{}))
+	})
+})
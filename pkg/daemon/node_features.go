@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NodeFeaturesDir is the directory of local feature files read by the Node Feature Discovery worker
+	NodeFeaturesDir = "/etc/kubernetes/node-feature-discovery/features.d"
+	// nodeFeaturesExpiry makes Node Feature Discovery drop features which are not refreshed by the daemon anymore
+	nodeFeaturesExpiry = time.Hour
+	// nodeFeaturePrefix prefixes names of the features; Node Feature Discovery adds feature.node.kubernetes.io/ to them
+	nodeFeaturePrefix = "intel-vran."
+	// nodeFeatureMixed is the value of a feature which differs between accelerators of the same model
+	nodeFeatureMixed = "mixed"
+)
+
+var invalidNodeFeatureChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// NodeFeatureWriter publishes facts about accelerators of the node as local features of Node Feature Discovery
+type NodeFeatureWriter struct {
+	log    *logrus.Logger
+	dir    string
+	expiry time.Duration
+
+	mu      sync.Mutex
+	written map[string]writtenNodeFeatures
+}
+
+type writtenNodeFeatures struct {
+	features map[string]string
+	at       time.Time
+}
+
+func NewNodeFeatureWriter(log *logrus.Logger, dir string) *NodeFeatureWriter {
+	return &NodeFeatureWriter{log: log, dir: dir, expiry: nodeFeaturesExpiry, written: map[string]writtenNodeFeatures{}}
+}
+
+/*****************************************************************************
+ * Method: NodeFeatureWriter::Write
+ * Description: Writes features of the accelerator family to its feature
+ * 		file when they changed or their expiry time is approaching;
+ * 		nil writer does nothing
+ ****************************************************************************/
+func (w *NodeFeatureWriter) Write(family string, features map[string]string) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	previous, ok := w.written[family]
+	if ok && reflect.DeepEqual(previous.features, features) && now.Before(previous.at.Add(w.expiry/2)) {
+		return nil
+	}
+
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var content strings.Builder
+	content.WriteString("# Accelerator features of the node published by the SRIOV-FEC daemon\n")
+	content.WriteString(fmt.Sprintf("# +expiry-time=%s\n", now.Add(w.expiry).UTC().Format(time.RFC3339)))
+	for _, name := range names {
+		content.WriteString(fmt.Sprintf("%s=%s\n", name, features[name]))
+	}
+
+	// write to a temporary file first so that Node Feature Discovery never reads partial content
+	path := filepath.Join(w.dir, "sriov-fec-"+family)
+	tmp := filepath.Join(w.dir, ".sriov-fec-"+family+".tmp")
+	if err := os.WriteFile(tmp, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write node features: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write node features: %v", err)
+	}
+
+	if !reflect.DeepEqual(previous.features, features) {
+		w.log.WithField("path", path).WithField("features", features).Info("node features written")
+	}
+	w.written[family] = writtenNodeFeatures{features: features, at: now}
+	return nil
+}
+
+// acceleratorFacts describes a physical function published as node features
+type acceleratorFacts struct {
+	model  string
+	driver string
+	maxVFs int
+	// queueProfile is empty when the accelerator is not configured
+	queueProfile string
+}
+
+// nodeFeatures summarizes facts of accelerators per model
+func nodeFeatures(accelerators []acceleratorFacts, pfBbConfigVersion string) map[string]string {
+	features := map[string]string{}
+	if len(accelerators) == 0 {
+		return features
+	}
+	if pfBbConfigVersion != "" {
+		features[nodeFeaturePrefix+"pf-bb-config-version"] = nodeFeatureValue(pfBbConfigVersion)
+	}
+
+	byModel := map[string][]acceleratorFacts{}
+	for _, acc := range accelerators {
+		model := invalidNodeFeatureChars.ReplaceAllString(acc.model, "_")
+		byModel[model] = append(byModel[model], acc)
+	}
+	for model, accs := range byModel {
+		prefix := nodeFeaturePrefix + model + "."
+		features[prefix+"count"] = strconv.Itoa(len(accs))
+
+		maxVFs := 0
+		drivers, profiles := map[string]bool{}, map[string]bool{}
+		for _, acc := range accs {
+			if acc.maxVFs > maxVFs {
+				maxVFs = acc.maxVFs
+			}
+			drivers[acc.driver] = true
+			profiles[acc.queueProfile] = true
+		}
+		features[prefix+"max-vfs"] = strconv.Itoa(maxVFs)
+		if value := commonNodeFeatureValue(drivers); value != "" {
+			features[prefix+"pf-driver"] = value
+		}
+		if value := commonNodeFeatureValue(profiles); value != "" {
+			features[prefix+"queue-profile"] = value
+		}
+	}
+	return features
+}
+
+// commonNodeFeatureValue returns the value shared by all accelerators, or nodeFeatureMixed when they differ
+func commonNodeFeatureValue(values map[string]bool) string {
+	if len(values) > 1 {
+		return nodeFeatureMixed
+	}
+	for value := range values {
+		return nodeFeatureValue(value)
+	}
+	return ""
+}
+
+// nodeFeatureValue makes the value a valid label value
+func nodeFeatureValue(value string) string {
+	value = invalidNodeFeatureChars.ReplaceAllString(value, "_")
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "_.-")
+}
+
+// isSpecApplied tells whether the spec of the node config with given generation was configured successfully
+func isSpecApplied(conditions []metav1.Condition, generation int64) bool {
+	condition := meta.FindStatusCondition(conditions, ConditionConfigured)
+	return condition != nil && condition.Reason == string(ConfigurationSucceeded) && condition.ObservedGeneration == generation
+}
+
+// fecQueueProfile describes queue groups of the bbdev config, e.g. vfb16.ul4g0.dl4g0.ul5g4.dl5g4
+func fecQueueProfile(cfg fec.BBDevConfig) string {
+	switch {
+	case cfg.N3000 != nil:
+		return cfg.N3000.NetworkType
+	case cfg.ACC100 != nil:
+		return fecAcc100QueueProfile(*cfg.ACC100)
+	case cfg.ACC200 != nil:
+		return fmt.Sprintf("%s.fft%d", fecAcc100QueueProfile(cfg.ACC200.ACC100BBDevConfig), cfg.ACC200.QFFT.NumQueueGroups)
+	}
+	return ""
+}
+
+func fecAcc100QueueProfile(cfg fec.ACC100BBDevConfig) string {
+	return fmt.Sprintf("vfb%d.ul4g%d.dl4g%d.ul5g%d.dl5g%d", cfg.NumVfBundles, cfg.Uplink4G.NumQueueGroups,
+		cfg.Downlink4G.NumQueueGroups, cfg.Uplink5G.NumQueueGroups, cfg.Downlink5G.NumQueueGroups)
+}
+
+// fecNodeFeatures describes FEC accelerators of the inventory; queue profiles are published once the spec is applied
+func fecNodeFeatures(nc *fec.SriovFecNodeConfig, inventory *fec.NodeInventory) map[string]string {
+	profiles := map[string]string{}
+	if isSpecApplied(nc.Status.Conditions, nc.GetGeneration()) {
+		for _, pf := range nc.Spec.PhysicalFunctions {
+			profiles[pf.PCIAddress] = fecQueueProfile(pf.BBDevConfig)
+		}
+	}
+
+	var accelerators []acceleratorFacts
+	for _, acc := range inventory.SriovAccelerators {
+		model := supportedAccelerators.Devices[acc.DeviceID]
+		if model == "" {
+			model = acc.DeviceID
+		}
+		accelerators = append(accelerators, acceleratorFacts{
+			model:        model,
+			driver:       acc.PFDriver,
+			maxVFs:       acc.MaxVFs,
+			queueProfile: profiles[acc.PCIAddress],
+		})
+	}
+	return nodeFeatures(accelerators, nc.Status.PfBbConfVersion)
+}
+
+// vrbQueueProfile describes queue groups of the bbdev config, e.g. vfb16.ul4g0.dl4g0.ul5g4.dl5g4.fft4.mld4
+func vrbQueueProfile(cfg vrbv1.BBDevConfig) string {
+	switch {
+	case cfg.VRB1 != nil:
+		return fmt.Sprintf("%s.fft%d", vrbAcc100QueueProfile(cfg.VRB1.ACC100BBDevConfig), cfg.VRB1.QFFT.NumQueueGroups)
+	case cfg.VRB2 != nil:
+		return fmt.Sprintf("%s.fft%d.mld%d", vrbAcc100QueueProfile(cfg.VRB2.ACC100BBDevConfig), cfg.VRB2.QFFT.NumQueueGroups,
+			cfg.VRB2.QMLD.NumQueueGroups)
+	}
+	return ""
+}
+
+func vrbAcc100QueueProfile(cfg vrbv1.ACC100BBDevConfig) string {
+	return fmt.Sprintf("vfb%d.ul4g%d.dl4g%d.ul5g%d.dl5g%d", cfg.NumVfBundles, cfg.Uplink4G.NumQueueGroups,
+		cfg.Downlink4G.NumQueueGroups, cfg.Uplink5G.NumQueueGroups, cfg.Downlink5G.NumQueueGroups)
+}
+
+// vrbNodeFeatures describes VRB accelerators of the inventory; queue profiles are published once the spec is applied
+func vrbNodeFeatures(nc *vrbv1.SriovVrbNodeConfig, inventory *vrbv1.NodeInventory) map[string]string {
+	profiles := map[string]string{}
+	if isSpecApplied(nc.Status.Conditions, nc.GetGeneration()) {
+		for _, pf := range nc.Spec.PhysicalFunctions {
+			profiles[pf.PCIAddress] = vrbQueueProfile(pf.BBDevConfig)
+		}
+	}
+
+	var accelerators []acceleratorFacts
+	for _, acc := range inventory.SriovAccelerators {
+		model := VrbsupportedAccelerators.Devices[acc.DeviceID]
+		if model == "" {
+			model = acc.DeviceID
+		}
+		accelerators = append(accelerators, acceleratorFacts{
+			model:        model,
+			driver:       acc.PFDriver,
+			maxVFs:       acc.MaxVFs,
+			queueProfile: profiles[acc.PCIAddress],
+		})
+	}
+	return nodeFeatures(accelerators, nc.Status.PfBbConfVersion)
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	fec "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("node features", func() {
+	var origSupportedAccelerators utils.AcceleratorDiscoveryConfig
+
+	BeforeEach(func() {
+		origSupportedAccelerators = supportedAccelerators
+		supportedAccelerators = utils.AcceleratorDiscoveryConfig{Devices: map[string]string{"0d5c": "ACC100", "0b32": ""}}
+	})
+
+	AfterEach(func() {
+		supportedAccelerators = origSupportedAccelerators
+	})
+
+	acc100 := func(pci, driver string) fec.SriovAccelerator {
+		return fec.SriovAccelerator{VendorID: "8086", DeviceID: "0d5c", PCIAddress: pci, PFDriver: driver, MaxVFs: 16}
+	}
+	acc100Config := func(pci string) fec.PhysicalFunctionConfigExt {
+		return fec.PhysicalFunctionConfigExt{PCIAddress: pci, BBDevConfig: fec.BBDevConfig{ACC100: &fec.ACC100BBDevConfig{
+			NumVfBundles: 16,
+			Uplink5G:     fec.QueueGroupConfig{NumQueueGroups: 4},
+			Downlink5G:   fec.QueueGroupConfig{NumQueueGroups: 4},
+		}}}
+	}
+
+	Context("fecNodeFeatures", func() {
+		var nc *fec.SriovFecNodeConfig
+
+		BeforeEach(func() {
+			nc = &fec.SriovFecNodeConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Generation: 2},
+				Spec:       fec.SriovFecNodeConfigSpec{PhysicalFunctions: []fec.PhysicalFunctionConfigExt{acc100Config("0000:17:00.0"), acc100Config("0000:b1:00.0")}},
+				Status: fec.SriovFecNodeConfigStatus{
+					PfBbConfVersion: "v24.03-0-g1bbb3ac",
+					Conditions: []metav1.Condition{{Type: ConditionConfigured, Status: metav1.ConditionTrue,
+						Reason: string(ConfigurationSucceeded), ObservedGeneration: 2}},
+				},
+			}
+		})
+
+		It("should describe accelerators per model with the queue profile of the applied spec", func() {
+			inventory := &fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{
+				acc100("0000:17:00.0", utils.PciPfStubDash),
+				acc100("0000:b1:00.0", utils.PciPfStubDash),
+				{VendorID: "8086", DeviceID: "0b32", PCIAddress: "0000:20:00.0", PFDriver: utils.VfioPci, MaxVFs: 0},
+			}}
+
+			Expect(fecNodeFeatures(nc, inventory)).To(Equal(map[string]string{
+				"intel-vran.pf-bb-config-version": "v24.03-0-g1bbb3ac",
+				"intel-vran.ACC100.count":         "2",
+				"intel-vran.ACC100.max-vfs":       "16",
+				"intel-vran.ACC100.pf-driver":     "pci-pf-stub",
+				"intel-vran.ACC100.queue-profile": "vfb16.ul4g0.dl4g0.ul5g4.dl5g4",
+				"intel-vran.0b32.count":           "1",
+				"intel-vran.0b32.max-vfs":         "0",
+				"intel-vran.0b32.pf-driver":       "vfio-pci",
+			}))
+		})
+
+		It("should not publish queue profiles until the spec is applied and mark differing values as mixed", func() {
+			nc.Generation = 3
+			inventory := &fec.NodeInventory{SriovAccelerators: []fec.SriovAccelerator{
+				acc100("0000:17:00.0", utils.PciPfStubDash),
+				acc100("0000:b1:00.0", utils.VfioPci),
+			}}
+
+			features := fecNodeFeatures(nc, inventory)
+			Expect(features).ToNot(HaveKey("intel-vran.ACC100.queue-profile"))
+			Expect(features).To(HaveKeyWithValue("intel-vran.ACC100.pf-driver", "mixed"))
+		})
+
+		It("should not publish anything without accelerators", func() {
+			Expect(fecNodeFeatures(nc, &fec.NodeInventory{})).To(BeEmpty())
+		})
+	})
+
+	Context("NodeFeatureWriter", func() {
+		var (
+			dir    string
+			writer *NodeFeatureWriter
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "features.d")
+			Expect(err).ToNot(HaveOccurred())
+			writer = NewNodeFeatureWriter(utils.NewLogger(), dir)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should write features of the family with expiry time", func() {
+			Expect(writer.Write("fec", map[string]string{"intel-vran.ACC100.count": "1", "intel-vran.ACC100.max-vfs": "16"})).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(dir, "sriov-fec-fec"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("# +expiry-time="))
+			Expect(string(content)).To(HaveSuffix("intel-vran.ACC100.count=1\nintel-vran.ACC100.max-vfs=16\n"))
+			Expect(filepath.Glob(filepath.Join(dir, ".*"))).To(BeEmpty())
+		})
+
+		It("should rewrite features only when they change or are about to expire", func() {
+			path := filepath.Join(dir, "sriov-fec-vrb")
+			Expect(writer.Write("vrb", map[string]string{"intel-vran.VRB2.count": "1"})).To(Succeed())
+			Expect(os.Remove(path)).To(Succeed())
+
+			Expect(writer.Write("vrb", map[string]string{"intel-vran.VRB2.count": "1"})).To(Succeed())
+			Expect(path).ToNot(BeAnExistingFile())
+
+			writer.written["vrb"] = writtenNodeFeatures{features: writer.written["vrb"].features, at: time.Now().Add(-nodeFeaturesExpiry)}
+			Expect(writer.Write("vrb", map[string]string{"intel-vran.VRB2.count": "1"})).To(Succeed())
+			Expect(path).To(BeAnExistingFile())
+
+			Expect(writer.Write("vrb", map[string]string{"intel-vran.VRB2.count": "2"})).To(Succeed())
+			Expect(os.ReadFile(path)).To(HaveSuffix("intel-vran.VRB2.count=2\n"))
+		})
+
+		It("should do nothing when disabled", func() {
+			var disabled *NodeFeatureWriter
+			Expect(disabled.Write("fec", map[string]string{"intel-vran.ACC100.count": "1"})).To(Succeed())
+		})
+	})
+})
@@ -251,6 +251,27 @@ func (n *NodeConfigurator) VrbcleanAcceleratorConfig(acc vrbv1.SriovAccelerator)
 	return nil
 }
 
+// restoreDefaultDriver clears the driver override of the device and lets the kernel bind the driver it selects
+func (n *NodeConfigurator) restoreDefaultDriver(pciAddress string) error {
+	if err := n.unbindIfBound(pciAddress); err != nil {
+		return err
+	}
+
+	driverOverridePath := filepath.Join(sysBusPciDevices, pciAddress, "driver_override")
+	if err := writeFileWithTimeout(driverOverridePath, "\n"); err != nil {
+		n.Log.WithError(err).WithField("path", driverOverridePath).Error("failed to clear driver override")
+		return err
+	}
+
+	probePath := filepath.Join(filepath.Dir(sysBusPciDrivers), "drivers_probe")
+	n.Log.WithField("pciAddress", pciAddress).Info("probing default driver of device")
+	if err := writeFileWithTimeout(probePath, pciAddress); err != nil {
+		n.Log.WithError(err).WithField("pciAddress", pciAddress).WithField("probePath", probePath).Error("failed to probe driver of device")
+		return err
+	}
+	return nil
+}
+
 func removeVFs(nc *NodeConfigurator, acc sriovv2.SriovAccelerator) error {
 	if len(acc.VFs) > 0 {
 		if err := nc.changeAmountOfVFs(acc.PFDriver, acc.PCIAddress, 0); err != nil {
@@ -400,6 +421,16 @@ func (n *NodeConfigurator) configureAccelerator(acc sriovv2.SriovAccelerator, re
 		return err
 	}
 
+	for _, group := range requestedConfig.VFGroups {
+		if group.VFDriver == "" {
+			continue
+		}
+		if err := n.loadModule(group.VFDriver); err != nil {
+			n.Log.WithField("driver", group.VFDriver).Info("failed to load module for VF group driver")
+			return err
+		}
+	}
+
 	if requestedConfig.BBDevConfig.N3000 != nil {
 		if err := n.configureCommandRegister(requestedConfig.PCIAddress); err != nil {
 			return err
@@ -420,13 +451,7 @@ func (n *NodeConfigurator) configureAccelerator(acc sriovv2.SriovAccelerator, re
 		return err
 	}
 
-	for _, vf := range createdVfs {
-		if err := n.bindDeviceToDriver(vf, requestedConfig.VFDriver); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return n.bindVFs(acc.PCIAddress, createdVfs, requestedConfig.VFDriver, requestedConfig.VFDriverOf)
 
 }
 
@@ -441,6 +466,16 @@ func (n *NodeConfigurator) VrbconfigureAccelerator(acc vrbv1.SriovAccelerator, r
 		return err
 	}
 
+	for _, group := range requestedConfig.VFGroups {
+		if group.VFDriver == "" {
+			continue
+		}
+		if err := n.loadModule(group.VFDriver); err != nil {
+			n.Log.WithField("driver", group.VFDriver).Info("failed to load module for VF group driver")
+			return err
+		}
+	}
+
 	if err := n.pfBBConfigController.VrbinitializePfBBConfig(acc, requestedConfig); err != nil {
 		return err
 	}
@@ -455,14 +490,25 @@ func (n *NodeConfigurator) VrbconfigureAccelerator(acc vrbv1.SriovAccelerator, r
 		return err
 	}
 
-	for _, vf := range createdVfs {
-		if err := n.bindDeviceToDriver(vf, requestedConfig.VFDriver); err != nil {
+	return n.bindVFs(acc.PCIAddress, createdVfs, requestedConfig.VFDriver, requestedConfig.VFDriverOf)
+
+}
+
+// bindVFs binds VFs of the PF to drivers of VF groups they belong to; VFs of unknown index get the default driver
+func (n *NodeConfigurator) bindVFs(pfAddress string, vfs []string, defaultDriver string, driverOf func(index int) string) error {
+	indexes := vfIndexes(pfAddress)
+	for _, vf := range vfs {
+		driver := defaultDriver
+		if index, ok := indexes[vf]; ok {
+			driver = driverOf(index)
+		} else {
+			n.Log.WithField("pf", pfAddress).WithField("vf", vf).Warn("unknown VF index, default VF driver is used")
+		}
+		if err := n.bindDeviceToDriver(vf, driver); err != nil {
 			return err
 		}
 	}
-
 	return nil
-
 }
 
 func getMatchingConfiguration(pciAddress string, configurations []sriovv2.PhysicalFunctionConfigExt) *sriovv2.PhysicalFunctionConfigExt {
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bindVFs", func() {
+	const pf = "0000:b1:00.0"
+
+	var (
+		origDevices, origDrivers = sysBusPciDevices, sysBusPciDrivers
+		workdir                  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		workdir, err = os.MkdirTemp("", "bindvfs")
+		Expect(err).ToNot(HaveOccurred())
+		sysBusPciDevices = filepath.Join(workdir, "devices")
+		sysBusPciDrivers = filepath.Join(workdir, "drivers")
+		Expect(createFiles(filepath.Join(sysBusPciDevices, pf))).To(Succeed())
+		for _, driver := range []string{"vfio-pci", "igb_uio"} {
+			Expect(createFiles(filepath.Join(sysBusPciDrivers, driver), "bind")).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		sysBusPciDevices, sysBusPciDrivers = origDevices, origDrivers
+		Expect(os.RemoveAll(workdir)).To(Succeed())
+	})
+
+	driverOverride := func(vf string) string {
+		data, err := os.ReadFile(filepath.Join(sysBusPciDevices, vf, "driver_override"))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	It("should bind VFs to drivers of their groups", func() {
+		vfs := []string{"0000:b2:00.0", "0000:b2:00.1", "0000:b2:00.2", "0000:b2:00.3"}
+		for index, vf := range vfs {
+			Expect(createFiles(filepath.Join(sysBusPciDevices, vf), "driver_override")).To(Succeed())
+			Expect(os.Symlink("../"+vf, filepath.Join(sysBusPciDevices, pf, "virtfn"+strconv.Itoa(index)))).To(Succeed())
+		}
+		Expect(createFiles(filepath.Join(sysBusPciDevices, "0000:b2:00.4"), "driver_override")).To(Succeed())
+
+		config := sriovv2.PhysicalFunctionConfigExt{PCIAddress: pf, VFDriver: "vfio-pci", VFAmount: 4, VFGroups: []sriovv2.VFGroup{
+			{First: 0, Last: 1, ResourceName: "intel_fec_du_a"},
+			{First: 2, Last: 3, ResourceName: "intel_fec_du_b", VFDriver: "igb_uio"},
+		}}
+		n := &NodeConfigurator{Log: utils.NewLogger()}
+		Expect(n.bindVFs(pf, append(vfs, "0000:b2:00.4"), config.VFDriver, config.VFDriverOf)).To(Succeed())
+
+		Expect(driverOverride("0000:b2:00.0")).To(Equal("vfio-pci"))
+		Expect(driverOverride("0000:b2:00.1")).To(Equal("vfio-pci"))
+		Expect(driverOverride("0000:b2:00.2")).To(Equal("igb_uio"))
+		Expect(driverOverride("0000:b2:00.3")).To(Equal("igb_uio"))
+		Expect(driverOverride("0000:b2:00.4")).To(Equal("vfio-pci"), "VF of unknown index gets the default driver")
+	})
+})
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reconfigurationStarted                = "Started"
+	reconfigurationApplyingConfiguration  = "ApplyingConfiguration"
+	reconfigurationRestartingDevicePlugin = "RestartingDevicePlugin"
+)
+
+// staleMarksRetryInterval paces attempts to remove stale reconfiguration marks at daemon startup
+var staleMarksRetryInterval = 5 * time.Second
+
+// reconfigurationsInProgress counts FEC and VRB reconfigurations running concurrently on the node,
+// the taint is removed only when the last of them finishes
+var reconfigurationsInProgress = struct {
+	sync.Mutex
+	count int
+}{}
+
+var reconfiguringTaint = corev1.Taint{
+	Key:    utils.ReconfiguringTaintKey,
+	Effect: corev1.TaintEffectNoSchedule,
+}
+
+// nodeReconfigurationMarker taints and annotates the Node for the time of accelerators reconfiguration,
+// so that no new pods land on the node and grab VFs which are about to disappear
+type nodeReconfigurationMarker struct {
+	client   client.Client
+	log      *logrus.Logger
+	nodeName string
+}
+
+/*****************************************************************************
+ * Method: nodeReconfigurationMarker::begin
+ * Description: Taints the node with the reconfiguring taint and sets the
+ * 		progress annotation
+ ****************************************************************************/
+func (m *nodeReconfigurationMarker) begin(ctx context.Context) error {
+	reconfigurationsInProgress.Lock()
+	defer reconfigurationsInProgress.Unlock()
+
+	reconfigurationsInProgress.count++
+	m.log.WithField("taint", reconfiguringTaint.Key).Info("tainting node for the time of reconfiguration")
+	return m.updateNode(ctx, func(node *corev1.Node) {
+		if !hasReconfiguringTaint(node) {
+			node.Spec.Taints = append(node.Spec.Taints, reconfiguringTaint)
+		}
+		setReconfigurationProgress(node, reconfigurationStarted)
+	})
+}
+
+/*****************************************************************************
+ * Method: nodeReconfigurationMarker::progress
+ * Description: Updates the progress annotation of the node
+ ****************************************************************************/
+func (m *nodeReconfigurationMarker) progress(ctx context.Context, progress string) {
+	err := m.updateNode(ctx, func(node *corev1.Node) {
+		setReconfigurationProgress(node, progress)
+	})
+	if err != nil {
+		m.log.WithError(err).WithField("progress", progress).Warn("failed to update reconfiguration progress annotation")
+	}
+}
+
+/*****************************************************************************
+ * Method: nodeReconfigurationMarker::end
+ * Description: Removes the reconfiguring taint and progress annotation when
+ * 		no other reconfiguration is in progress on the node
+ ****************************************************************************/
+func (m *nodeReconfigurationMarker) end(ctx context.Context) error {
+	reconfigurationsInProgress.Lock()
+	defer reconfigurationsInProgress.Unlock()
+
+	if reconfigurationsInProgress.count > 0 {
+		reconfigurationsInProgress.count--
+	}
+	if reconfigurationsInProgress.count > 0 {
+		m.log.Info("another reconfiguration is in progress - keeping the node tainted")
+		return nil
+	}
+
+	m.log.WithField("taint", reconfiguringTaint.Key).Info("removing reconfiguration taint from the node")
+	return m.updateNode(ctx, clearReconfigurationMarks)
+}
+
+func (m *nodeReconfigurationMarker) updateNode(ctx context.Context, mutate func(node *corev1.Node)) error {
+	return updateNode(ctx, m.client, m.nodeName, mutate)
+}
+
+func updateNode(ctx context.Context, c client.Client, nodeName string, mutate func(node *corev1.Node)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node := &corev1.Node{}
+		if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			return err
+		}
+		original := node.DeepCopy()
+		mutate(node)
+		return c.Patch(ctx, node, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+func hasReconfiguringTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.MatchTaint(&reconfiguringTaint) {
+			return true
+		}
+	}
+	return false
+}
+
+func setReconfigurationProgress(node *corev1.Node, progress string) {
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[utils.ReconfigurationProgressAnnotation] = progress
+}
+
+func clearReconfigurationMarks(node *corev1.Node) {
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+	for _, taint := range node.Spec.Taints {
+		if !taint.MatchTaint(&reconfiguringTaint) {
+			taints = append(taints, taint)
+		}
+	}
+	node.Spec.Taints = taints
+	delete(node.Annotations, utils.ReconfigurationProgressAnnotation)
+}
+
+/*****************************************************************************
+ * Method: RemoveStaleReconfigurationMarks
+ * Description: Removes the reconfiguring taint and progress annotation left
+ * 		on the node e.g. when the daemon was restarted during reconfiguration
+ ****************************************************************************/
+func RemoveStaleReconfigurationMarks(c client.Client, nodeName string, log *logrus.Logger) error {
+	node := &corev1.Node{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: nodeName}, node); err != nil {
+		return err
+	}
+	if _, annotated := node.Annotations[utils.ReconfigurationProgressAnnotation]; !annotated && !hasReconfiguringTaint(node) {
+		return nil
+	}
+
+	log.WithField("progress", node.Annotations[utils.ReconfigurationProgressAnnotation]).
+		Info("removing stale reconfiguration taint and annotation from the node")
+	return updateNode(context.TODO(), c, nodeName, clearReconfigurationMarks)
+}
+
+/*****************************************************************************
+ * Method: RemoveStaleReconfigurationMarksWithRetry
+ * Description: Retries RemoveStaleReconfigurationMarks until it succeeds or
+ * 		the context is done, so that a temporary failure of the API server
+ * 		at startup neither fails the daemon nor leaves the node tainted
+ ****************************************************************************/
+func RemoveStaleReconfigurationMarksWithRetry(ctx context.Context, c client.Client, nodeName string, log *logrus.Logger) error {
+	return wait.PollImmediateInfiniteWithContext(ctx, staleMarksRetryInterval, func(context.Context) (bool, error) {
+		if err := RemoveStaleReconfigurationMarks(c, nodeName, log); err != nil {
+			log.WithError(err).Warn("failed to remove stale reconfiguration taint - retrying")
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("nodeReconfigurationMarker", func() {
+	var (
+		fakeClient client.Client
+		marker     nodeReconfigurationMarker
+		otherTaint = corev1.Taint{Key: "other", Effect: corev1.TaintEffectNoExecute}
+	)
+
+	getNode := func() *corev1.Node {
+		node := &corev1.Node{}
+		Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "worker"}, node)).To(Succeed())
+		return node
+	}
+
+	BeforeEach(func() {
+		reconfigurationsInProgress.count = 0
+		fakeClient = fake.NewClientBuilder().WithObjects(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{otherTaint}},
+		}).Build()
+		marker = nodeReconfigurationMarker{client: fakeClient, log: utils.NewLogger(), nodeName: "worker"}
+	})
+
+	It("should taint and annotate the node for the time of reconfiguration", func() {
+		Expect(marker.begin(context.TODO())).To(Succeed())
+		node := getNode()
+		Expect(node.Spec.Taints).To(ConsistOf(otherTaint, reconfiguringTaint))
+		Expect(node.Annotations).To(HaveKeyWithValue(utils.ReconfigurationProgressAnnotation, reconfigurationStarted))
+
+		marker.progress(context.TODO(), reconfigurationRestartingDevicePlugin)
+		Expect(getNode().Annotations).To(HaveKeyWithValue(utils.ReconfigurationProgressAnnotation, reconfigurationRestartingDevicePlugin))
+
+		Expect(marker.end(context.TODO())).To(Succeed())
+		node = getNode()
+		Expect(node.Spec.Taints).To(ConsistOf(otherTaint))
+		Expect(node.Annotations).ToNot(HaveKey(utils.ReconfigurationProgressAnnotation))
+	})
+
+	It("should keep the taint until the last concurrent reconfiguration ends", func() {
+		Expect(marker.begin(context.TODO())).To(Succeed())
+		Expect(marker.begin(context.TODO())).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(otherTaint, reconfiguringTaint))
+
+		Expect(marker.end(context.TODO())).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(otherTaint, reconfiguringTaint))
+
+		Expect(marker.end(context.TODO())).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(otherTaint))
+	})
+
+	It("should remove stale taint and annotation", func() {
+		Expect(marker.begin(context.TODO())).To(Succeed())
+
+		Expect(RemoveStaleReconfigurationMarks(fakeClient, "worker", utils.NewLogger())).To(Succeed())
+		node := getNode()
+		Expect(node.Spec.Taints).To(ConsistOf(otherTaint))
+		Expect(node.Annotations).ToNot(HaveKey(utils.ReconfigurationProgressAnnotation))
+	})
+
+	It("should not fail when there is nothing to clean up", func() {
+		Expect(RemoveStaleReconfigurationMarks(fakeClient, "worker", utils.NewLogger())).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(otherTaint))
+	})
+
+	It("should retry removal of stale taint until it succeeds", func() {
+		Expect(marker.begin(context.TODO())).To(Succeed())
+		reconfigurationsInProgress.count = 0
+		defer func(interval time.Duration) { staleMarksRetryInterval = interval }(staleMarksRetryInterval)
+		staleMarksRetryInterval = 10 * time.Millisecond
+
+		failing := &failingGetClient{Client: fakeClient, failures: 2}
+		Expect(RemoveStaleReconfigurationMarksWithRetry(context.TODO(), failing, "worker", utils.NewLogger())).To(Succeed())
+		Expect(failing.failures).To(BeZero())
+		Expect(getNode().Spec.Taints).To(ConsistOf(otherTaint))
+	})
+
+	It("should stop retrying when the context is done", func() {
+		defer func(interval time.Duration) { staleMarksRetryInterval = interval }(staleMarksRetryInterval)
+		staleMarksRetryInterval = 10 * time.Millisecond
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+		defer cancel()
+		failing := &failingGetClient{Client: fakeClient, failures: -1}
+		Expect(RemoveStaleReconfigurationMarksWithRetry(ctx, failing, "worker", utils.NewLogger())).ToNot(Succeed())
+	})
+})
+
+// failingGetClient fails given number of Get calls; negative number fails all of them
+type failingGetClient struct {
+	client.Client
+	failures int
+}
+
+func (c *failingGetClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if c.failures != 0 {
+		if c.failures > 0 {
+			c.failures--
+		}
+		return fmt.Errorf("API server unavailable")
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
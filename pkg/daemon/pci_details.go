@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+)
+
+const (
+	// pcieExtendedCapabilitiesOffset is the offset of the first extended capability in the PCIe config space
+	pcieExtendedCapabilitiesOffset = 0x100
+	pcieConfigSpaceSize            = 4096
+	pcieDsnCapabilityID            = 0x0003
+)
+
+// pciDetails holds the inventory data of a PCI device shared by FEC and VRB accelerators
+type pciDetails struct {
+	numaNode          *int
+	iommuGroup        string
+	revision          string
+	subsystemVendorID string
+	subsystemDeviceID string
+	serialNumber      string
+	linkSpeed         string
+	linkWidth         int
+}
+
+// readPciDetails collects locality, identification and link data of the device; data which is not available is left empty
+func readPciDetails(device *ghw.PCIDevice) pciDetails {
+	details := pciDetails{
+		revision:     device.Revision,
+		iommuGroup:   readIommuGroup(device.Address),
+		serialNumber: readDeviceSerialNumber(device.Address),
+		linkSpeed:    readPciAttribute(device.Address, "current_link_speed"),
+	}
+	if device.Node != nil {
+		numaNode := device.Node.ID
+		details.numaNode = &numaNode
+	}
+	if device.Subsystem != nil {
+		details.subsystemVendorID, details.subsystemDeviceID = device.Subsystem.VendorID, device.Subsystem.ID
+	}
+	if width, err := strconv.Atoi(readPciAttribute(device.Address, "current_link_width")); err == nil {
+		details.linkWidth = width
+	}
+	return details
+}
+
+func readPciAttribute(pciAddress, attribute string) string {
+	raw, err := os.ReadFile(filepath.Join(sysBusPciDevices, pciAddress, attribute))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// readIommuGroup returns the IOMMU group of the device, empty when IOMMU is disabled
+func readIommuGroup(pciAddress string) string {
+	link, err := os.Readlink(filepath.Join(sysBusPciDevices, pciAddress, "iommu_group"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(link)
+}
+
+// readDeviceSerialNumber returns the Device Serial Number found among PCIe extended capabilities of the device,
+// formatted the way lspci does; empty when the device has no such capability or its config space is not readable
+func readDeviceSerialNumber(pciAddress string) string {
+	config, err := os.ReadFile(filepath.Join(sysBusPciDevices, pciAddress, "config"))
+	if err != nil || len(config) < pcieConfigSpaceSize {
+		return ""
+	}
+
+	// every capability takes at least 4 bytes, so the list can't be longer without a loop
+	offset := pcieExtendedCapabilitiesOffset
+	for i := 0; i < (pcieConfigSpaceSize-pcieExtendedCapabilitiesOffset)/4; i++ {
+		if offset < pcieExtendedCapabilitiesOffset || offset+12 > pcieConfigSpaceSize {
+			return ""
+		}
+		header := binary.LittleEndian.Uint32(config[offset:])
+		if header == 0 || header == 0xffffffff {
+			return ""
+		}
+		if header&0xffff == pcieDsnCapabilityID {
+			serial := binary.LittleEndian.Uint64(config[offset+4:])
+			bytes := make([]string, 8)
+			for b := 0; b < 8; b++ {
+				bytes[b] = fmt.Sprintf("%02x", byte(serial>>(8*(7-b))))
+			}
+			return strings.Join(bytes, "-")
+		}
+		offset = int(header>>20) & 0xffc
+	}
+	return ""
+}
+
+// vfIndexes maps PCI addresses of VFs of the PF to their index given by virtfnN links of the PF
+func vfIndexes(pfAddress string) map[string]int {
+	indexes := map[string]int{}
+	links, err := filepath.Glob(filepath.Join(sysBusPciDevices, pfAddress, "virtfn*"))
+	if err != nil {
+		return indexes
+	}
+	for _, link := range links {
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(link), "virtfn"))
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(link)
+		if err != nil {
+			continue
+		}
+		indexes[filepath.Base(target)] = index
+	}
+	return indexes
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+
+	"github.com/jaypipes/ghw"
+	"github.com/jaypipes/ghw/pkg/topology"
+	"github.com/jaypipes/pcidb"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pciDetails", func() {
+	const pf = "0000:b1:00.0"
+
+	var (
+		origSysBusPciDevices = sysBusPciDevices
+		devices              string
+	)
+
+	BeforeEach(func() {
+		var err error
+		devices, err = os.MkdirTemp("", "devices")
+		Expect(err).ToNot(HaveOccurred())
+		sysBusPciDevices = devices
+		Expect(os.MkdirAll(filepath.Join(devices, pf), 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		sysBusPciDevices = origSysBusPciDevices
+		Expect(os.RemoveAll(devices)).To(Succeed())
+	})
+
+	writeAttribute := func(address, attribute, value string) {
+		Expect(os.MkdirAll(filepath.Join(devices, address), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(devices, address, attribute), []byte(value), 0600)).To(Succeed())
+	}
+
+	It("should read locality, identification and link data of the device", func() {
+		Expect(os.Symlink("../../../kernel/iommu_groups/42", filepath.Join(devices, pf, "iommu_group"))).To(Succeed())
+		writeAttribute(pf, "current_link_speed", "16.0 GT/s PCIe\n")
+		writeAttribute(pf, "current_link_width", "16\n")
+
+		// AER capability at 0x100 pointing to DSN capability at 0x148
+		config := make([]byte, pcieConfigSpaceSize)
+		binary.LittleEndian.PutUint32(config[0x100:], 0x148<<20|0x1<<16|0x0001)
+		binary.LittleEndian.PutUint32(config[0x148:], 0x000<<20|0x1<<16|pcieDsnCapabilityID)
+		binary.LittleEndian.PutUint64(config[0x14c:], 0x001122fffe334455)
+		writeAttribute(pf, "config", string(config))
+
+		details := readPciDetails(&ghw.PCIDevice{
+			Address:   pf,
+			Revision:  "0x00",
+			Subsystem: &pcidb.Product{VendorID: "8086", ID: "0001"},
+			Node:      &topology.Node{ID: 1},
+		})
+		Expect(*details.numaNode).To(Equal(1))
+		Expect(details.iommuGroup).To(Equal("42"))
+		Expect(details.revision).To(Equal("0x00"))
+		Expect(details.subsystemVendorID).To(Equal("8086"))
+		Expect(details.subsystemDeviceID).To(Equal("0001"))
+		Expect(details.serialNumber).To(Equal("00-11-22-ff-fe-33-44-55"))
+		Expect(details.linkSpeed).To(Equal("16.0 GT/s PCIe"))
+		Expect(details.linkWidth).To(Equal(16))
+	})
+
+	It("should leave data which is not available empty", func() {
+		writeAttribute(pf, "config", string(make([]byte, 256)))
+
+		details := readPciDetails(&ghw.PCIDevice{Address: pf})
+		Expect(details).To(Equal(pciDetails{}))
+	})
+
+	It("should not loop on malformed capability lists", func() {
+		config := make([]byte, pcieConfigSpaceSize)
+		binary.LittleEndian.PutUint32(config[0x100:], 0x100<<20|0x1<<16|0x0001)
+		writeAttribute(pf, "config", string(config))
+
+		Expect(readDeviceSerialNumber(pf)).To(BeEmpty())
+	})
+
+	It("should map VFs to their index", func() {
+		for index, vf := range map[string]string{"0": "0000:b2:00.0", "1": "0000:b2:00.1", "10": "0000:b2:01.2"} {
+			Expect(os.Symlink("../"+vf, filepath.Join(devices, pf, "virtfn"+index))).To(Succeed())
+		}
+
+		Expect(vfIndexes(pf)).To(Equal(map[string]int{"0000:b2:00.0": 0, "0000:b2:00.1": 1, "0000:b2:01.2": 10}))
+	})
+})
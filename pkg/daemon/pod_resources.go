@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+const podResourcesTimeout = 5 * time.Second
+
+var (
+	// podResourcesSocket is the socket of PodResources API served by the kubelet
+	podResourcesSocket = "/var/lib/kubelet/pod-resources/kubelet.sock"
+	getVfConsumers     = listVfConsumers
+)
+
+// vfConsumer identifies the container a device is allocated to
+type vfConsumer struct {
+	namespace    string
+	pod          string
+	container    string
+	resourceName string
+}
+
+/*****************************************************************************
+ * Function: listVfConsumers
+ * Description: Queries PodResources API of the kubelet for devices allocated
+ * 		to containers; returns consumers keyed by device ID, which is
+ * 		the PCI address of the VF for devices of SR-IOV device plugin.
+ * 		Returns no consumers when the API is not available
+ ****************************************************************************/
+func listVfConsumers(log *logrus.Logger) map[string]vfConsumer {
+	if _, err := os.Stat(podResourcesSocket); err != nil {
+		log.WithError(err).Debug("PodResources API is not available, VF consumers are not reported")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), podResourcesTimeout)
+	defer cancel()
+	consumers, err := queryVfConsumers(ctx, podResourcesSocket)
+	if err != nil {
+		log.WithError(err).Info("failed to list VF consumers")
+		return nil
+	}
+	return consumers
+}
+
+func queryVfConsumers(ctx context.Context, socket string) (map[string]vfConsumer, error) {
+	conn, err := grpc.DialContext(ctx, "unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", socket, err)
+	}
+	defer conn.Close()
+
+	response, err := podresourcesapi.NewPodResourcesListerClient(conn).List(ctx, &podresourcesapi.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod resources: %v", err)
+	}
+
+	consumers := map[string]vfConsumer{}
+	for _, pod := range response.GetPodResources() {
+		for _, container := range pod.GetContainers() {
+			for _, devices := range container.GetDevices() {
+				for _, id := range devices.GetDeviceIds() {
+					consumers[id] = vfConsumer{
+						namespace:    pod.GetNamespace(),
+						pod:          pod.GetName(),
+						container:    container.GetName(),
+						resourceName: devices.GetResourceName(),
+					}
+				}
+			}
+		}
+	}
+	return consumers, nil
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+type fakePodResourcesServer struct {
+	podresourcesapi.UnimplementedPodResourcesListerServer
+	pods []*podresourcesapi.PodResources
+}
+
+func (f *fakePodResourcesServer) List(context.Context, *podresourcesapi.ListPodResourcesRequest) (*podresourcesapi.ListPodResourcesResponse, error) {
+	return &podresourcesapi.ListPodResourcesResponse{PodResources: f.pods}, nil
+}
+
+var _ = Describe("listVfConsumers", func() {
+	var (
+		origPodResourcesSocket = podResourcesSocket
+		dir                    string
+		server                 *grpc.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "pod-resources")
+		Expect(err).ToNot(HaveOccurred())
+		podResourcesSocket = filepath.Join(dir, "kubelet.sock")
+	})
+
+	AfterEach(func() {
+		if server != nil {
+			server.Stop()
+			server = nil
+		}
+		podResourcesSocket = origPodResourcesSocket
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	serve := func(pods ...*podresourcesapi.PodResources) {
+		listener, err := net.Listen("unix", podResourcesSocket)
+		Expect(err).ToNot(HaveOccurred())
+		server = grpc.NewServer()
+		podresourcesapi.RegisterPodResourcesListerServer(server, &fakePodResourcesServer{pods: pods})
+		go func() { _ = server.Serve(listener) }()
+	}
+
+	It("should map allocated devices to their containers", func() {
+		serve(
+			&podresourcesapi.PodResources{Name: "du-0", Namespace: "ran", Containers: []*podresourcesapi.ContainerResources{
+				{Name: "l1", Devices: []*podresourcesapi.ContainerDevices{
+					{ResourceName: "intel.com/intel_fec_acc100", DeviceIds: []string{"0000:b2:00.0", "0000:b2:00.1"}},
+				}},
+				{Name: "sidecar"},
+			}},
+			&podresourcesapi.PodResources{Name: "du-1", Namespace: "ran", Containers: []*podresourcesapi.ContainerResources{
+				{Name: "l1", Devices: []*podresourcesapi.ContainerDevices{
+					{ResourceName: "intel.com/intel_vrb_vrb2", DeviceIds: []string{"0000:f8:00.0"}},
+				}},
+			}},
+		)
+
+		Expect(listVfConsumers(logrus.New())).To(Equal(map[string]vfConsumer{
+			"0000:b2:00.0": {namespace: "ran", pod: "du-0", container: "l1", resourceName: "intel.com/intel_fec_acc100"},
+			"0000:b2:00.1": {namespace: "ran", pod: "du-0", container: "l1", resourceName: "intel.com/intel_fec_acc100"},
+			"0000:f8:00.0": {namespace: "ran", pod: "du-1", container: "l1", resourceName: "intel.com/intel_vrb_vrb2"},
+		}))
+	})
+
+	It("should not report consumers when the API is not available", func() {
+		Expect(listVfConsumers(logrus.New())).To(BeNil())
+	})
+})
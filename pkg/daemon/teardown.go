@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// TeardownController resets accelerators of the node when SriovFecOperatorConfig requesting teardown is deleted
+type TeardownController struct {
+	client.Client
+	log            *logrus.Logger
+	nodeNameRef    types.NamespacedName
+	nodeConfigurer *NodeConfigurator
+}
+
+func NewTeardownController(c client.Client, logger *logrus.Logger, nodeNameRef types.NamespacedName, nodeConfigurer *NodeConfigurator) *TeardownController {
+	return &TeardownController{
+		Client:         c,
+		log:            logger,
+		nodeNameRef:    nodeNameRef,
+		nodeConfigurer: nodeConfigurer,
+	}
+}
+
+/*****************************************************************************
+ * Method: TeardownController::Reconcile
+ * Description: Resets accelerators of the node once the SriovFecOperatorConfig
+ * 		with teardown is being deleted and reports completion by
+ * 		annotating the node
+ ****************************************************************************/
+func (t *TeardownController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	config := new(sriovv2.SriovFecOperatorConfig)
+	if err := t.Get(ctx, req.NamespacedName, config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if config.GetDeletionTimestamp().IsZero() || config.Spec.Teardown == nil || !controllerutil.ContainsFinalizer(config, utils.TeardownFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	node := &corev1.Node{}
+	if err := t.Get(ctx, client.ObjectKey{Name: t.nodeNameRef.Name}, node); err != nil {
+		return ctrl.Result{}, err
+	}
+	if node.Annotations[utils.TeardownCompletedAnnotation] == string(config.GetUID()) {
+		return ctrl.Result{}, nil
+	}
+
+	t.log.WithField("teardown", *config.Spec.Teardown).Info("resetting accelerators of the node")
+	if err := t.teardown(*config.Spec.Teardown); err != nil {
+		t.log.WithError(err).Error("failed to reset accelerators of the node")
+		return ctrl.Result{}, err
+	}
+
+	t.log.Info("accelerators of the node are reset")
+	return ctrl.Result{}, updateNode(ctx, t, t.nodeNameRef.Name, func(node *corev1.Node) {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[utils.TeardownCompletedAnnotation] = string(config.GetUID())
+	})
+}
+
+// isTornDown returns whether accelerators of the node are being or were reset by the teardown; such node is left
+// unconfigured until the operator removes the teardown mark once a new SriovFecOperatorConfig is created
+func isTornDown(ctx context.Context, c client.Reader, nodeNameRef types.NamespacedName) (bool, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeNameRef.Name}, node); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if _, completed := node.Annotations[utils.TeardownCompletedAnnotation]; completed {
+		return true, nil
+	}
+
+	config := new(sriovv2.SriovFecOperatorConfig)
+	if err := c.Get(ctx, client.ObjectKey{Name: sriovv2.OperatorConfigName, Namespace: nodeNameRef.Namespace}, config); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return !config.GetDeletionTimestamp().IsZero() && controllerutil.ContainsFinalizer(config, utils.TeardownFinalizer), nil
+}
+
+// teardown stops pf_bb_config, removes VFs and unbinds PFs of all FEC and VRB accelerators of the node
+func (t *TeardownController) teardown(policy sriovv2.Teardown) error {
+	var pfs []string
+
+	fecInventory, err := getSriovInventory(t.log)
+	if err != nil {
+		return err
+	}
+	for _, acc := range fecInventory.SriovAccelerators {
+		if err := t.nodeConfigurer.cleanAcceleratorConfig(acc); err != nil {
+			return err
+		}
+		pfs = append(pfs, acc.PCIAddress)
+	}
+
+	vrbInventory, err := VrbgetSriovInventory(t.log)
+	if err != nil {
+		return err
+	}
+	for _, acc := range vrbInventory.SriovAccelerators {
+		if err := t.nodeConfigurer.VrbcleanAcceleratorConfig(acc); err != nil {
+			return err
+		}
+		pfs = append(pfs, acc.PCIAddress)
+	}
+
+	if !policy.RestorePfDriver {
+		return nil
+	}
+	for _, pf := range pfs {
+		if err := t.nodeConfigurer.restoreDefaultDriver(pf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TeardownController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("teardown").
+		For(&sriovv2.SriovFecOperatorConfig{}).
+		WithEventFilter(resourceNamePredicate{
+			requiredName: sriovv2.OperatorConfigName,
+			log:          t.log,
+		}).Complete(t)
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	sriovv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	vrbv1 "github.com/intel/sriov-fec-operator/api/sriovvrb/v1"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("TeardownController", func() {
+	const pf = "0000:f7:00.0"
+
+	var (
+		controller *TeardownController
+		config     *sriovv2.SriovFecOperatorConfig
+		devices    string
+
+		origDevices, origDrivers = sysBusPciDevices, sysBusPciDrivers
+	)
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: sriovv2.OperatorConfigName, Namespace: "default"}}
+
+	build := func() {
+		scheme := runtime.NewScheme()
+		Expect(sriovv2.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(config, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}}).Build()
+		nodeNameRef := types.NamespacedName{Name: "worker", Namespace: "default"}
+		controller = NewTeardownController(c, utils.NewLogger(), nodeNameRef, NewNodeConfigurator(utils.NewLogger(), &pfBBConfigController{log: utils.NewLogger()}, c, nodeNameRef))
+	}
+
+	completedTeardown := func() string {
+		node := &corev1.Node{}
+		Expect(controller.Get(context.TODO(), client.ObjectKey{Name: "worker"}, node)).To(Succeed())
+		return node.Annotations[utils.TeardownCompletedAnnotation]
+	}
+
+	BeforeEach(func() {
+		deletedAt := metav1.Now()
+		devices = filepath.Join(testTmpFolder, "teardown", "devices")
+		sysBusPciDevices = devices
+		sysBusPciDrivers = filepath.Join(testTmpFolder, "teardown", "drivers")
+		Expect(createFiles(filepath.Join(devices, pf), "driver_override", "reset")).To(Succeed())
+
+		getVFList = func(string) ([]string, error) {
+			return nil, nil
+		}
+		getSriovInventory = func(_ *logrus.Logger) (*sriovv2.NodeInventory, error) {
+			return &sriovv2.NodeInventory{SriovAccelerators: []sriovv2.SriovAccelerator{{PCIAddress: pf}}}, nil
+		}
+		VrbgetSriovInventory = func(_ *logrus.Logger) (*vrbv1.NodeInventory, error) {
+			return &vrbv1.NodeInventory{}, nil
+		}
+
+		config = &sriovv2.SriovFecOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              request.Name,
+				Namespace:         request.Namespace,
+				UID:               "config-uid",
+				Finalizers:        []string{utils.TeardownFinalizer},
+				DeletionTimestamp: &deletedAt,
+			},
+			Spec: sriovv2.SriovFecOperatorConfigSpec{Teardown: &sriovv2.Teardown{RestorePfDriver: true}},
+		}
+	})
+
+	AfterEach(func() {
+		getSriovInventory = GetSriovInventory
+		VrbgetSriovInventory = VrbGetSriovInventory
+		sysBusPciDevices, sysBusPciDrivers = origDevices, origDrivers
+		Expect(os.RemoveAll(filepath.Join(testTmpFolder, "teardown"))).To(Succeed())
+	})
+
+	It("should reset accelerators and report completion when config is deleted", func() {
+		build()
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(completedTeardown()).To(Equal("config-uid"))
+
+		probed, err := os.ReadFile(filepath.Join(testTmpFolder, "teardown", "drivers_probe"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(probed)).To(Equal(pf))
+		Expect(os.ReadFile(filepath.Join(devices, pf, "reset"))).To(BeEquivalentTo("1"))
+	})
+
+	It("should not reset accelerators while config is not deleted", func() {
+		config.DeletionTimestamp = nil
+		build()
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(completedTeardown()).To(BeEmpty())
+		Expect(os.ReadFile(filepath.Join(devices, pf, "reset"))).To(BeEmpty())
+	})
+
+	It("should make node config reconcilers leave torn down node unconfigured", func() {
+		build()
+		nodeNameRef := types.NamespacedName{Name: "worker", Namespace: "default"}
+		fecReconciler := &FecNodeConfigReconciler{Client: controller.Client, log: utils.NewLogger(), nodeNameRef: nodeNameRef}
+		vrbReconciler := &VrbNodeConfigReconciler{Client: controller.Client, log: utils.NewLogger(), nodeNameRef: nodeNameRef}
+		nodeConfigRequest := reconcile.Request{NamespacedName: nodeNameRef}
+
+		By("skipping configuration while the teardown is pending")
+		Expect(fecReconciler.Reconcile(context.TODO(), nodeConfigRequest)).To(Equal(reconcile.Result{RequeueAfter: resyncPeriod}))
+		Expect(vrbReconciler.Reconcile(context.TODO(), nodeConfigRequest)).To(Equal(reconcile.Result{RequeueAfter: resyncPeriod}))
+
+		By("skipping configuration after the teardown while the node is marked")
+		_, err := controller.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controller.Delete(context.TODO(), config)).To(Succeed())
+		Expect(fecReconciler.Reconcile(context.TODO(), nodeConfigRequest)).To(Equal(reconcile.Result{RequeueAfter: resyncPeriod}))
+		Expect(vrbReconciler.Reconcile(context.TODO(), nodeConfigRequest)).To(Equal(reconcile.Result{RequeueAfter: resyncPeriod}))
+	})
+})
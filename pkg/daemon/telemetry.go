@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +20,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -29,18 +32,28 @@ const (
 	queueTypeLabel  = "queue_type"
 	engineIDLabel   = "engine_id"
 	statusLabel     = "status"
+
+	vfsReadyReason    = "AllVfsReady"
+	vfsNotReadyReason = "VfsNotReady"
 )
 
 type telemetryGatherer struct {
-	codeBlocksGauge, bytesGauge, engineGauge, vfStatusGauge, vfCountGauge *prometheus.GaugeVec
-	metricUpdates                                                         []func()
+	codeBlocksGauge, bytesGauge, engineGauge, vfStatusGauge, vfCountGauge, vfAllocatedGauge *prometheus.GaugeVec
+	metricUpdates                                                                           []func()
+	// readiness of VFs reported by vf_status since last takeVfReadiness call; key: PCI address of VF
+	vfReadiness map[string]bool
+	// onVfReadiness is notified about readiness of VFs gathered in every telemetry loop
+	onVfReadiness VfReadinessListener
 }
 
+// VfReadinessListener consumes readiness of VFs reported by vf_status telemetry; key: PCI address of VF
+type VfReadinessListener func(readiness map[string]bool)
+
 // VFUnion represents a union of fec.VF and vrbv1.VF
 type VFUnion interface{}
 
 func newTelemetryGatherer() *telemetryGatherer {
-	t := &telemetryGatherer{}
+	t := &telemetryGatherer{vfReadiness: map[string]bool{}}
 	t.codeBlocksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "code_blocks_per_vfs",
 		Help: `number of code blocks processed by VF. 'pci_address' - represents unique BDF for VF. 'queue_type' - represents queue type for Vfs. Available values: '5GDL', '5GUL', 'FFT'`,
@@ -68,6 +81,11 @@ func newTelemetryGatherer() *telemetryGatherer {
 		Name: "vf_count",
 		Help: `describes number of configured VFs on card.'pci_address' - represents unique BDF for PF.'status' - represents current status of SriovFecNodeConfig. Available values: 'InProgress', 'Succeeded', 'Failed', 'Ignored'`,
 	}, []string{pciAddressLabel, statusLabel})
+
+	t.vfAllocatedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vf_allocated_count",
+		Help: `describes number of VFs of the card allocated to containers as reported by PodResources API of kubelet.'pci_address' - represents unique BDF for PF.`,
+	}, []string{pciAddressLabel})
 	return t
 }
 
@@ -83,6 +101,7 @@ func (t *telemetryGatherer) resetMetrics() {
 	t.bytesGauge.Reset()
 	t.codeBlocksGauge.Reset()
 	t.engineGauge.Reset()
+	t.vfAllocatedGauge.Reset()
 }
 
 func (t *telemetryGatherer) updateMetrics() {
@@ -94,13 +113,30 @@ func (t *telemetryGatherer) updateMetrics() {
 }
 
 func (t *telemetryGatherer) updateVfStatus(pciAddr, status string, value float64) {
+	t.vfReadiness[pciAddr] = value == 1
 	t.queueMetric(t.vfStatusGauge, map[string]string{pciAddressLabel: pciAddr, statusLabel: status}, value)
 }
 
+func (t *telemetryGatherer) takeVfReadiness() map[string]bool {
+	readiness := t.vfReadiness
+	t.vfReadiness = map[string]bool{}
+	return readiness
+}
+
+func (t *telemetryGatherer) notifyVfReadiness(readiness map[string]bool) {
+	if t.onVfReadiness != nil && len(readiness) > 0 {
+		t.onVfReadiness(readiness)
+	}
+}
+
 func (t *telemetryGatherer) updateVfCount(pciAddr, status string, value float64) {
 	t.queueMetric(t.vfCountGauge, map[string]string{pciAddressLabel: pciAddr, statusLabel: status}, value)
 }
 
+func (t *telemetryGatherer) updateVfAllocatedCount(pciAddr string, value float64) {
+	t.queueMetric(t.vfAllocatedGauge, map[string]string{pciAddressLabel: pciAddr}, value)
+}
+
 func (t *telemetryGatherer) updateCodeBlocks(opType, pciAddr string, value float64) {
 	t.queueMetric(t.codeBlocksGauge, map[string]string{queueTypeLabel: opType, pciAddressLabel: pciAddr}, value)
 }
@@ -114,12 +150,13 @@ func (t *telemetryGatherer) updateEngines(opType, engineID, pciAddr string, valu
 }
 
 func (t *telemetryGatherer) getGauges() []*prometheus.GaugeVec {
-	return []*prometheus.GaugeVec{t.codeBlocksGauge, t.bytesGauge, t.engineGauge, t.vfStatusGauge, t.vfCountGauge}
+	return []*prometheus.GaugeVec{t.codeBlocksGauge, t.bytesGauge, t.engineGauge, t.vfStatusGauge, t.vfCountGauge, t.vfAllocatedGauge}
 }
 
-func StartTelemetryDaemon(mgr manager.Manager, nodeName string, ns string, directClient client.Client, log *logrus.Logger) {
+func StartTelemetryDaemon(mgr manager.Manager, nodeName string, ns string, directClient client.Client, log *logrus.Logger, onVfReadiness VfReadinessListener) {
 	reg := prometheus.NewRegistry()
 	telemetryGatherer := newTelemetryGatherer()
+	telemetryGatherer.onVfReadiness = onVfReadiness
 	for _, collector := range telemetryGatherer.getGauges() {
 		reg.MustRegister(collector)
 	}
@@ -136,6 +173,15 @@ func StartTelemetryDaemon(mgr manager.Manager, nodeName string, ns string, direc
 }
 
 func getFecMetrics(log *logrus.Logger, telemetryGatherer *telemetryGatherer, fecNodeConfig *fec.SriovFecNodeConfig) {
+	for _, acc := range fecNodeConfig.Status.Inventory.SriovAccelerators {
+		allocated := 0
+		for _, vf := range acc.VFs {
+			if vf.Consumer != nil {
+				allocated++
+			}
+		}
+		telemetryGatherer.updateVfAllocatedCount(acc.PCIAddress, float64(allocated))
+	}
 
 	if len(fecNodeConfig.Status.Conditions) > 0 && fecNodeConfig.Status.Conditions[0].Reason == string(fec.SucceededSync) {
 		for _, acc := range fecNodeConfig.Status.Inventory.SriovAccelerators {
@@ -157,6 +203,15 @@ func getFecMetrics(log *logrus.Logger, telemetryGatherer *telemetryGatherer, fec
 }
 
 func getVrbMetrics(log *logrus.Logger, telemetryGatherer *telemetryGatherer, vrbNodeConfig *vrbv1.SriovVrbNodeConfig) {
+	for _, acc := range vrbNodeConfig.Status.Inventory.SriovAccelerators {
+		allocated := 0
+		for _, vf := range acc.VFs {
+			if vf.Consumer != nil {
+				allocated++
+			}
+		}
+		telemetryGatherer.updateVfAllocatedCount(acc.PCIAddress, float64(allocated))
+	}
 
 	if len(vrbNodeConfig.Status.Conditions) > 0 && vrbNodeConfig.Status.Conditions[0].Reason == string(vrbv1.SucceededSync) {
 		for _, acc := range vrbNodeConfig.Status.Inventory.SriovAccelerators {
@@ -188,7 +243,7 @@ func getMetrics(nodeName, namespace string, c client.Client, log *logrus.Logger,
 		return
 	}
 
-	utils.NewLogger().Info("metrics update loop will run every ", sleepDuration)
+	log.Info("metrics update loop will run every ", sleepDuration)
 	wait.Forever(func() {
 		fecNodeConfig := &fec.SriovFecNodeConfig{}
 		vrbNodeConfig := &vrbv1.SriovVrbNodeConfig{}
@@ -204,16 +259,78 @@ func getMetrics(nodeName, namespace string, c client.Client, log *logrus.Logger,
 
 		if fecNodeConfigErr == nil && len(fecNodeConfig.Spec.PhysicalFunctions) != 0 {
 			getFecMetrics(log, telemetryGatherer, fecNodeConfig)
+			readiness := telemetryGatherer.takeVfReadiness()
+			telemetryGatherer.notifyVfReadiness(readiness)
+			publishVfStatus(c, fecNodeConfig, &fecNodeConfig.Status.Conditions, readiness, log)
 		}
 
 		if vrbNodeConfigErr == nil && len(vrbNodeConfig.Spec.PhysicalFunctions) != 0 {
 			getVrbMetrics(log, telemetryGatherer, vrbNodeConfig)
+			readiness := telemetryGatherer.takeVfReadiness()
+			telemetryGatherer.notifyVfReadiness(readiness)
+			publishVfStatus(c, vrbNodeConfig, &vrbNodeConfig.Status.Conditions, readiness, log)
 		}
 
 		telemetryGatherer.updateMetrics()
 	}, sleepDuration)
 }
 
+/******************************************************************************
+ * Function: vfStatusCondition
+ * Description: Builds VfStatusReady condition out of VF readiness reported by
+ *              vf_status telemetry
+ *****************************************************************************/
+func vfStatusCondition(generation int64, readiness map[string]bool) metav1.Condition {
+	var notReady []string
+	for pciAddr, ready := range readiness {
+		if !ready {
+			notReady = append(notReady, pciAddr)
+		}
+	}
+	sort.Strings(notReady)
+
+	condition := metav1.Condition{
+		Type:               utils.VfStatusReadyCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             vfsReadyReason,
+		Message:            fmt.Sprintf("all %d VFs are configured or active", len(readiness)),
+		ObservedGeneration: generation,
+	}
+	if len(notReady) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = vfsNotReadyReason
+		condition.Message = fmt.Sprintf("VFs not ready: %s", strings.Join(notReady, ", "))
+	}
+	return condition
+}
+
+/******************************************************************************
+ * Function: publishVfStatus
+ * Description: Publishes VfStatusReady condition in status of the node config
+ *              when telemetry was gathered and the condition changed
+ *****************************************************************************/
+func publishVfStatus(c client.Client, nc client.Object, conditions *[]metav1.Condition, readiness map[string]bool, log *logrus.Logger) {
+	if len(readiness) == 0 {
+		return
+	}
+
+	// telemetry describes the configuration which was applied last, not necessarily the requested one
+	configured := meta.FindStatusCondition(*conditions, ConditionConfigured)
+	if configured == nil {
+		return
+	}
+	condition := vfStatusCondition(configured.ObservedGeneration, readiness)
+	if previous := meta.FindStatusCondition(*conditions, condition.Type); previous != nil &&
+		previous.Status == condition.Status && previous.Message == condition.Message && previous.ObservedGeneration == condition.ObservedGeneration {
+		return
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+	if err := c.Status().Update(context.Background(), nc); err != nil {
+		log.WithError(err).WithField("nodeConfig", nc.GetName()).Info("failed to publish VF status, will retry in next telemetry loop")
+	}
+}
+
 func getTelemetry(pciAddr string, vfs []fec.VF, telemetryGatherer *telemetryGatherer, log *logrus.Logger) {
 	err := clearLog(pciAddr)
 	if err != nil {
@@ -4,6 +4,7 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -16,6 +17,10 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const fileLog = `
@@ -626,6 +631,87 @@ Fri Sep 16 10:42:33 2022:INFO:5GUL counters: Per Engine`
 	})
 })
 
+var _ = Describe("VF status readiness", func() {
+	vfs := []v2.VF{{PCIAddress: "0000:af:00.1"}, {PCIAddress: "0000:af:00.2"}}
+
+	It("should record readiness of VFs reported by vf_status", func() {
+		tg := newTelemetryGatherer()
+		parseVFStatus([]string{"-  VF 0 RTE_BBDEV_DEV_ACTIVE", "-  VF 1 RTE_BBDEV_DEV_FATAL_ERR"}, 2, []VFUnion{vfs[0], vfs[1]}, tg, utils.NewLogger())
+
+		Expect(tg.takeVfReadiness()).To(Equal(map[string]bool{"0000:af:00.1": true, "0000:af:00.2": false}))
+		Expect(tg.takeVfReadiness()).To(BeEmpty())
+	})
+
+	Context("publishVfStatus", func() {
+		var (
+			nodeConfig *v2.SriovFecNodeConfig
+			fakeClient client.Client
+		)
+
+		BeforeEach(func() {
+			nodeConfig = &v2.SriovFecNodeConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default", Generation: 2},
+				Status: v2.SriovFecNodeConfigStatus{Conditions: []metav1.Condition{
+					{Type: ConditionConfigured, Status: metav1.ConditionTrue, Reason: string(v2.SucceededSync), ObservedGeneration: 2},
+				}},
+			}
+			scheme := runtime.NewScheme()
+			Expect(v2.AddToScheme(scheme)).To(Succeed())
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodeConfig).Build()
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(nodeConfig), nodeConfig)).To(Succeed())
+		})
+
+		published := func() *metav1.Condition {
+			res := new(v2.SriovFecNodeConfig)
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(nodeConfig), res)).To(Succeed())
+			return res.FindCondition(utils.VfStatusReadyCondition)
+		}
+
+		It("should publish VFs which are not ready", func() {
+			publishVfStatus(fakeClient, nodeConfig, &nodeConfig.Status.Conditions,
+				map[string]bool{"0000:af:00.1": true, "0000:af:00.2": false}, utils.NewLogger())
+
+			condition := published()
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("VFs not ready: 0000:af:00.2"))
+			Expect(condition.ObservedGeneration).To(BeEquivalentTo(2))
+		})
+
+		It("should publish readiness of all VFs", func() {
+			publishVfStatus(fakeClient, nodeConfig, &nodeConfig.Status.Conditions,
+				map[string]bool{"0000:af:00.1": true, "0000:af:00.2": true}, utils.NewLogger())
+
+			condition := published()
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(vfsReadyReason))
+		})
+
+		It("should not publish anything when no telemetry was gathered", func() {
+			publishVfStatus(fakeClient, nodeConfig, &nodeConfig.Status.Conditions, map[string]bool{}, utils.NewLogger())
+			Expect(published()).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("VF allocation", func() {
+	It("should count VFs allocated to containers per PF", func() {
+		consumer := &v2.VFConsumer{Namespace: "ran", Pod: "du-0", Container: "l1", ResourceName: "intel.com/intel_fec_acc100"}
+		nodeConfig := &v2.SriovFecNodeConfig{Status: v2.SriovFecNodeConfigStatus{Inventory: v2.NodeInventory{SriovAccelerators: []v2.SriovAccelerator{
+			{PCIAddress: "0000:af:00.0", VFs: []v2.VF{{PCIAddress: "0000:b0:00.0", Consumer: consumer}, {PCIAddress: "0000:b0:00.1"}}},
+			{PCIAddress: "0000:bf:00.0", VFs: []v2.VF{{PCIAddress: "0000:c0:00.0"}}},
+		}}}}
+
+		tg := newTelemetryGatherer()
+		getFecMetrics(utils.NewLogger(), tg, nodeConfig)
+		tg.updateMetrics()
+
+		Expect(testutil.ToFloat64(tg.vfAllocatedGauge.WithLabelValues("0000:af:00.0"))).To(Equal(float64(1)))
+		Expect(testutil.ToFloat64(tg.vfAllocatedGauge.WithLabelValues("0000:bf:00.0"))).To(Equal(float64(0)))
+	})
+})
+
 type testHook struct {
 	expectedError        string
 	expectedErrorOccured bool
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	sriovfecv2 "github.com/intel/sriov-fec-operator/api/sriovfec/v2"
+	controllers "github.com/intel/sriov-fec-operator/controllers/sriovfec"
+	"github.com/intel/sriov-fec-operator/pkg/common/assets"
+	"github.com/intel/sriov-fec-operator/pkg/common/utils"
+)
+
+const renderCommand = "render"
+
+// templateVarsFlag collects KEY=VALUE template variables given by repeated flag
+type templateVarsFlag map[string]string
+
+func (f templateVarsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for key, value := range f {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f templateVarsFlag) Set(pair string) error {
+	kv := strings.SplitN(pair, "=", 2)
+	if len(kv) != 2 || !strings.HasPrefix(kv[0], utils.SriovPrefix) {
+		return fmt.Errorf("expected %sKEY=VALUE, got %q", utils.SriovPrefix, pair)
+	}
+	f[kv[0]] = kv[1]
+	return nil
+}
+
+/*****************************************************************************
+ * Function: renderManifests
+ * Description: Renders assets of the daemon, labeler and device plugin into
+ * 		plain manifests the way the operator deploys them, without
+ * 		contacting the API server
+ ****************************************************************************/
+func renderManifests(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet(renderCommand, flag.ContinueOnError)
+	assetsDir := fs.String("assets-dir", "assets", "Directory with templates of the assets")
+	outputDir := fs.String("output-dir", "", "Directory to write manifests of each asset to; manifests are written to stdout when empty")
+	kernel := fs.String("kernel", "", "Kernel version of accelerator nodes")
+	operatorConfigPath := fs.String("operator-config", "", "File with SriovFecOperatorConfig to render the assets according to")
+	vars := templateVarsFlag{}
+	fs.Var(vars, "set", "Template variable "+utils.SriovPrefix+"KEY=VALUE; takes precedence over environment variables, can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	operatorConfig := &sriovfecv2.SriovFecOperatorConfig{}
+	if *operatorConfigPath != "" {
+		raw, err := os.ReadFile(*operatorConfigPath)
+		if err != nil {
+			return err
+		}
+		if err := yaml.UnmarshalStrict(raw, operatorConfig); err != nil {
+			return fmt.Errorf("invalid SriovFecOperatorConfig %s: %w", *operatorConfigPath, err)
+		}
+	}
+
+	m := &assets.Manager{
+		Log:       setupLog,
+		EnvPrefix: utils.SriovPrefix,
+	}
+	// the daemon asset is mounted into the operator pod, other deployments may not have it
+	for _, asset := range operatorAssets(*assetsDir) {
+		if _, err := os.Stat(asset.Path); os.IsNotExist(err) {
+			setupLog.WithField("path", asset.Path).Warn("asset does not exist, skipping")
+			continue
+		}
+		m.Assets = append(m.Assets, asset)
+	}
+	if len(m.Assets) == 0 {
+		return fmt.Errorf("no assets found in %s", *assetsDir)
+	}
+
+	if err := controllers.ConfigureAssets(m, operatorConfig); err != nil {
+		return fmt.Errorf("invalid SriovFecOperatorConfig: %w", err)
+	}
+	for key, value := range vars {
+		m.Overrides[key] = value
+	}
+
+	rendered, err := m.Render(*kernel)
+	if err != nil {
+		return err
+	}
+
+	for idx, objects := range rendered {
+		manifests := &bytes.Buffer{}
+		for _, obj := range objects {
+			raw, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			manifests.WriteString("---\n")
+			manifests.Write(raw)
+		}
+
+		if *outputDir == "" {
+			if _, err := manifests.WriteTo(stdout); err != nil {
+				return err
+			}
+			continue
+		}
+		path := filepath.Join(*outputDir, filepath.Base(m.Assets[idx].Path))
+		if err := os.WriteFile(path, manifests.Bytes(), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
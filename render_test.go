@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020-2025 Intel Corporation
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const fakeDaemonAsset = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: daemon-config
+  namespace: {{ .SRIOV_FEC_NAMESPACE }}
+data:
+  daemonSet: |
+    apiVersion: apps/v1
+    kind: DaemonSet
+    metadata:
+      name: sriov-fec-daemonset
+      namespace: {{ .SRIOV_FEC_NAMESPACE }}
+    spec:
+      template:
+        spec:
+          containers:
+          - name: sriov-fec-daemon
+            image: daemon:{{ .kernel }}
+`
+
+var _ = Describe("renderManifests", func() {
+	var (
+		assetsDir string
+		args      []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		assetsDir, err = os.MkdirTemp("", "render")
+		Expect(err).ToNot(HaveOccurred())
+		for _, name := range []string{"100-labeler.yaml", "200-device-plugin.yaml"} {
+			raw, err := os.ReadFile(filepath.Join("assets", name))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(assetsDir, name), raw, 0600)).To(Succeed())
+		}
+		Expect(os.WriteFile(filepath.Join(assetsDir, "300-daemon.yaml"), []byte(fakeDaemonAsset), 0600)).To(Succeed())
+
+		args = []string{
+			"--assets-dir", assetsDir,
+			"--kernel", "5.14.0-70",
+			"--set", "SRIOV_FEC_NAMESPACE=vran-acceleration-operators",
+			"--set", "SRIOV_FEC_GENERIC_K8S=false",
+			"--set", "SRIOV_FEC_LABELER_IMAGE=labeler:latest",
+			"--set", "SRIOV_FEC_NETWORK_DEVICE_PLUGIN_IMAGE=device-plugin:latest",
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(assetsDir)).To(Succeed())
+	})
+
+	It("should render manifests of all assets", func() {
+		out := &bytes.Buffer{}
+		Expect(renderManifests(args, out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("name: accelerator-discovery"))
+		Expect(out.String()).To(ContainSubstring("name: sriov-device-plugin"))
+		Expect(out.String()).To(ContainSubstring("image: daemon:5.14.0-70"))
+	})
+
+	It("should write manifests of each asset to the output directory", func() {
+		outputDir := filepath.Join(assetsDir, "out")
+		Expect(os.Mkdir(outputDir, 0700)).To(Succeed())
+
+		Expect(renderManifests(append(args, "--output-dir", outputDir), &bytes.Buffer{})).To(Succeed())
+		for _, name := range []string{"100-labeler.yaml", "200-device-plugin.yaml", "300-daemon.yaml"} {
+			Expect(filepath.Join(outputDir, name)).To(BeAnExistingFile())
+		}
+	})
+
+	It("should reject daemon node grouping", func() {
+		operatorConfig := filepath.Join(assetsDir, "operator-config.yaml")
+		Expect(os.WriteFile(operatorConfig, []byte("spec:\n  daemonNodeGrouping:\n    kernelVersion: true\n"), 0600)).To(Succeed())
+
+		err := renderManifests(append(args, "--operator-config", operatorConfig), &bytes.Buffer{})
+		Expect(err).To(MatchError(ContainSubstring(`node grouping "kernel" requires nodes of the cluster`)))
+	})
+})
+
+func TestRender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Render suite")
+}